@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
-	"strconv"
+	"sort"
 	"strings"
 
 	"go/ast"
@@ -49,6 +49,14 @@ func (cu *Checkup) Printf(msg string, a ...interface{}) (int, error) {
 }
 
 func (cu *Checkup) Run() error {
+	idStrategy := cu.options.IdStrategyFlag
+	if idStrategy == "" {
+		idStrategy = common.SOURCE_ID_STRATEGY
+	}
+	if err := common.CheckIDStrategy(idStrategy); err != nil {
+		return err
+	}
+
 	//FIND PROBLEMS HERE AND RETURN AN ERROR
 	sourceStrings, err := cu.findSourceStrings()
 
@@ -73,6 +81,9 @@ func (cu *Checkup) Run() error {
 	}
 
 	err = cu.diffStrings("the code", "en_US", sourceStrings, englishStrings)
+	if idErr := cu.checkIDs(idStrategy, sourceStrings, englishStrings); idErr != nil {
+		err = idErr
+	}
 
 	for locale, i18nFiles := range locales {
 		if locale == "en_US" {
@@ -116,25 +127,33 @@ func getGoFiles(dir string) (files []string) {
 
 func (cu *Checkup) inspectFile(file string) (translatedStrings []string, err error) {
 	fset := token.NewFileSet()
-	astFile, err := parser.ParseFile(fset, file, nil, parser.AllErrors)
+	astFile, err := parser.ParseFile(fset, file, nil, parser.ParseComments|parser.AllErrors)
 	if err != nil {
 		cu.Println(err)
 		return
 	}
 
+	constFolder := common.NewConstFolder(nil)
+	directives := common.ParseDirectives(fset, astFile)
+	if directives.IgnoreFile {
+		cu.Println("i18n4go: ignoring file with an ignore-file directive:", file)
+		return
+	}
+
 	ast.Inspect(astFile, func(n ast.Node) bool {
 		switch x := n.(type) {
 		case *ast.CallExpr:
+			if translatedString, ok := cu.contextString(x, constFolder); ok && !directives.Ignores(x.Args[1]) {
+				translatedStrings = append(translatedStrings, translatedString)
+				return true
+			}
+
 			switch x.Fun.(type) {
 			case *ast.Ident:
 				funName := x.Fun.(*ast.Ident).Name
 
 				if funName == "T" || funName == "t" {
-					if stringArg, ok := x.Args[0].(*ast.BasicLit); ok {
-						translatedString, err := strconv.Unquote(stringArg.Value)
-						if err != nil {
-							panic(err.Error())
-						}
+					if translatedString, ok := constFolder.Fold(x.Args[0]); ok && !directives.Ignores(x.Args[0]) {
 						translatedStrings = append(translatedStrings, translatedString)
 					}
 				}
@@ -143,11 +162,7 @@ func (cu *Checkup) inspectFile(file string) (translatedStrings []string, err err
 				if ident, ok := expr.X.(*ast.Ident); ok {
 					funName := expr.Sel.Name
 					if ident.Name == cu.options.QualifierFlag && (funName == "T" || funName == "t") {
-						if stringArg, ok := x.Args[0].(*ast.BasicLit); ok {
-							translatedString, err := strconv.Unquote(stringArg.Value)
-							if err != nil {
-								panic(err.Error())
-							}
+						if translatedString, ok := constFolder.Fold(x.Args[0]); ok && !directives.Ignores(x.Args[0]) {
 							translatedStrings = append(translatedStrings, translatedString)
 						}
 					}
@@ -162,6 +177,30 @@ func (cu *Checkup) inspectFile(file string) (translatedStrings []string, err err
 	return
 }
 
+// contextString returns the message key of TC(context, message) or qualifier.TC(context, message)
+func (cu *Checkup) contextString(call *ast.CallExpr, constFolder *common.ConstFolder) (string, bool) {
+	if !common.IsContextCall(call) {
+		return "", false
+	}
+	if expr, ok := call.Fun.(*ast.SelectorExpr); ok {
+		if ident, ok := expr.X.(*ast.Ident); !ok || ident.Name != cu.options.QualifierFlag {
+			return "", false
+		}
+	}
+
+	context, ok := constFolder.Fold(call.Args[0])
+	if !ok {
+		return "", false
+	}
+
+	translatedString, ok := constFolder.Fold(call.Args[1])
+	if !ok {
+		return "", false
+	}
+
+	return common.MessageKey(context, translatedString), true
+}
+
 func (cu *Checkup) findSourceStrings() (sourceStrings map[string]string, err error) {
 	sourceStrings = make(map[string]string)
 	files := getGoFiles(".")
@@ -250,7 +289,7 @@ func (cu *Checkup) findI18nStrings(i18nFiles []string) (i18nStrings map[string]s
 		}
 
 		for _, info := range stringInfos {
-			i18nStrings[info.ID] = info.Translation
+			i18nStrings[info.Key()] = info.Translation
 		}
 	}
 
@@ -260,17 +299,49 @@ func (cu *Checkup) findI18nStrings(i18nFiles []string) (i18nStrings map[string]s
 func (cu *Checkup) diffStrings(sourceNameOne, sourceNameTwo string, stringsOne, stringsTwo map[string]string) (err error) {
 	for key, _ := range stringsOne {
 		if stringsTwo[key] == "" {
-			cu.Printf("\"%s\" exists in %s, but not in %s\n", key, sourceNameOne, sourceNameTwo)
+			cu.Printf("%s exists in %s, but not in %s\n", common.QuoteMessageKey(key), sourceNameOne, sourceNameTwo)
 			err = errors.New("Strings don't match")
 		}
 	}
 
 	for key, _ := range stringsTwo {
 		if stringsOne[key] == "" {
-			cu.Printf("\"%s\" exists in %s, but not in %s\n", key, sourceNameTwo, sourceNameOne)
+			cu.Printf("%s exists in %s, but not in %s\n", common.QuoteMessageKey(key), sourceNameTwo, sourceNameOne)
 			err = errors.New("Strings don't match")
 		}
 	}
 
 	return
 }
+
+// checkIDs reports the IDs of the code and of the en_US strings that do not follow the ID strategy, an ID is kept when
+// the source text of its en_US string changes
+func (cu *Checkup) checkIDs(idStrategy string, sourceStrings, englishStrings map[string]string) (err error) {
+	if idStrategy == common.SOURCE_ID_STRATEGY {
+		return
+	}
+
+	for _, source := range []struct {
+		name    string
+		strings map[string]string
+	}{{"the code", sourceStrings}, {"en_US", englishStrings}} {
+		for _, key := range sortedKeys(source.strings) {
+			if _, id := common.SplitMessageKey(key); !common.MatchesIDStrategy(idStrategy, id) {
+				cu.Printf("%s in %s is not a %s ID\n", common.QuoteMessageKey(key), source.name, idStrategy)
+				err = errors.New("IDs don't match the ID strategy")
+			}
+		}
+	}
+
+	return
+}
+
+func sortedKeys(stringsMap map[string]string) []string {
+	keys := make([]string, 0, len(stringsMap))
+	for key := range stringsMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -132,7 +132,7 @@ func (ct *createTranslations) createTranslationFileWithGoogleTranslate(language
 		if err != nil {
 			ct.Println("i18n4go: error invoking Google Translate for string:", i18nStringInfo.Translation)
 		} else {
-			modifiedI18nStringInfos[i] = common.I18nStringInfo{ID: i18nStringInfo.ID, Translation: translation}
+			modifiedI18nStringInfos[i] = common.I18nStringInfo{ID: i18nStringInfo.ID, Context: i18nStringInfo.Context, Translation: translation, Description: i18nStringInfo.Description}
 		}
 	}
 
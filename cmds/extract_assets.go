@@ -0,0 +1,71 @@
+package cmds
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"go/token"
+
+	"github.com/Liam-Williams/i18n4go/common"
+)
+
+// findAssetFiles lists the JSON, YAML, text and Markdown files in dirName selected by an assets rule
+func (es *extractStrings) findAssetFiles(dirName string, rules *common.Rules) ([]string, error) {
+	if len(rules.Assets) == 0 {
+		return nil, nil
+	}
+
+	fileInfos, err := ioutil.ReadDir(dirName)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileNames []string
+	for _, fileInfo := range fileInfos {
+		fileName := filepath.Join(dirName, fileInfo.Name())
+		if fileInfo.IsDir() || strings.HasPrefix(fileInfo.Name(), ".") || strings.HasSuffix(fileName, ".go") || rules.Asset(fileName) == nil {
+			continue
+		}
+
+		if es.IgnoreRegexp != nil && es.IgnoreRegexp.MatchString(fileName) {
+			es.Println("Using ignore-regexp:", es.options.IgnoreRegexpFlag)
+			continue
+		}
+
+		if rules.ExcludesFile(fileName) {
+			es.Println("Using excludedFileRegexps of:", strings.Join(rules.Filenames, ", "))
+			continue
+		}
+
+		fileNames = append(fileNames, fileName)
+	}
+
+	return fileNames, nil
+}
+
+// extractAssetFile extracts the strings of a JSON or YAML file selected by the paths of its assets rule, or the text
+// or the paragraphs of a text or Markdown file, a file without assets rule is extracted whole
+func (es *extractStrings) extractAssetFile(ef *extractedFile, selector *common.AssetSelector) error {
+	content, err := ioutil.ReadFile(ef.AbsFilename)
+	if err != nil {
+		return err
+	}
+	ef.lines = strings.Split(string(content), "\n")
+
+	if selector == nil {
+		selector = &common.AssetSelector{}
+	}
+
+	assetStrings, err := common.AssetStrings(ef.AbsFilename, content, *selector)
+	if err != nil {
+		return err
+	}
+
+	for _, assetString := range assetStrings {
+		position := token.Position{Filename: ef.AbsFilename, Offset: assetString.Offset, Line: assetString.Line, Column: assetString.Column}
+		es.processString(ef, assetString.Value, "", position, "", "")
+	}
+
+	return nil
+}
@@ -1,54 +1,101 @@
 package cmds
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"regexp"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 
 	"go/ast"
-	"go/build"
 	"go/parser"
 	"go/token"
 
 	"path/filepath"
 
-	"bufio"
 	"encoding/json"
 	"errors"
-	"io"
 	"io/ioutil"
 
 	"github.com/Liam-Williams/i18n4go/common"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
 )
 
 type extractStrings struct {
 	options common.Options
 
-	i18nFilename string
-	poFilename   string
-
-	Filename      string
 	OutputDirname string
 
-	ExtractedStrings    map[string]common.StringInfo
-	FilteredStrings     map[string]string
-	FilteredRegexps     []*regexp.Regexp
-	FilteredLines       []string
-	FilteredFileRegexps *regexp.Regexp
-	EnforcedFuncs       []string
+	rulesLoader *common.RulesLoader
+
+	packageLoader *common.PackageLoader
+	typedPackages map[string]*common.TypedPackage
+	sinkFinders   map[string]*common.SinkFinder
+
+	MaxWorkers int
+
+	cache          *common.Cache
+	packageDigests map[string]string
 
-	SubstringRegexpsFile string
-	SubstringRegexps     []*regexp.Regexp
+	TotalStrings int
+	TotalFiles   int
 
-	TotalStringsDir int
-	TotalStrings    int
-	TotalFiles      int
+	// the decisions taken for every string literal, in file order, when --explain is set
+	Classifications []common.Classification
 
 	IgnoreRegexp *regexp.Regexp
 }
 
+// extractedFile holds the strings of one source file, files are extracted concurrently
+// and only read the settings of extractStrings so the results can be saved in any order
+type extractedFile struct {
+	Filename    string
+	AbsFilename string
+
+	ExtractedStrings map[string]common.StringInfo
+
+	rules         *common.Rules
+	importStrings map[string]bool
+	sinkFinder    *common.SinkFinder
+	funcName      string
+	handled       map[ast.Node]bool
+	constFolder   *common.ConstFolder
+	descriptions  map[int]string
+	directives    *common.Directives
+	contexts      []messageContext
+	lines         []string
+	imports       common.Imports
+	// the name of the package of the file, the directory name for templates
+	pkgName      string
+	typedPackage *common.TypedPackage
+	// the types of the composite literals whose type is elided, e.g., the elements of []cli.Command{{...}}
+	elidedTypes map[*ast.CompositeLit]ast.Expr
+
+	explain         bool
+	classifications []common.Classification
+
+	// the file is skipped, e.g., a hidden file
+	ignored bool
+	// the verbose messages of the file, they are printed in file order once the workers are done
+	messages []string
+}
+
+// cachedFile is the cache entry of an extracted file, the package name is cached since the IDs of the strings depend on it
+type cachedFile struct {
+	PkgName          string                       `json:"pkgName"`
+	ExtractedStrings map[string]common.StringInfo `json:"extractedStrings"`
+}
+
+// messageContext is the context of the message of a TC(context, message) call, pos and end delimit the message
+type messageContext struct {
+	pos, end token.Pos
+	context  string
+}
+
 func NewExtractStrings(options common.Options) extractStrings {
 	var compiledRegexp *regexp.Regexp
 	if options.IgnoreRegexpFlag != "" {
@@ -59,17 +106,21 @@ func NewExtractStrings(options common.Options) extractStrings {
 		compiledRegexp = compiledReg
 	}
 
+	maxWorkers := options.JobsFlag
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.GOMAXPROCS(0)
+	}
+
 	return extractStrings{options: options,
-		Filename:         "extracted_strings.json",
-		OutputDirname:    options.OutputDirFlag,
-		ExtractedStrings: make(map[string]common.StringInfo),
-		FilteredStrings:  make(map[string]string),
-		FilteredRegexps:  []*regexp.Regexp{},
-		SubstringRegexps: nil,
-		TotalStringsDir:  0,
-		TotalStrings:     0,
-		TotalFiles:       0,
-		IgnoreRegexp:     compiledRegexp,
+		OutputDirname:  options.OutputDirFlag,
+		rulesLoader:    common.NewRulesLoader(),
+		typedPackages:  make(map[string]*common.TypedPackage),
+		sinkFinders:    make(map[string]*common.SinkFinder),
+		MaxWorkers:     maxWorkers,
+		packageDigests: make(map[string]string),
+		TotalStrings:   0,
+		TotalFiles:     0,
+		IgnoreRegexp:   compiledRegexp,
 	}
 }
 
@@ -94,32 +145,40 @@ func (es *extractStrings) Printf(msg string, a ...interface{}) (int, error) {
 }
 
 func (es *extractStrings) Run() error {
-	err := es.loadExcludedStrings()
+	if err := common.CheckIDStrategy(es.options.IdStrategyFlag); err != nil {
+		fmt.Println(err)
+		return err
+	}
+
+	if es.options.ExplainFlag && es.options.ExplainFormatFlag != common.TEXT_EXPLAIN_FORMAT && es.options.ExplainFormatFlag != common.JSON_EXPLAIN_FORMAT {
+		err := fmt.Errorf("i18n4go: unknown explain format %q, expected %s or %s", es.options.ExplainFormatFlag, common.TEXT_EXPLAIN_FORMAT, common.JSON_EXPLAIN_FORMAT)
+		fmt.Println(err)
+		return err
+	}
+
+	err := es.loadRules()
 	if err != nil {
-		es.Println(err)
+		fmt.Println(err)
 		return err
 	}
-	es.Println(fmt.Sprintf("Loaded %d excluded strings", len(es.FilteredStrings)))
 
-	err = es.loadExcludedRegexps()
+	if es.options.SinksFlag {
+		es.packageLoader = common.NewPackageLoader(token.NewFileSet(), es.options.TagsFlag)
+	}
+
+	err = es.loadCache()
 	if err != nil {
 		es.Println(err)
 		return err
 	}
-	es.Println(fmt.Sprintf("Loaded %d excluded regexps", len(es.FilteredRegexps)))
-
-	if es.options.SubstringFilenameFlag != "" {
-		err := es.loadSubstringRegexps()
-		if err != nil {
-			es.Println(err)
-			return err
-		}
-		es.Println(fmt.Sprintf("Loaded %d substring regexps", len(es.FilteredRegexps)))
+	if es.options.FilenameFlag == "" && es.options.DirnameFlag == "" {
+		return nil
 	}
+
 	if es.options.FilenameFlag != "" {
-		return es.InspectFile(es.options.FilenameFlag)
+		err = es.InspectFile(es.options.FilenameFlag)
 	} else {
-		err := es.InspectDir(es.options.DirnameFlag, es.options.RecurseFlag)
+		err = es.InspectDir(es.options.DirnameFlag, es.options.RecurseFlag)
 		if err != nil {
 			es.Println("i18n4go: could not extract strings from directory:", es.options.DirnameFlag)
 			return err
@@ -128,365 +187,790 @@ func (es *extractStrings) Run() error {
 		es.Println("Total files parsed:", es.TotalFiles)
 		es.Println("Total extracted strings:", es.TotalStrings)
 	}
-	return nil
-}
 
-func (es *extractStrings) InspectFile(filename string) error {
-	es.Println("i18n4go: extracting strings from file:", filename)
-	if es.options.DryRunFlag {
-		es.Println("WARNING running in -dry-run mode")
+	if es.cache != nil {
+		hits, misses := es.cache.Stats()
+		es.Println(fmt.Sprintf("Cache hits: %d, misses: %d", hits, misses))
 	}
 
-	es.setFilename(filename)
-	es.setI18nFilename(filename)
-	es.setPoFilename(filename)
+	if es.options.ExplainFlag {
+		explainErr := common.SaveClassifications(os.Stdout, es.options.ExplainFormatFlag, es.Classifications)
+		if err == nil {
+			err = explainErr
+		}
+	}
 
-	fset := token.NewFileSet()
+	return err
+}
 
-	var absFilePath = filename
-	if !filepath.IsAbs(absFilePath) {
-		absFilePath = filepath.Join(os.Getenv("PWD"), absFilePath)
-	}
+// loadRules loads and validates the excluded JSON file, the substring capturing JSON file and the rules file,
+// the rules files of the extracted directories are added to them as the directories are found
+func (es *extractStrings) loadRules() error {
+	for _, filename := range []string{es.options.ExcludedFilenameFlag, es.options.SubstringFilenameFlag} {
+		if filename == "" {
+			continue
+		}
 
-	fileInfo, err := common.GetAbsFileInfo(absFilePath)
-	if err != nil {
-		es.Println(err)
+		_, err := os.Stat(filename)
+		if os.IsNotExist(err) {
+			es.Println("Could not find:", filename)
+			continue
+		}
+
+		es.Println("Loading rules in file:", filename)
+		err = es.rulesLoader.AddLegacyBase(filename)
+		if err != nil {
+			return err
+		}
+	}
+	for _, warning := range es.rulesLoader.Warnings {
+		fmt.Println("WARNING ignoring the rule:", warning)
 	}
 
-	if strings.HasPrefix(fileInfo.Name(), ".") {
-		es.Println("WARNING ignoring file:", absFilePath)
-		return nil
+	if es.options.RulesFilenameFlag != "" {
+		es.Println("Loading rules in file:", es.options.RulesFilenameFlag)
+		err := es.rulesLoader.AddBase(es.options.RulesFilenameFlag)
+		if err != nil {
+			return err
+		}
 	}
 
-	astFile, err := parser.ParseFile(fset, absFilePath, nil, parser.ParseComments|parser.AllErrors)
+	rules, err := es.rulesLoader.Rules(".")
 	if err != nil {
-		es.Println(err)
 		return err
 	}
 
-	es.excludeImports(astFile)
-
-	es.extractString(astFile, fset)
-	es.TotalStringsDir += len(es.ExtractedStrings)
-	es.TotalStrings += len(es.ExtractedStrings)
-	es.TotalFiles += 1
+	es.Println(fmt.Sprintf("Loaded %d excluded strings", len(rules.ExcludedStrings)))
+	es.Println(fmt.Sprintf("Loaded %d excluded regexps", len(rules.ExcludedRegexps)))
+	es.Println(fmt.Sprintf("Loaded %d substring regexps", len(rules.CaptureGroupSubstrings)))
+	if es.options.SinksFlag {
+		es.Println(fmt.Sprintf("Loaded %d sinks", len(common.DEFAULT_SINKS)+len(rules.Sinks)))
+	}
 
-	es.Printf("Extracted %d strings from file: %s\n", len(es.ExtractedStrings), absFilePath)
+	return nil
+}
 
-	var outputDirname = es.OutputDirname
-	if es.options.OutputDirFlag != "" {
-		if es.options.OutputMatchImportFlag {
-			outputDirname, err = es.findImportPath(absFilePath)
-			if err != nil {
-				es.Println(err)
-				return err
-			}
-		} else if es.options.OutputMatchPackageFlag {
-			outputDirname, err = es.findPackagePath(absFilePath)
-			if err != nil {
-				es.Println(err)
-				return err
-			}
-		}
-	} else {
-		outputDirname, err = common.FindFilePath(absFilePath)
-		if err != nil {
-			es.Println(err)
+// loadDirRules validates the rules of the directories of fileNames before any file is extracted
+func (es *extractStrings) loadDirRules(fileNames []string) error {
+	var errs common.RuleErrors
+	for _, fileName := range fileNames {
+		_, err := es.rulesLoader.Rules(filepath.Dir(es.absFilename(fileName)))
+		if ruleErrors, ok := err.(common.RuleErrors); ok {
+			errs = append(errs, ruleErrors...)
+		} else if err != nil {
 			return err
 		}
 	}
 
-	if es.options.MetaFlag {
-		err = es.saveExtractedStrings(outputDirname)
-		if err != nil {
-			es.Println(err)
-			return err
-		}
+	if len(errs) > 0 {
+		return errs
 	}
 
-	err = common.SaveStrings(es, es.Options(), es.ExtractedStrings, outputDirname, es.i18nFilename)
-	if err != nil {
-		es.Println(err)
-		return err
+	return nil
+}
+
+// loadCache sets up the extraction cache, its entries depend on every setting that changes the strings extracted from a file
+func (es *extractStrings) loadCache() error {
+	// the rules are part of the key of each file since they can differ per directory
+	settings := [][]byte{
+		[]byte(strconv.FormatBool(es.options.SinksFlag)),
+		[]byte(es.options.TagsFlag),
 	}
 
-	if es.options.PoFlag {
-		err = common.SaveStringsInPo(es, es.Options(), es.ExtractedStrings, outputDirname, es.poFilename)
+	cacheDirname := es.options.CacheDirFlag
+	if cacheDirname == "" {
+		cacheDirname = common.DefaultCacheDirname("extract-strings")
+	}
+	cache := common.NewCache(cacheDirname, settings...)
+
+	if es.options.ClearCacheFlag {
+		err := cache.Clear()
 		if err != nil {
-			es.Println(err)
 			return err
 		}
+		es.Println("i18n4go: cleared the cache in:", cacheDirname)
+	}
+
+	if !es.options.NoCacheFlag && !es.options.DryRunFlag {
+		es.cache = cache
 	}
 
 	return nil
 }
 
-func (es *extractStrings) InspectDir(dirName string, recursive bool) error {
-	es.Printf("i18n4go: inspecting dir %s, recursive: %t\n", dirName, recursive)
-	es.Println()
+func (es *extractStrings) InspectFile(filename string) error {
+	return es.inspectFiles([]string{filename}, false)
+}
 
-	fset := token.NewFileSet()
-	es.TotalStringsDir = 0
+func (es *extractStrings) InspectDir(dirName string, recursive bool) error {
+	fileNames, err := es.findFiles(dirName, recursive)
+	if _, ok := err.(common.RuleErrors); ok {
+		fmt.Println(err)
+		return err
+	}
+	if err != nil {
+		es.Println(err)
+		return err
+	}
 
-	packages, err := parser.ParseDir(fset, dirName, nil, parser.ParseComments)
+	err = es.inspectFiles(fileNames, true)
 	if err != nil {
 		es.Println(err)
+	}
+
+	return nil
+}
+
+// inspectFiles extracts the files with at most MaxWorkers goroutines and then saves the results and prints their messages
+// in the order of fileNames, so the output does not depend on how the goroutines are scheduled, dirTotals prints the
+// total of strings of each directory
+func (es *extractStrings) inspectFiles(fileNames []string, dirTotals bool) error {
+	err := es.loadDirRules(fileNames)
+	if err != nil {
+		fmt.Println(err)
 		return err
 	}
 
-	for k, pkg := range packages {
-		es.Println("Extracting strings in package:", k)
-		for fileName := range pkg.Files {
-			if es.IgnoreRegexp != nil && es.IgnoreRegexp.MatchString(fileName) {
-				es.Println("Using ignore-regexp:", es.options.IgnoreRegexpFlag)
+	if es.options.SinksFlag {
+		// the type checker is not safe for concurrent use, packages are loaded up front
+		for _, fileName := range fileNames {
+			if !strings.HasSuffix(fileName, ".go") {
 				continue
-			} else {
-				es.Println("No match for ignore-regexp:", es.options.IgnoreRegexpFlag)
 			}
 
-			if es.FilteredFileRegexps != nil {
-				if es.FilteredFileRegexps.MatchString(fileName) {
-					es.Println("Using ignore-regexp:", es.options.IgnoreRegexpFlag)
-					continue
-				}
+			if es.cache != nil && !es.options.ExplainFlag && es.cache.Has(es.cacheKey(fileName)) {
+				continue
 			}
 
-			if strings.HasSuffix(fileName, ".go") {
-				err = es.InspectFile(fileName)
-				if err != nil {
-					es.Println(err)
-				}
+			_, err := es.loadSinkFinder(es.absFilename(fileName))
+			if err != nil {
+				es.Println(err)
 			}
 		}
 	}
-	es.Printf("Extracted total of %d strings\n\n", es.TotalStringsDir)
 
-	if recursive {
-		fileInfos, _ := ioutil.ReadDir(dirName)
-		for _, fileInfo := range fileInfos {
-			if fileInfo.IsDir() && !strings.HasPrefix(fileInfo.Name(), ".") {
-				err = es.InspectDir(filepath.Join(dirName, fileInfo.Name()), recursive)
-				if err != nil {
-					es.Println(err)
-				}
+	extractedFiles := make([]*extractedFile, len(fileNames))
+	errs := make([]error, len(fileNames))
+
+	var eg errgroup.Group
+	sem := semaphore.NewWeighted(int64(es.MaxWorkers))
+	for i := range fileNames {
+		if err := sem.Acquire(context.TODO(), 1); err != nil {
+			return fmt.Errorf("err acquiring semaphore: %w", err)
+		}
+
+		i := i // copy index for goroutine closure
+		eg.Go(func() error {
+			defer sem.Release(1)
+			extractedFiles[i], errs[i] = es.extractFile(fileNames[i])
+			return nil
+		})
+	}
+	eg.Wait()
+	es.assignIDs(extractedFiles)
+
+	var firstErr error
+	totalStringsDir := 0
+	for i, fileName := range fileNames {
+		es.Println("i18n4go: extracting strings from file:", fileName)
+		if es.options.DryRunFlag {
+			es.Println("WARNING running in -dry-run mode")
+		}
+
+		ef := extractedFiles[i]
+		err := errs[i]
+		if ef != nil {
+			for _, message := range ef.messages {
+				es.Printf("%s", message)
+			}
+		}
+		if err == nil && ef != nil && !ef.ignored {
+			es.Classifications = append(es.Classifications, ef.classifications...)
+			err = es.saveFile(ef)
+			totalStringsDir += len(ef.ExtractedStrings)
+		}
+		if err != nil {
+			es.Println(err)
+			if firstErr == nil {
+				firstErr = err
 			}
 		}
+
+		if dirTotals && (i == len(fileNames)-1 || filepath.Dir(fileNames[i+1]) != filepath.Dir(fileName)) {
+			es.Printf("Extracted total of %d strings\n\n", totalStringsDir)
+			totalStringsDir = 0
+		}
 	}
 
-	return nil
+	return firstErr
 }
 
-func (es *extractStrings) findImportPath(filename string) (string, error) {
-	path := es.OutputDirname
+// extractFile is called concurrently, it must not change es nor print, the messages are kept in the extracted file
+func (es *extractStrings) extractFile(filename string) (*extractedFile, error) {
+	absFilePath := es.absFilename(filename)
 
-	filePath, err := common.FindFilePath(filename)
+	fileInfo, err := common.GetAbsFileInfo(absFilePath)
 	if err != nil {
-		fmt.Println("ERROR opening file", err)
-		return "", err
+		return nil, err
 	}
 
-	pkg, err := build.ImportDir(filePath, 0)
-	srcPath := "src" + string(os.PathSeparator)
-	if strings.HasPrefix(pkg.Dir, srcPath) {
-		path = filepath.Join(path, pkg.Dir[len(srcPath):len(pkg.Dir)])
+	rules, err := es.rulesLoader.Rules(filepath.Dir(absFilePath))
+	if err != nil {
+		return nil, err
 	}
 
-	return path, nil
-}
+	ef := &extractedFile{
+		Filename:         filename,
+		AbsFilename:      absFilePath,
+		ExtractedStrings: make(map[string]common.StringInfo),
+		rules:            rules,
+		importStrings:    make(map[string]bool),
+		handled:          make(map[ast.Node]bool),
+		constFolder:      common.NewConstFolder(nil),
+		descriptions:     make(map[int]string),
+		imports:          make(common.Imports),
+		pkgName:          filepath.Base(filepath.Dir(absFilePath)),
+		elidedTypes:      make(map[*ast.CompositeLit]ast.Expr),
+		explain:          es.options.ExplainFlag,
+	}
 
-func (es *extractStrings) findPackagePath(filename string) (string, error) {
-	path := es.OutputDirname
+	if strings.HasPrefix(fileInfo.Name(), ".") {
+		ef.Println("WARNING ignoring file:", absFilePath)
+		ef.ignored = true
+		return ef, nil
+	}
 
-	filePath, err := common.FindFilePath(filename)
+	// the classifications are not cached, every file is classified again to explain it
+	var key string
+	if es.cache != nil {
+		key = es.cacheKey(filename)
+		var entry cachedFile
+		if key != "" && !ef.explain && es.cache.Load(key, &entry) {
+			ef.pkgName = entry.PkgName
+			ef.ExtractedStrings = entry.ExtractedStrings
+			return ef, nil
+		}
+	}
+
+	var selector *common.AssetSelector
+	if !strings.HasSuffix(absFilePath, ".go") {
+		selector = rules.Asset(absFilePath)
+	}
+
+	if selector != nil || (common.IsAssetFile(absFilePath) && !common.IsTemplateFile(absFilePath)) {
+		err = es.extractAssetFile(ef, selector)
+	} else if common.IsTemplateFile(absFilePath) {
+		err = es.extractTemplateFile(ef)
+	} else {
+		err = es.extractGoFile(ef)
+	}
 	if err != nil {
-		fmt.Println("ERROR opening file", err)
-		return "", err
+		return nil, err
+	}
+
+	if key != "" {
+		err = es.cache.Save(key, cachedFile{PkgName: ef.pkgName, ExtractedStrings: ef.ExtractedStrings})
+		if err != nil {
+			ef.Println("WARNING could not cache the strings of file:", absFilePath, err)
+		}
 	}
 
-	pkg, err := build.ImportDir(filePath, 0)
+	return ef, nil
+}
+
+// cacheKey returns the key of the cached strings of a file, in sinks mode the strings depend on every file of the package,
+// it is "" when the file cannot be read
+func (es *extractStrings) cacheKey(filename string) string {
+	absFilePath := es.absFilename(filename)
+	content, err := ioutil.ReadFile(absFilePath)
 	if err != nil {
-		fmt.Println("ERROR opening file", err)
-		return "", err
+		return ""
 	}
 
-	return filepath.Join(path, pkg.Name), nil
+	rules, err := es.rulesLoader.Rules(filepath.Dir(absFilePath))
+	if err != nil {
+		return ""
+	}
+
+	var packageDigest string
+	if es.options.SinksFlag && strings.HasSuffix(absFilePath, ".go") {
+		packageDigest = es.packageDigest(filepath.Dir(absFilePath))
+	}
+
+	return es.cache.Key([]byte(filename), []byte(absFilePath), content, []byte(rules.Digest), []byte(packageDigest), []byte(es.options.IdStrategyFlag))
 }
 
-func (es *extractStrings) saveExtractedStrings(outputDirname string) error {
-	if len(es.ExtractedStrings) != 0 {
-		es.Println("Saving extracted strings to file:", es.Filename)
+// packageDigest digests the go files of a directory, digests are computed while packages are loaded up front
+// and only read afterwards so it is safe to call concurrently then
+func (es *extractStrings) packageDigest(dirName string) string {
+	if digest, ok := es.packageDigests[dirName]; ok {
+		return digest
 	}
 
-	if !es.options.DryRunFlag {
-		err := common.CreateOutputDirsIfNeeded(outputDirname)
-		if err != nil {
-			es.Println(err)
-			return err
-		}
+	fileNames, _ := filepath.Glob(filepath.Join(dirName, "*.go"))
+	sort.Strings(fileNames)
+
+	var parts [][]byte
+	for _, fileName := range fileNames {
+		content, _ := ioutil.ReadFile(fileName)
+		parts = append(parts, []byte(filepath.Base(fileName)), content)
 	}
 
-	stringInfos := make([]common.StringInfo, 0)
-	for _, stringInfo := range es.ExtractedStrings {
-		stringInfo.Filename = strings.Split(es.Filename, ".extracted.json")[0]
+	digest := common.Digest(parts...)
+	es.packageDigests[dirName] = digest
+	return digest
+}
+
+func (es *extractStrings) extractGoFile(ef *extractedFile) error {
+	absFilePath := ef.AbsFilename
+
+	var err error
+	fset := token.NewFileSet()
+	var astFile *ast.File
+	if es.options.SinksFlag {
+		astFile, fset, ef.sinkFinder, err = es.typedFile(absFilePath)
+		if err == nil {
+			ef.typedPackage = es.typedPackages[filepath.Dir(absFilePath)]
+			ef.constFolder = common.NewConstFolder(ef.typedPackage.Info)
+		}
+	} else {
+		astFile, err = parser.ParseFile(fset, absFilePath, nil, parser.ParseComments|parser.AllErrors)
+	}
+	if err != nil {
+		return err
+	}
 
-		stringInfos = append(stringInfos, stringInfo)
+	src, err := ioutil.ReadFile(absFilePath)
+	if err != nil {
+		return err
+	}
+	ef.lines = strings.Split(string(src), "\n")
+	ef.descriptions = common.TranslatorComments(fset, astFile, src)
+	ef.directives = common.ParseDirectives(fset, astFile)
+	if ef.directives.IgnoreFile {
+		ef.Println("i18n4go: ignoring file with an ignore-file directive:", absFilePath)
+		return nil
 	}
 
-	jsonData, err := json.MarshalIndent(stringInfos, "", "   ")
+	es.excludeImports(ef, astFile)
+	ef.imports = common.FileImports(astFile)
+	ef.pkgName = astFile.Name.Name
+	es.extractString(ef, astFile, fset)
+
+	return nil
+}
+
+func (es *extractStrings) saveFile(ef *extractedFile) error {
+	es.TotalStrings += len(ef.ExtractedStrings)
+	es.TotalFiles += 1
+
+	es.Printf("Extracted %d strings from file: %s\n", len(ef.ExtractedStrings), ef.AbsFilename)
+
+	outputDirname, err := es.outputDirname(ef)
 	if err != nil {
-		es.Println(err)
 		return err
 	}
-	jsonData = common.UnescapeHTML(jsonData)
 
-	if !es.options.DryRunFlag && len(stringInfos) != 0 {
-		file, err := os.Create(filepath.Join(outputDirname, es.Filename[strings.LastIndex(es.Filename, string(os.PathSeparator))+1:len(es.Filename)]))
-		defer file.Close()
+	if es.options.MetaFlag {
+		err = es.saveExtractedStrings(ef, outputDirname)
 		if err != nil {
-			es.Println(err)
 			return err
 		}
+	}
 
-		file.Write(jsonData)
+	err = common.SaveStrings(es, es.Options(), ef.ExtractedStrings, outputDirname, ef.Filename+".en.json")
+	if err != nil {
+		return err
+	}
+
+	if es.options.PoFlag {
+		err = common.SaveStringsInPo(es, es.Options(), ef.ExtractedStrings, outputDirname, ef.Filename+".en.po")
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (es *extractStrings) setFilename(filename string) {
-	es.Filename = filename + ".extracted.json"
+// outputDirname is the directory the strings of a file are saved to
+func (es *extractStrings) outputDirname(ef *extractedFile) (string, error) {
+	if es.options.OutputDirFlag == "" {
+		return common.FindFilePath(ef.AbsFilename)
+	}
+
+	if es.options.OutputMatchImportFlag {
+		return es.findImportPath(ef.AbsFilename)
+	} else if es.options.OutputMatchPackageFlag {
+		return es.findPackagePath(ef.AbsFilename)
+	}
+
+	return es.OutputDirname, nil
 }
 
-func (es *extractStrings) setI18nFilename(filename string) {
-	es.i18nFilename = filename + ".en.json"
+// assignIDs gives their IDs to the extracted strings with one ID generator per package, so that the semantic IDs of
+// the files of a package do not collide, the IDs the strings had in the previous extraction of their file are kept
+func (es *extractStrings) assignIDs(extractedFiles []*extractedFile) {
+	if es.options.IdStrategyFlag == "" || es.options.IdStrategyFlag == common.SOURCE_ID_STRATEGY {
+		return
+	}
+
+	generators := make(map[string]*common.IDGenerator)
+	existingIDs := make([]map[string]string, len(extractedFiles))
+	for i, ef := range extractedFiles {
+		if ef == nil {
+			continue
+		}
+
+		packageKey := filepath.Join(filepath.Dir(ef.AbsFilename), ef.pkgName)
+		if generators[packageKey] == nil {
+			generators[packageKey] = common.NewIDGenerator(es.options.IdStrategyFlag, ef.pkgName)
+		}
+
+		existingIDs[i] = es.existingIDs(ef)
+		for key, id := range existingIDs[i] {
+			stringInfo := ef.ExtractedStrings[key]
+			generators[packageKey].Reserve(stringInfo.Context, stringInfo.Value, id)
+		}
+	}
+
+	for i, ef := range extractedFiles {
+		if ef == nil {
+			continue
+		}
+
+		packageKey := filepath.Join(filepath.Dir(ef.AbsFilename), ef.pkgName)
+		generators[packageKey].AssignIDs(ef.ExtractedStrings, existingIDs[i])
+	}
 }
 
-func (es *extractStrings) setPoFilename(filename string) {
-	es.poFilename = filename + ".en.po"
+// existingIDs returns the IDs following the ID strategy that the strings of a file had in its previous extraction, keyed
+// by message key, a string whose source text changed keeps the ID of the string found at the same place of the
+// .extracted.json file
+func (es *extractStrings) existingIDs(ef *extractedFile) map[string]string {
+	existingIDs := make(map[string]string)
+	outputDirname, err := es.outputDirname(ef)
+	if err != nil {
+		return existingIDs
+	}
+
+	takenIDs := make(map[string]bool)
+	keepID := func(key, id, value string) {
+		if _, ok := ef.ExtractedStrings[key]; !ok || existingIDs[key] != "" || takenIDs[id] {
+			return
+		}
+		if id == "" || id == value || !common.MatchesIDStrategy(es.options.IdStrategyFlag, id) {
+			return
+		}
+
+		existingIDs[key] = id
+		takenIDs[id] = true
+	}
+
+	i18nStringInfos, _ := common.LoadI18nStringInfos(filepath.Join(outputDirname, strings.Replace(ef.Filename, string(os.PathSeparator), "-", -1)+".en.json"))
+	for _, i18nStringInfo := range i18nStringInfos {
+		keepID(common.MessageKey(i18nStringInfo.Context, i18nStringInfo.Translation), i18nStringInfo.ID, i18nStringInfo.Translation)
+	}
+
+	var stringInfos []common.StringInfo
+	content, err := ioutil.ReadFile(filepath.Join(outputDirname, filepath.Base(ef.Filename)+".extracted.json"))
+	if err == nil {
+		json.Unmarshal(content, &stringInfos)
+	}
+	for _, stringInfo := range stringInfos {
+		keepID(common.MessageKey(stringInfo.Context, stringInfo.Value), stringInfo.ID, stringInfo.Value)
+	}
+
+	for _, stringInfo := range stringInfos {
+		if _, ok := ef.ExtractedStrings[common.MessageKey(stringInfo.Context, stringInfo.Value)]; ok {
+			continue
+		}
+
+		for key, extractedString := range ef.ExtractedStrings {
+			if extractedString.Line == stringInfo.Line && extractedString.Column == stringInfo.Column {
+				keepID(key, stringInfo.ID, stringInfo.Value)
+			}
+		}
+	}
+
+	return existingIDs
 }
 
-func (es *extractStrings) loadExcludedStrings() error {
-	_, err := os.Stat(es.options.ExcludedFilenameFlag)
-	if os.IsNotExist(err) {
-		es.Println("Could not find:", es.options.ExcludedFilenameFlag)
-		return nil
+func (es *extractStrings) absFilename(filename string) string {
+	if filepath.IsAbs(filename) {
+		return filename
 	}
 
-	es.Println("Excluding strings in file:", es.options.ExcludedFilenameFlag)
+	return filepath.Join(os.Getenv("PWD"), filename)
+}
+
+// findFiles lists the files to extract in dirName, and its subdirectories when recursive, in a stable order
+func (es *extractStrings) findFiles(dirName string, recursive bool) ([]string, error) {
+	es.Printf("i18n4go: inspecting dir %s, recursive: %t\n", dirName, recursive)
+	es.Println()
 
-	content, err := ioutil.ReadFile(es.options.ExcludedFilenameFlag)
+	rules, err := es.rulesLoader.Rules(dirName)
 	if err != nil {
-		fmt.Print(err)
-		return err
+		return nil, err
 	}
 
-	var excludedStrings common.ExcludedStrings
-	err = json.Unmarshal(content, &excludedStrings)
+	packageFiles, err := es.findPackageFiles(dirName)
 	if err != nil {
-		fmt.Print(err)
-		return err
+		return nil, err
 	}
 
-	for i := range excludedStrings.ExcludedStrings {
-		es.FilteredStrings[excludedStrings.ExcludedStrings[i]] = excludedStrings.ExcludedStrings[i]
+	var packageNames []string
+	for k := range packageFiles {
+		packageNames = append(packageNames, k)
 	}
+	sort.Strings(packageNames)
+
+	var fileNames []string
+	for _, k := range packageNames {
+		es.Println("Extracting strings in package:", k)
+		for _, fileName := range packageFiles[k] {
+			if es.IgnoreRegexp != nil && es.IgnoreRegexp.MatchString(fileName) {
+				es.Println("Using ignore-regexp:", es.options.IgnoreRegexpFlag)
+				continue
+			} else {
+				es.Println("No match for ignore-regexp:", es.options.IgnoreRegexpFlag)
+			}
+
+			if rules.ExcludesFile(fileName) {
+				es.Println("Using excludedFileRegexps of:", strings.Join(rules.Filenames, ", "))
+				continue
+			}
 
-	for _, excludeLine := range excludedStrings.ExcludedLines {
-		es.FilteredLines = append(es.FilteredLines, excludeLine)
+			if strings.HasSuffix(fileName, ".go") {
+				fileNames = append(fileNames, fileName)
+			}
+		}
 	}
 
-	if len(excludedStrings.ExcludedFileRegexps) > 0 {
-		excludeFileRegexs := strings.Join(excludedStrings.ExcludedFileRegexps, "|")
-		es.FilteredFileRegexps = regexp.MustCompile(excludeFileRegexs)
+	templateFileNames, err := es.findTemplateFiles(dirName, rules)
+	if err != nil {
+		return nil, err
 	}
+	fileNames = append(fileNames, templateFileNames...)
 
-	for _, enforcedFunc := range excludedStrings.EnforcedFuncs {
-		es.EnforcedFuncs = append(es.EnforcedFuncs, enforcedFunc)
+	assetFileNames, err := es.findAssetFiles(dirName, rules)
+	if err != nil {
+		return nil, err
 	}
+	fileNames = append(fileNames, assetFileNames...)
 
-	return nil
+	if recursive {
+		fileInfos, _ := ioutil.ReadDir(dirName)
+		for _, fileInfo := range fileInfos {
+			if fileInfo.IsDir() && !strings.HasPrefix(fileInfo.Name(), ".") && fileInfo.Name() != "vendor" && fileInfo.Name() != "testdata" {
+				subDirFileNames, err := es.findFiles(filepath.Join(dirName, fileInfo.Name()), recursive)
+				if _, ok := err.(common.RuleErrors); ok {
+					return nil, err
+				}
+				if err != nil {
+					es.Println(err)
+					continue
+				}
+				fileNames = append(fileNames, subDirFileNames...)
+			}
+		}
+	}
+
+	return fileNames, nil
 }
 
-func (es *extractStrings) loadExcludedRegexps() error {
-	_, err := os.Stat(es.options.ExcludedFilenameFlag)
-	if os.IsNotExist(err) {
-		es.Println("Could not find:", es.options.ExcludedFilenameFlag)
-		return nil
-	}
+// findPackageFiles returns the Go files of the package in dirName, including test files, keyed by package name,
+// the go command selects the files so build tags are honoured, directories it cannot load are parsed as is
+func (es *extractStrings) findPackageFiles(dirName string) (map[string][]string, error) {
+	packageFiles := make(map[string][]string)
 
-	es.Println("Excluding regexps in file:", es.options.ExcludedFilenameFlag)
+	listedPackages, err := common.ListPackages(dirName, es.options.TagsFlag, ".")
+	if err == nil && len(listedPackages) == 1 && listedPackages[0].Error == nil {
+		listedPackage := listedPackages[0]
+		for _, fileName := range listedPackage.SourceFiles(true) {
+			packageFiles[listedPackage.Name] = append(packageFiles[listedPackage.Name], filepath.Join(dirName, filepath.Base(fileName)))
+		}
+		return packageFiles, nil
+	}
 
-	content, err := ioutil.ReadFile(es.options.ExcludedFilenameFlag)
+	packages, err := parser.ParseDir(token.NewFileSet(), dirName, nil, parser.ParseComments)
 	if err != nil {
-		fmt.Print(err)
-		return err
+		return nil, err
+	}
+
+	for k, pkg := range packages {
+		for fileName := range pkg.Files {
+			packageFiles[k] = append(packageFiles[k], fileName)
+		}
+		sort.Strings(packageFiles[k])
+	}
+
+	return packageFiles, nil
+}
+
+// loadSinkFinder type checks the package of the file, packages are cached by directory
+func (es *extractStrings) loadSinkFinder(absFilePath string) (*common.SinkFinder, error) {
+	dirName := filepath.Dir(absFilePath)
+	if sinkFinder, ok := es.sinkFinders[dirName]; ok {
+		return sinkFinder, nil
 	}
 
-	var excludedRegexps common.ExcludedStrings
-	err = json.Unmarshal(content, &excludedRegexps)
+	typedPackage, err := es.packageLoader.LoadDir(dirName)
 	if err != nil {
-		fmt.Print(err)
-		return err
+		return nil, err
 	}
 
-	for _, regexpString := range excludedRegexps.ExcludedRegexps {
-		compiledRegexp, err := regexp.Compile(regexpString)
-		if err != nil {
-			fmt.Println("WARNING error compiling regexp:", regexpString)
-		}
+	if len(typedPackage.TypeErrors) > 0 {
+		es.Printf("WARNING package %s has %d type errors, strings using unresolved types will not reach a sink\n", typedPackage.Name, len(typedPackage.TypeErrors))
+	}
 
-		es.FilteredRegexps = append(es.FilteredRegexps, compiledRegexp)
+	rules, err := es.rulesLoader.Rules(dirName)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	sinks, err := common.ParseSinks(append(append([]string{}, common.DEFAULT_SINKS...), rules.Sinks...))
+	if err != nil {
+		return nil, err
+	}
+
+	sinkFinder := common.NewSinkFinder(typedPackage, sinks)
+	es.typedPackages[dirName] = typedPackage
+	es.sinkFinders[dirName] = sinkFinder
+
+	return sinkFinder, nil
 }
 
-type CaptureGroupSubstrings struct {
-	RegexpsStrings []string `json:"captureGroupSubstrings"`
+// typedFile returns a file of a package loaded by loadSinkFinder, it is called concurrently and only reads the caches
+func (es *extractStrings) typedFile(absFilePath string) (*ast.File, *token.FileSet, *common.SinkFinder, error) {
+	dirName := filepath.Dir(absFilePath)
+	typedPackage, ok := es.typedPackages[dirName]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("i18n4go: could not load the package in dir: %s", dirName)
+	}
+
+	astFile, ok := typedPackage.Files[absFilePath]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("i18n4go: file %s is not part of package %s", absFilePath, typedPackage.Name)
+	}
+
+	return astFile, es.packageLoader.Fset, es.sinkFinders[dirName], nil
 }
 
-func (es *extractStrings) loadSubstringRegexps() error {
-	_, err := os.Stat(es.options.SubstringFilenameFlag)
-	if os.IsNotExist(err) {
-		es.Println("Could not find:", es.options.SubstringFilenameFlag)
-		return nil
+func (es *extractStrings) findImportPath(filename string) (string, error) {
+	path := es.OutputDirname
+
+	filePath, err := common.FindFilePath(filename)
+	if err != nil {
+		fmt.Println("ERROR opening file", err)
+		return "", err
+	}
+
+	pkg, err := common.FindPackage(filePath, es.options.TagsFlag)
+	if err != nil {
+		fmt.Println("ERROR opening file", err)
+		return "", err
 	}
 
-	es.Println("Capturing substrings in file:", es.options.SubstringFilenameFlag)
+	// packages outside of a module or GOPATH get a local import path such as _/abs/dir
+	if pkg.ImportPath == "" || strings.HasPrefix(pkg.ImportPath, "_") || strings.HasPrefix(pkg.ImportPath, ".") {
+		return path, nil
+	}
+
+	return filepath.Join(path, filepath.FromSlash(pkg.ImportPath)), nil
+}
+
+func (es *extractStrings) findPackagePath(filename string) (string, error) {
+	path := es.OutputDirname
 
-	content, err := ioutil.ReadFile(es.options.SubstringFilenameFlag)
+	filePath, err := common.FindFilePath(filename)
 	if err != nil {
-		fmt.Print(err)
-		return err
+		fmt.Println("ERROR opening file", err)
+		return "", err
+	}
+
+	pkg, err := common.FindPackage(filePath, es.options.TagsFlag)
+	if err == nil && pkg.Name == "" && pkg.Error != nil {
+		err = errors.New(pkg.Error.Err)
+	}
+	if err != nil {
+		fmt.Println("ERROR opening file", err)
+		return "", err
 	}
 
-	var captureGroupStrings CaptureGroupSubstrings
-	err = json.Unmarshal(content, &captureGroupStrings)
+	return filepath.Join(path, pkg.Name), nil
+}
+
+func (es *extractStrings) saveExtractedStrings(ef *extractedFile, outputDirname string) error {
+	filename := ef.Filename + ".extracted.json"
+	if len(ef.ExtractedStrings) != 0 {
+		es.Println("Saving extracted strings to file:", filename)
+	}
+
+	if !es.options.DryRunFlag {
+		err := common.CreateOutputDirsIfNeeded(outputDirname)
+		if err != nil {
+			es.Println(err)
+			return err
+		}
+	}
+
+	stringInfos := common.StringInfoMapValues2Array(ef.ExtractedStrings)
+	jsonData, err := json.MarshalIndent(stringInfos, "", "   ")
 	if err != nil {
-		fmt.Print(err)
+		es.Println(err)
 		return err
 	}
-	for _, regexpString := range captureGroupStrings.RegexpsStrings {
-		compiledRegexp, err := regexp.Compile(regexpString)
+	jsonData = common.UnescapeHTML(jsonData)
+
+	if !es.options.DryRunFlag && len(stringInfos) != 0 {
+		file, err := os.Create(filepath.Join(outputDirname, filename[strings.LastIndex(filename, string(os.PathSeparator))+1:len(filename)]))
+		defer file.Close()
 		if err != nil {
-			fmt.Println("WARNING error compiling regexp:", regexpString)
+			es.Println(err)
+			return err
 		}
 
-		es.SubstringRegexps = append(es.SubstringRegexps, compiledRegexp)
+		file.Write(jsonData)
 	}
 
 	return nil
 }
 
-func (es *extractStrings) extractString(f *ast.File, fset *token.FileSet) error {
+func (es *extractStrings) extractString(ef *extractedFile, f *ast.File, fset *token.FileSet) error {
 	shouldProcessBasicLit := true
+	var funcDecl *ast.FuncDecl
 	ast.Inspect(f, func(n ast.Node) bool {
+		if n == nil {
+			return true
+		}
+
+		// func declarations do not nest, every node inside one is visited before its end
+		if funcDecl != nil && n.Pos() >= funcDecl.End() {
+			funcDecl = nil
+			ef.funcName = ""
+		}
+
 		switch x := n.(type) {
+		case *ast.FuncDecl:
+			funcDecl = x
+			ef.funcName = common.FuncDeclName(x)
 		case *ast.CallExpr:
-			es.processEnforcedFunc(x, fset)
+			es.processContextCall(ef, x)
+			es.processEnforcedFunc(ef, x, fset)
+			es.processTemplateParse(ef, x, fset)
+		case *ast.CompositeLit:
+			es.processEnforcedFields(ef, x, fset)
+		case *ast.BinaryExpr:
+			if x.Op == token.ADD && !ef.handled[x] {
+				es.processConcatenation(ef, x, fset, "")
+			}
 		case *ast.BasicLit:
-			if shouldProcessBasicLit {
-				es.processBasicLit(x, n, fset, false)
+			if shouldProcessBasicLit && !ef.handled[x] {
+				es.processBasicLit(ef, x, n, fset, "")
+			} else if !shouldProcessBasicLit && x.Kind == token.STRING {
+				s, _ := strconv.Unquote(x.Value)
+				ef.classify(s, "", fset.Position(x.Pos()), common.SKIP_DECISION, "index or key")
 			}
 			shouldProcessBasicLit = true
 		case *ast.IndexExpr:
@@ -506,23 +990,105 @@ func (es *extractStrings) extractString(f *ast.File, fset *token.FileSet) error
 	return nil
 }
 
-func (es *extractStrings) processBasicLit(basicLit *ast.BasicLit, n ast.Node, fset *token.FileSet, mustInclude bool) {
+// processConcatenation extracts the adjacent constant operands of a chain of + as one string, e.g., "Failed to " + "connect: " + reason
+// is extracted as "Failed to connect: ", the literals of the folded operands are not extracted on their own,
+// enforcedBy is the enforcement rule of the concatenation, if any
+func (es *extractStrings) processConcatenation(ef *extractedFile, binaryExpr *ast.BinaryExpr, fset *token.FileSet, enforcedBy string) {
+	_, joins := common.Concatenation(binaryExpr)
+	for _, join := range joins {
+		ef.handled[join] = true
+	}
+
+	for _, foldedString := range ef.constFolder.FoldConcatenation(binaryExpr) {
+		mustInclude, ignored := false, false
+		for _, basicLit := range foldedString.Lits {
+			ef.handled[basicLit] = true
+			mustInclude = mustInclude || ef.directives.Translates(basicLit)
+			ignored = ignored || ef.directives.Ignores(basicLit)
+		}
+		position := fset.Position(foldedString.Operands[0].Pos())
+		context, inContextCall := ef.context(foldedString.Lits[0])
+		if mustInclude {
+			ef.classify(foldedString.Value, context, position, common.EXTRACT_DECISION, "directive translate")
+			ef.addOccurrence(foldedString.Value, context, position, "")
+			continue
+		}
+		if ignored {
+			ef.classify(foldedString.Value, context, position, common.SKIP_DECISION, "directive ignore")
+			continue
+		}
+
+		sink := ""
+		if es.options.SinksFlag && enforcedBy == "" {
+			if ef.sinkFinder != nil {
+				sink = ef.sinkFinder.FindSink(foldedString.Operands[0])
+			}
+			if sink == "" && inContextCall {
+				sink = common.CONTEXT_FUNC
+			}
+			if sink == "" {
+				ef.classify(foldedString.Value, context, position, common.SKIP_DECISION, "no sink")
+				continue
+			}
+		}
+
+		es.processString(ef, foldedString.Value, context, position, enforcedBy, sink)
+	}
+}
+
+// processBasicLit extracts a literal, enforcedBy is the enforcement rule of the literal, if any
+func (es *extractStrings) processBasicLit(ef *extractedFile, basicLit *ast.BasicLit, n ast.Node, fset *token.FileSet, enforcedBy string) {
+	context, inContextCall := ef.context(basicLit)
+
+	position := fset.Position(n.Pos())
+	value := ""
+	if basicLit.Kind == token.STRING {
+		value, _ = strconv.Unquote(basicLit.Value)
+	}
+
+	// a translate directive wins over the exclusion rules and the other directives
+	if basicLit.Kind == token.STRING && ef.directives.Translates(basicLit) {
+		if value != "" {
+			ef.classify(value, context, position, common.EXTRACT_DECISION, "directive translate")
+			ef.addOccurrence(value, context, position, "")
+		}
+		return
+	}
+	if ef.directives.Ignores(basicLit) {
+		if basicLit.Kind == token.STRING {
+			ef.classify(value, context, position, common.SKIP_DECISION, "directive ignore")
+		}
+		return
+	}
+
+	sink := ""
+	if es.options.SinksFlag && enforcedBy == "" {
+		if ef.sinkFinder != nil {
+			sink = ef.sinkFinder.FindSink(basicLit)
+		}
+		// the message of TC is translated wherever it flows
+		if sink == "" && inContextCall {
+			sink = common.CONTEXT_FUNC
+		}
+		if sink == "" {
+			if basicLit.Kind == token.STRING {
+				ef.classify(value, context, position, common.SKIP_DECISION, "no sink")
+			}
+			return
+		}
+	}
+
 	foundSubstring := false
-	for _, compiledRegexp := range es.SubstringRegexps {
+	for _, compiledRegexp := range ef.rules.CaptureGroupSubstrings {
 		if compiledRegexp.MatchString(basicLit.Value) {
 			submatches := compiledRegexp.FindStringSubmatch(basicLit.Value)
 			if submatches == nil {
-				es.Println(fmt.Sprintf("WARNING No capturing group found in %s", compiledRegexp.String()))
+				ef.Println(fmt.Sprintf("WARNING No capturing group found in %s", compiledRegexp.String()))
 				return
 			}
 			captureGroup := submatches[1]
-			position := fset.Position(n.Pos())
-			stringInfo := common.StringInfo{Value: captureGroup,
-				Filename: position.Filename,
-				Offset:   position.Offset,
-				Line:     position.Line,
-				Column:   position.Column}
-			es.ExtractedStrings[captureGroup] = stringInfo
+			ef.classify(captureGroup, context, position, common.EXTRACT_DECISION, "captureGroupSubstrings "+strconv.Quote(compiledRegexp.String()))
+			ef.addOccurrence(captureGroup, context, position, sink)
 			foundSubstring = true
 		}
 	}
@@ -530,109 +1096,320 @@ func (es *extractStrings) processBasicLit(basicLit *ast.BasicLit, n ast.Node, fs
 		return
 	}
 
-	if len(es.FilteredRegexps) > 0 && !mustInclude {
+	if basicLit.Kind == token.STRING {
+		es.processString(ef, value, context, position, enforcedBy, sink)
+	}
+}
+
+// processString applies the exclusion rules and the enabled detectors to a string found at position and records it,
+// the excluded lines and the detectors do not apply to the strings enforced by the enforcedBy rule
+func (es *extractStrings) processString(ef *extractedFile, s string, context string, position token.Position, enforcedBy string, sink string) {
+	mustInclude := enforcedBy != ""
+	if len(ef.rules.ExcludedRegexps) > 0 && !mustInclude {
 		// If we want to filter out some strings based on a substring in that line of code
-		if line, err := readLine(fset.Position(n.Pos()).Filename, fset.Position(n.Pos()).Line); err == nil {
-			for _, exclude := range es.FilteredLines {
+		if line := ef.line(position.Line); line != "" {
+			for _, exclude := range ef.rules.ExcludedLines {
 				if strings.Contains(line, exclude) {
+					ef.classify(s, context, position, common.SKIP_DECISION, "excludedLines "+strconv.Quote(exclude))
 					return
 				}
 			}
 		}
 	}
 
-	s, _ := strconv.Unquote(basicLit.Value)
-	if len(s) > 0 && basicLit.Kind == token.STRING && s != "\t" && s != "\n" && s != " " && !es.filter(s) { // TODO: fix to remove these: s != "\\t" && s != "\\n" && s != " "
-		position := fset.Position(n.Pos())
-		stringInfo := common.StringInfo{Value: s,
-			Filename: position.Filename,
+	if len(s) == 0 || s == "\t" || s == "\n" || s == " " { // TODO: fix to remove these: s != "\\t" && s != "\\n" && s != " "
+		ef.classify(s, context, position, common.SKIP_DECISION, "blank")
+		return
+	}
+
+	if reason := es.filterReason(ef, s); reason != "" {
+		ef.classify(s, context, position, common.SKIP_DECISION, reason)
+		return
+	}
+
+	if !mustInclude {
+		if detection := ef.rules.Detection(s); detection != nil {
+			ef.classify(s, context, position, common.SKIP_DECISION, "detector "+detection.Detector)
+			return
+		}
+	}
+
+	reason := "default"
+	switch {
+	case mustInclude:
+		reason = enforcedBy
+	case sink != "":
+		reason = "sink " + sink
+	}
+	ef.classify(s, context, position, common.EXTRACT_DECISION, reason)
+	ef.addOccurrence(s, context, position, sink)
+}
+
+// classify records the decision taken for a string and the rule, directive or detector behind it when --explain is set
+// Println keeps a verbose message of the file, it is printed once the file is extracted
+func (ef *extractedFile) Println(a ...interface{}) {
+	ef.messages = append(ef.messages, fmt.Sprintln(a...))
+}
+
+func (ef *extractedFile) classify(value string, context string, position token.Position, decision string, reason string) {
+	if !ef.explain {
+		return
+	}
+
+	ef.classifications = append(ef.classifications, common.Classification{
+		Filename:   ef.Filename,
+		Line:       position.Line,
+		Column:     position.Column,
+		Value:      value,
+		Context:    context,
+		Decision:   decision,
+		Reason:     reason,
+		Detections: common.Detect(value),
+	})
+}
+
+// addOccurrence records where value is used in context, the position of the first occurrence is kept as the position of the string
+func (ef *extractedFile) addOccurrence(value string, context string, position token.Position, sink string) {
+	occurrence := common.Occurrence{
+		Filename: ef.Filename,
+		Offset:   position.Offset,
+		Line:     position.Line,
+		Column:   position.Column,
+		Func:     ef.funcName,
+	}
+
+	key := common.MessageKey(context, value)
+	stringInfo, ok := ef.ExtractedStrings[key]
+	if !ok {
+		stringInfo = common.StringInfo{Value: value,
+			Context:  context,
+			Filename: ef.Filename,
 			Offset:   position.Offset,
 			Line:     position.Line,
-			Column:   position.Column}
-		es.ExtractedStrings[s] = stringInfo
+			Column:   position.Column,
+			Sink:     sink}
 	}
-}
 
-func readLine(fn string, n int) (string, error) {
-	if n < 1 {
-		return "", fmt.Errorf("invalid request: line %d", n)
+	for _, existing := range stringInfo.Occurrences {
+		if existing.Offset == occurrence.Offset {
+			return
+		}
 	}
-	f, err := os.Open(fn)
-	if err != nil {
-		return "", err
+
+	if stringInfo.Sink == "" {
+		stringInfo.Sink = sink
 	}
-	defer f.Close()
-	bf := bufio.NewReader(f)
-	var line string
-	for lnum := 0; lnum < n; lnum++ {
-		line, err = bf.ReadString('\n')
-		if err == io.EOF {
-			switch lnum {
-			case 0:
-				return "", errors.New("no lines in file")
-			case 1:
-				return "", errors.New("only 1 line")
-			default:
-				return "", fmt.Errorf("only %d lines", lnum)
-			}
+
+	if description := ef.descriptions[position.Line]; description != "" && !strings.Contains(stringInfo.Description, description) {
+		if stringInfo.Description != "" {
+			stringInfo.Description += "\n"
 		}
-		if err != nil {
-			return "", err
+		stringInfo.Description += description
+	}
+	stringInfo.Occurrences = append(stringInfo.Occurrences, occurrence)
+	stringInfo.Count = len(stringInfo.Occurrences)
+	ef.ExtractedStrings[key] = stringInfo
+}
+
+// context returns the message context of a string and whether it is the message of a TC call,
+// the context of TC wins over the one of a context directive
+func (ef *extractedFile) context(node ast.Node) (string, bool) {
+	context, inContextCall := "", false
+	for _, messageContext := range ef.contexts {
+		if node.Pos() >= messageContext.pos && node.End() <= messageContext.end {
+			context, inContextCall = messageContext.context, true
 		}
 	}
-	if line == "" {
-		return "", fmt.Errorf("line %d empty", n)
+	if inContextCall {
+		return context, true
 	}
-	return line, nil
+
+	return ef.directives.Context(node), false
 }
 
-func (es *extractStrings) excludeImports(astFile *ast.File) {
+// line returns the source of line n of the file, lines start at 1
+func (ef *extractedFile) line(n int) string {
+	if n < 1 || n > len(ef.lines) {
+		return ""
+	}
+
+	return ef.lines[n-1]
+}
+
+func (es *extractStrings) excludeImports(ef *extractedFile, astFile *ast.File) {
 	for i := range astFile.Imports {
 		importString, _ := strconv.Unquote(astFile.Imports[i].Path.Value)
-		es.FilteredStrings[importString] = importString
+		ef.importStrings[importString] = true
 	}
-
 }
 
-func (es *extractStrings) filter(aString string) bool {
+// filterReason returns the rule that excludes aString, "" when it is not excluded
+func (es *extractStrings) filterReason(ef *extractedFile, aString string) string {
 	for i := range common.BLANKS {
 		if aString == common.BLANKS[i] {
-			return true
+			return "blank"
 		}
 	}
 
-	if _, ok := es.FilteredStrings[aString]; ok {
-		return true
+	if ef.rules.ExcludedStrings[aString] {
+		return "excludedStrings"
+	}
+
+	if ef.importStrings[aString] {
+		return "import"
 	}
 
-	for _, compiledRegexp := range es.FilteredRegexps {
+	for _, compiledRegexp := range ef.rules.ExcludedRegexps {
 		if compiledRegexp.MatchString(aString) {
-			return true
+			return "excludedRegexps " + strconv.Quote(compiledRegexp.String())
 		}
 	}
 
-	return false
+	return ""
+}
+
+// processContextCall records the context of the message of TC(context, message), the context itself is not extracted
+func (es *extractStrings) processContextCall(ef *extractedFile, call *ast.CallExpr) {
+	if !common.IsContextCall(call) {
+		return
+	}
+
+	context, ok := ef.constFolder.Fold(call.Args[0])
+	if !ok {
+		return
+	}
+
+	ast.Inspect(call.Args[0], func(n ast.Node) bool {
+		if n != nil {
+			ef.handled[n] = true
+		}
+		return true
+	})
+	ef.contexts = append(ef.contexts, messageContext{pos: call.Args[1].Pos(), end: call.Args[1].End(), context: context})
+}
+
+// processEnforcedFunc extracts the string arguments of the calls of the enforced funcs, a func matched by name only
+// enforces its first string argument, a qualified one the arguments at its positions and the others are skipped
+func (es *extractStrings) processEnforcedFunc(ef *extractedFile, call *ast.CallExpr, fset *token.FileSet) {
+	for _, enforcedCall := range ef.rules.EnforcedCalls {
+		if !enforcedCall.MatchCall(call, ef.imports, ef.typedPackage) {
+			continue
+		}
+
+		enforcedBy := "enforcedFuncs " + enforcedCall.Pattern
+		if !enforcedCall.Qualified() && len(enforcedCall.Positions) == 0 {
+			es.processFirstEnforcedArg(ef, call, fset, enforcedBy)
+			continue
+		}
+
+		for i, arg := range call.Args {
+			if enforcedCall.Enforces(i) {
+				es.processEnforcedExpr(ef, arg, fset, enforcedBy)
+			} else {
+				es.skipNotEnforced(ef, arg, fset, "unenforced argument of "+enforcedBy)
+			}
+		}
+	}
 }
 
-func (es *extractStrings) processEnforcedFunc(call *ast.CallExpr, fset *token.FileSet) {
-	if fun, ok := call.Fun.(*ast.SelectorExpr); ok {
-		for _, enforcedFunc := range es.EnforcedFuncs {
-			if fun.Sel.Name == enforcedFunc {
-				for _, arg := range call.Args {
-					if b, ok := arg.(*ast.BasicLit); ok {
-						es.processBasicLit(b, arg, fset, true)
-						return
-					}
-					// in case a string argument is wrapped by fmt.Sprintf or similar funcs
-					if innerCall, ok := arg.(*ast.CallExpr); ok {
-						for _, innerArg := range innerCall.Args {
-							if innerB, ok := innerArg.(*ast.BasicLit); ok {
-								es.processBasicLit(innerB, innerArg, fset, true)
-							}
-						}
-					}
+func (es *extractStrings) processFirstEnforcedArg(ef *extractedFile, call *ast.CallExpr, fset *token.FileSet, enforcedBy string) {
+	for _, arg := range call.Args {
+		if b, ok := arg.(*ast.BasicLit); ok {
+			ef.handled[b] = true
+			es.processBasicLit(ef, b, arg, fset, enforcedBy)
+			return
+		}
+		// in case a string argument is wrapped by fmt.Sprintf or similar funcs
+		if innerCall, ok := arg.(*ast.CallExpr); ok {
+			for _, innerArg := range innerCall.Args {
+				if innerB, ok := innerArg.(*ast.BasicLit); ok {
+					ef.handled[innerB] = true
+					es.processBasicLit(ef, innerB, innerArg, fset, enforcedBy)
 				}
 			}
 		}
 	}
 }
+
+// processEnforcedFields extracts the enforced fields of a composite literal and skips its other string fields,
+// it also records the elided types of the composite literals it holds, they are visited after it
+func (es *extractStrings) processEnforcedFields(ef *extractedFile, lit *ast.CompositeLit, fset *token.FileSet) {
+	typeExpr := lit.Type
+	if typeExpr == nil {
+		typeExpr = ef.elidedTypes[lit]
+	}
+
+	var elementType ast.Expr
+	switch x := typeExpr.(type) {
+	case *ast.ArrayType:
+		elementType = x.Elt
+	case *ast.MapType:
+		elementType = x.Value
+	}
+	if elementType != nil {
+		for _, elt := range lit.Elts {
+			if keyValue, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = keyValue.Value
+			}
+			if elementLit, ok := elt.(*ast.CompositeLit); ok && elementLit.Type == nil {
+				ef.elidedTypes[elementLit] = elementType
+			}
+		}
+	}
+
+	for _, enforcedField := range ef.rules.EnforcedFields {
+		if !enforcedField.MatchType(lit, typeExpr, ef.imports, ef.typedPackage) {
+			continue
+		}
+
+		enforcedBy := "enforcedFields " + enforcedField.Pattern
+		for _, elt := range lit.Elts {
+			keyValue, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			if key, ok := keyValue.Key.(*ast.Ident); ok && enforcedField.Enforces(key.Name) {
+				es.processEnforcedExpr(ef, keyValue.Value, fset, enforcedBy)
+			} else {
+				es.skipNotEnforced(ef, keyValue.Value, fset, "unenforced field of "+enforcedBy)
+			}
+		}
+	}
+}
+
+// processEnforcedExpr extracts a string literal, a concatenation or the string arguments of a call such as fmt.Sprintf
+func (es *extractStrings) processEnforcedExpr(ef *extractedFile, expr ast.Expr, fset *token.FileSet, enforcedBy string) {
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		if !ef.handled[x] {
+			ef.handled[x] = true
+			es.processBasicLit(ef, x, x, fset, enforcedBy)
+		}
+	case *ast.BinaryExpr:
+		if x.Op == token.ADD && !ef.handled[x] {
+			ef.handled[x] = true
+			es.processConcatenation(ef, x, fset, enforcedBy)
+		}
+	case *ast.CallExpr:
+		for _, arg := range x.Args {
+			if b, ok := arg.(*ast.BasicLit); ok && !ef.handled[b] {
+				ef.handled[b] = true
+				es.processBasicLit(ef, b, arg, fset, enforcedBy)
+			}
+		}
+	}
+}
+
+// skipNotEnforced skips a string literal passed to an enforced func or field but not enforced by it, e.g., the name of a flag
+func (es *extractStrings) skipNotEnforced(ef *extractedFile, expr ast.Expr, fset *token.FileSet, reason string) {
+	basicLit, ok := expr.(*ast.BasicLit)
+	if !ok || basicLit.Kind != token.STRING || ef.handled[basicLit] {
+		return
+	}
+
+	ef.handled[basicLit] = true
+	value, _ := strconv.Unquote(basicLit.Value)
+	context, _ := ef.context(basicLit)
+	ef.classify(value, context, fset.Position(basicLit.Pos()), common.SKIP_DECISION, reason)
+}
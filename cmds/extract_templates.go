@@ -0,0 +1,188 @@
+package cmds
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/Liam-Williams/i18n4go/common"
+)
+
+// findTemplateFiles lists the text/template and html/template files in dirName
+func (es *extractStrings) findTemplateFiles(dirName string, rules *common.Rules) ([]string, error) {
+	fileInfos, err := ioutil.ReadDir(dirName)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileNames []string
+	for _, fileInfo := range fileInfos {
+		fileName := filepath.Join(dirName, fileInfo.Name())
+		// a template file selected by an assets rule is extracted as an asset
+		if fileInfo.IsDir() || strings.HasPrefix(fileInfo.Name(), ".") || !common.IsTemplateFile(fileName) || rules.Asset(fileName) != nil {
+			continue
+		}
+
+		if es.IgnoreRegexp != nil && es.IgnoreRegexp.MatchString(fileName) {
+			es.Println("Using ignore-regexp:", es.options.IgnoreRegexpFlag)
+			continue
+		}
+
+		if rules.ExcludesFile(fileName) {
+			es.Println("Using excludedFileRegexps of:", strings.Join(rules.Filenames, ", "))
+			continue
+		}
+
+		fileNames = append(fileNames, fileName)
+	}
+
+	return fileNames, nil
+}
+
+// templateFuncs are the funcs whose string arguments are extracted from templates, the qualified enforced funcs only apply to go files
+func (es *extractStrings) templateFuncs(ef *extractedFile) []string {
+	funcs := append([]string{}, common.TEMPLATE_FUNCS...)
+	for _, enforcedCall := range ef.rules.EnforcedCalls {
+		if !enforcedCall.Qualified() {
+			funcs = append(funcs, enforcedCall.Name)
+		}
+	}
+
+	return funcs
+}
+
+// extractTemplateFile extracts the text and the {{T "..."}} strings of a template file
+func (es *extractStrings) extractTemplateFile(ef *extractedFile) error {
+	content, err := ioutil.ReadFile(ef.AbsFilename)
+	if err != nil {
+		return err
+	}
+
+	text := string(content)
+	trees, err := common.ParseTemplate(ef.AbsFilename, text)
+	if err != nil {
+		return err
+	}
+	ef.lines = strings.Split(text, "\n")
+
+	for _, comment := range common.TemplateComments(trees) {
+		if name, ok := common.Directive(comment.Value); ok && name == common.IGNORE_FILE_DIRECTIVE {
+			ef.Println("i18n4go: ignoring file with an ignore-file directive:", ef.AbsFilename)
+			return nil
+		}
+	}
+
+	for _, comment := range common.TemplateComments(trees) {
+		if description, ok := common.TranslatorComment(comment.Value); ok && description != "" {
+			startLine, _ := common.TemplateLineColumn(text, comment.Offset)
+			endLine, _ := common.TemplateLineColumn(text, comment.Offset+len(comment.Value))
+			common.AddTranslatorComment(ef.descriptions, startLine, endLine, true, description)
+		}
+	}
+
+	for _, templateString := range common.TemplateStrings(trees, common.IsHTMLTemplate(ef.AbsFilename), es.templateFuncs(ef)) {
+		line, column := common.TemplateLineColumn(text, templateString.Offset)
+		position := token.Position{Filename: ef.AbsFilename, Offset: templateString.Offset, Line: line, Column: column}
+		es.processString(ef, templateString.Value, "", position, "", "")
+	}
+
+	return nil
+}
+
+// processTemplateParse extracts the strings of templates parsed at runtime, e.g., template.New("name").Parse("..."),
+// instead of the whole template source, the markup of the templates of html/template is left out
+func (es *extractStrings) processTemplateParse(ef *extractedFile, call *ast.CallExpr, fset *token.FileSet) {
+	fun, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || fun.Sel.Name != "Parse" || len(call.Args) != 1 {
+		return
+	}
+
+	templatePackage := ef.templatePackage(fun.X)
+	if templatePackage == "" {
+		return
+	}
+
+	basicLit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || basicLit.Kind != token.STRING || ef.directives.Translates(basicLit) {
+		return
+	}
+	if ef.directives.Ignores(basicLit) {
+		ef.handled[basicLit] = true
+		return
+	}
+
+	text, err := strconv.Unquote(basicLit.Value)
+	if err != nil || !strings.Contains(text, "{{") {
+		return
+	}
+
+	trees, err := common.ParseTemplate(ef.Filename, text)
+	if err != nil {
+		return
+	}
+	ef.handled[basicLit] = true
+
+	litPosition := fset.Position(basicLit.Pos())
+	for _, templateString := range common.TemplateStrings(trees, templatePackage == "html/template", es.templateFuncs(ef)) {
+		// raw strings keep their lines, the positions inside interpreted strings are approximate
+		line, column := common.TemplateLineColumn(text, templateString.Offset)
+		position := litPosition
+		position.Offset += 1 + templateString.Offset
+		position.Line += line - 1
+		if line == 1 {
+			position.Column += column
+		} else {
+			position.Column = column
+		}
+
+		es.processString(ef, templateString.Value, "", position, "", "")
+	}
+}
+
+// templatePackage returns the template package, text/template or html/template, whose Template receiver is, or an
+// empty string. The type information resolves it when the package of the file is loaded, e.g., with --sinks, otherwise
+// receiver must be a chain of calls of the package, e.g., template.Must(template.New("name").Funcs(funcs))
+func (ef *extractedFile) templatePackage(receiver ast.Expr) string {
+	if ef.typedPackage != nil {
+		if typ := ef.typedPackage.Info.TypeOf(receiver); typ != nil {
+			if pointer, ok := typ.(*types.Pointer); ok {
+				typ = pointer.Elem()
+			}
+			if named, ok := typ.(*types.Named); ok && named.Obj().Name() == "Template" && named.Obj().Pkg() != nil {
+				return templateImportPath(named.Obj().Pkg().Path())
+			}
+			return ""
+		}
+	}
+
+	expr := receiver
+	for {
+		switch x := expr.(type) {
+		case *ast.ParenExpr:
+			expr = x.X
+		case *ast.CallExpr:
+			expr = x.Fun
+		case *ast.SelectorExpr:
+			if ident, ok := x.X.(*ast.Ident); ok {
+				return templateImportPath(ef.imports[ident.Name])
+			}
+			expr = x.X
+		default:
+			return ""
+		}
+	}
+}
+
+// templateImportPath is importPath when it is the import path of a template package, an empty string otherwise
+func templateImportPath(importPath string) string {
+	if importPath == "text/template" || importPath == "html/template" {
+		return importPath
+	}
+
+	return ""
+}
@@ -352,9 +352,9 @@ func updateTranslations(localMap map[string]common.I18nStringInfo, localeFile st
 		fmt.Println("\t", key)
 
 		if locale == "en_US" {
-			localMap[value] = common.I18nStringInfo{ID: value, Translation: value}
+			localMap[value] = common.I18nStringInfo{ID: value, Translation: value, Description: localMap[key].Description}
 		} else {
-			localMap[value] = common.I18nStringInfo{ID: value, Translation: localMap[key].Translation, Modified: true}
+			localMap[value] = common.I18nStringInfo{ID: value, Translation: localMap[key].Translation, Modified: true, Description: localMap[key].Description}
 		}
 		delete(localMap, key)
 	}
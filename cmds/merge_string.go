@@ -65,6 +65,8 @@ func (ms *MergeStrings) combineStringInfosPerDirectory(directory string) error {
 
 	var eg errgroup.Group
 	var combinedMap sync.Map
+	var descriptionsMutex sync.Mutex
+	descriptions := make(map[string][]string)
 	maxWorkers := runtime.GOMAXPROCS(0)
 	sem := semaphore.NewWeighted(int64(maxWorkers))
 
@@ -82,8 +84,14 @@ func (ms *MergeStrings) combineStringInfosPerDirectory(directory string) error {
 			if err != nil {
 				return fmt.Errorf("err retrieving file %v: %w", f, err)
 			}
+			// the same ID in different contexts are different strings
 			for _, stringInfo := range StringInfos {
-				_, _ = combinedMap.LoadOrStore(stringInfo.ID, stringInfo)
+				_, _ = combinedMap.LoadOrStore(stringInfo.Key(), stringInfo)
+				if stringInfo.Description != "" {
+					descriptionsMutex.Lock()
+					descriptions[stringInfo.Key()] = append(descriptions[stringInfo.Key()], stringInfo.Description)
+					descriptionsMutex.Unlock()
+				}
 			}
 			return nil
 		})
@@ -94,7 +102,9 @@ func (ms *MergeStrings) combineStringInfosPerDirectory(directory string) error {
 	}
 
 	combinedMap.Range(func(key interface{}, val interface{}) bool {
-		ms.I18nStringInfos = append(ms.I18nStringInfos, val.(common.I18nStringInfo))
+		stringInfo := val.(common.I18nStringInfo)
+		stringInfo.Description = mergeDescriptions(descriptions[stringInfo.Key()])
+		ms.I18nStringInfos = append(ms.I18nStringInfos, stringInfo)
 		return true
 	})
 	sort.Sort(ms)
@@ -146,7 +156,10 @@ func (ms *MergeStrings) Len() int {
 }
 
 func (ms *MergeStrings) Less(i, j int) bool {
-	return ms.I18nStringInfos[i].ID < ms.I18nStringInfos[j].ID
+	if ms.I18nStringInfos[i].ID != ms.I18nStringInfos[j].ID {
+		return ms.I18nStringInfos[i].ID < ms.I18nStringInfos[j].ID
+	}
+	return ms.I18nStringInfos[i].Context < ms.I18nStringInfos[j].Context
 }
 
 func (ms *MergeStrings) Swap(i, j int) {
@@ -154,3 +167,20 @@ func (ms *MergeStrings) Swap(i, j int) {
 	ms.I18nStringInfos[i] = ms.I18nStringInfos[j]
 	ms.I18nStringInfos[j] = tmpI18nStringInfo
 }
+
+// mergeDescriptions keeps each distinct description once, in a stable order since files are loaded concurrently
+func mergeDescriptions(descriptions []string) string {
+	var merged []string
+	seen := make(map[string]bool)
+	for _, description := range descriptions {
+		for _, line := range strings.Split(description, "\n") {
+			if !seen[line] {
+				seen[line] = true
+				merged = append(merged, line)
+			}
+		}
+	}
+	sort.Strings(merged)
+
+	return strings.Join(merged, "\n")
+}
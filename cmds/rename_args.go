@@ -0,0 +1,369 @@
+package cmds
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+
+	"go/ast"
+	"go/parser"
+	"go/token"
+
+	"github.com/Liam-Williams/i18n4go/common"
+)
+
+// RenameArgs renames the positional args of the templated strings, e.g., "{{.Arg0}}", after the values the T() and TC()
+// calls give them, the same way in the code and in every language file
+type RenameArgs struct {
+	options common.Options
+
+	Directory string
+
+	// the new names of the args of each message, by message key, the names of the arg names file first
+	Renames map[string]map[string]string
+
+	// the T() calls and the strings of the language files whose args are renamed
+	TotalRenamedStrings int
+}
+
+func NewRenameArgs(options common.Options) RenameArgs {
+	directory := options.DirnameFlag
+	if directory == "" {
+		directory = "."
+	}
+
+	return RenameArgs{
+		options:   options,
+		Directory: directory,
+		Renames:   make(map[string]map[string]string),
+	}
+}
+
+func (ra *RenameArgs) Options() common.Options {
+	return ra.options
+}
+
+func (ra *RenameArgs) Println(a ...interface{}) (int, error) {
+	if ra.options.VerboseFlag {
+		return fmt.Println(a...)
+	}
+
+	return 0, nil
+}
+
+func (ra *RenameArgs) Printf(msg string, a ...interface{}) (int, error) {
+	if ra.options.VerboseFlag {
+		return fmt.Printf(msg, a...)
+	}
+
+	return 0, nil
+}
+
+func (ra *RenameArgs) Run() error {
+	if ra.options.ArgNamesFilenameFlag != "" {
+		err := ra.loadArgNames(ra.options.ArgNamesFilenameFlag)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, fileName := range getGoFiles(ra.Directory) {
+		err := ra.renameFileArgs(fileName)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, languageFiles := range findTranslationFiles(ra.Directory) {
+		for _, languageFile := range languageFiles {
+			err := ra.renameLanguageFileArgs(languageFile)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	ra.Println("Total renamed strings:", ra.TotalRenamedStrings)
+	return nil
+}
+
+// loadArgNames loads the JSON file mapping message keys to the new names of their args, e.g.,
+// {"Deleting {{.Arg0}}": {"Arg0": "AppName"}}, the args it does not name are named after their values
+func (ra *RenameArgs) loadArgNames(fileName string) error {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return fmt.Errorf("i18n4go: could not read the arg names file %s: %w", fileName, err)
+	}
+
+	var argNames map[string]map[string]string
+	err = json.Unmarshal(content, &argNames)
+	if err != nil {
+		return fmt.Errorf("i18n4go: could not parse the arg names file %s: %w", fileName, err)
+	}
+
+	for key, renames := range argNames {
+		for oldName, newName := range renames {
+			if !common.IsTemplateArgName(newName) {
+				return fmt.Errorf("i18n4go: invalid name %q for arg %s of %q in the arg names file %s", newName, oldName, key, fileName)
+			}
+		}
+		ra.Renames[key] = renames
+	}
+
+	return nil
+}
+
+func (ra *RenameArgs) renameFileArgs(fileName string) error {
+	src, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	fileSet := token.NewFileSet()
+	astFile, err := parser.ParseFile(fileSet, fileName, src, parser.ParseComments|parser.AllErrors)
+	if err != nil {
+		ra.Println(err)
+		return err
+	}
+
+	editor := newSourceEditor(fileSet, astFile, src)
+	renamed := false
+	ast.Inspect(astFile, func(n ast.Node) bool {
+		if callExpr, ok := n.(*ast.CallExpr); ok && ra.renameCallArgs(callExpr) {
+			ra.TotalRenamedStrings++
+			renamed = true
+		}
+		return true
+	})
+
+	if !renamed {
+		return nil
+	}
+
+	ra.Println("i18n4go: renaming the args of the templated strings of file:", fileName)
+	if ra.options.DryRunFlag {
+		return nil
+	}
+
+	newSrc, err := editor.Source(astFile)
+	if err != nil {
+		return err
+	}
+
+	fileInfo, err := os.Stat(fileName)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, newSrc, fileInfo.Mode().Perm())
+}
+
+// renameCallArgs renames the positional args of the string of a T() or TC() call and the keys of its args map, it is
+// true when the call changes
+func (ra *RenameArgs) renameCallArgs(callExpr *ast.CallExpr) bool {
+	messageIndex, context, ok := ra.translationCall(callExpr)
+	if !ok || len(callExpr.Args) <= messageIndex {
+		return false
+	}
+
+	messageLit, ok := callExpr.Args[messageIndex].(*ast.BasicLit)
+	if !ok || messageLit.Kind != token.STRING {
+		return false
+	}
+	message, err := strconv.Unquote(messageLit.Value)
+	if err != nil {
+		return false
+	}
+
+	var argsLit *ast.CompositeLit
+	if len(callExpr.Args) > messageIndex+1 {
+		argsLit, _ = callExpr.Args[messageIndex+1].(*ast.CompositeLit)
+	}
+
+	key := common.MessageKey(context, message)
+	renames := ra.messageRenames(key, message, argsLit)
+	if len(renames) == 0 {
+		return false
+	}
+
+	changed := false
+	if newValue := common.RenameTemplateArgs(messageLit.Value, renames); newValue != messageLit.Value {
+		messageLit.Value = newValue
+		changed = true
+	}
+
+	if argsLit != nil {
+		for _, elt := range argsLit.Elts {
+			keyValueExpr, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			keyLit, ok := keyValueExpr.Key.(*ast.BasicLit)
+			if !ok || keyLit.Kind != token.STRING {
+				continue
+			}
+
+			argName, err := strconv.Unquote(keyLit.Value)
+			if newName, ok := renames[argName]; ok && err == nil {
+				keyLit.Value = strconv.Quote(newName)
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+// translationCall returns the index of the message arg of a T() or TC() call, unqualified or qualified with -q, and the
+// context of a TC() call
+func (ra *RenameArgs) translationCall(callExpr *ast.CallExpr) (int, string, bool) {
+	funName := ""
+	switch fun := callExpr.Fun.(type) {
+	case *ast.Ident:
+		funName = fun.Name
+	case *ast.SelectorExpr:
+		if ident, ok := fun.X.(*ast.Ident); ok && ident.Name == ra.options.QualifierFlag {
+			funName = fun.Sel.Name
+		}
+	}
+
+	switch {
+	case funName == "T" && len(callExpr.Args) > 0:
+		return 0, "", true
+	case funName == common.CONTEXT_FUNC && common.IsContextCall(callExpr):
+		contextLit, ok := callExpr.Args[0].(*ast.BasicLit)
+		if !ok || contextLit.Kind != token.STRING {
+			return 0, "", false
+		}
+		context, err := strconv.Unquote(contextLit.Value)
+		if err != nil {
+			return 0, "", false
+		}
+		return 1, context, true
+	}
+
+	return 0, "", false
+}
+
+// messageRenames returns the new names of the positional args of the message of key, the names of the arg names file,
+// or of an earlier call of the same message, or else the names of the values of argsLit, unique among the args of the message
+func (ra *RenameArgs) messageRenames(key, message string, argsLit *ast.CompositeLit) map[string]string {
+	renames, ok := ra.Renames[key]
+	if ok && len(renames) > 0 && !hasUnnamedArgs(argsLit, renames) {
+		return renames
+	}
+
+	newRenames := make(map[string]string)
+	taken := make(map[string]bool)
+	for oldName, newName := range renames {
+		newRenames[oldName] = newName
+		taken[newName] = true
+	}
+	for _, argName := range common.GetTemplatedStringArgs(message) {
+		if !common.IsPositionalArgName(argName) {
+			taken[argName] = true
+		}
+	}
+
+	if argsLit != nil {
+		for _, elt := range argsLit.Elts {
+			keyValueExpr, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			keyLit, ok := keyValueExpr.Key.(*ast.BasicLit)
+			if !ok || keyLit.Kind != token.STRING {
+				continue
+			}
+			argName, err := strconv.Unquote(keyLit.Value)
+			if err != nil || !common.IsPositionalArgName(argName) {
+				continue
+			}
+			if _, ok := newRenames[argName]; ok {
+				continue
+			}
+
+			if name := common.TemplateArgName(keyValueExpr.Value); name != "" {
+				newRenames[argName] = common.UniqueTemplateArgName(name, taken)
+			}
+		}
+	}
+
+	if len(newRenames) > 0 {
+		ra.Renames[key] = newRenames
+		ra.Println("i18n4go: renaming the args of:", message, "to:", renamesString(newRenames))
+	}
+
+	return newRenames
+}
+
+// hasUnnamedArgs is true when argsLit has positional args that renames does not name
+func hasUnnamedArgs(argsLit *ast.CompositeLit, renames map[string]string) bool {
+	if argsLit == nil {
+		return false
+	}
+
+	for _, elt := range argsLit.Elts {
+		if keyValueExpr, ok := elt.(*ast.KeyValueExpr); ok {
+			if keyLit, ok := keyValueExpr.Key.(*ast.BasicLit); ok {
+				argName, err := strconv.Unquote(keyLit.Value)
+				if _, named := renames[argName]; err == nil && common.IsPositionalArgName(argName) && !named {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// renameLanguageFileArgs renames the args of the IDs and of the translations of the language file the way the code does
+func (ra *RenameArgs) renameLanguageFileArgs(fileName string) error {
+	i18nStringInfos, err := common.LoadI18nStringInfos(fileName)
+	if err != nil {
+		return err
+	}
+
+	renamed := false
+	for i, i18nStringInfo := range i18nStringInfos {
+		renames, ok := ra.Renames[i18nStringInfo.Key()]
+		if !ok {
+			continue
+		}
+
+		i18nStringInfos[i].ID = common.RenameTemplateArgs(i18nStringInfo.ID, renames)
+		i18nStringInfos[i].Translation = common.RenameTemplateArgs(i18nStringInfo.Translation, renames)
+		if i18nStringInfos[i] != i18nStringInfo {
+			ra.TotalRenamedStrings++
+			renamed = true
+		}
+	}
+
+	if !renamed {
+		return nil
+	}
+
+	ra.Println("i18n4go: renaming the args of the templated strings of language file:", fileName)
+	return common.SaveI18nStringInfos(ra, ra.Options(), i18nStringInfos, fileName)
+}
+
+func renamesString(renames map[string]string) string {
+	var oldNames []string
+	for oldName := range renames {
+		oldNames = append(oldNames, oldName)
+	}
+	sort.Strings(oldNames)
+
+	s := ""
+	for i, oldName := range oldNames {
+		if i > 0 {
+			s += ", "
+		}
+		s += oldName + " -> " + renames[oldName]
+	}
+
+	return s
+}
@@ -0,0 +1,256 @@
+package cmds
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go/ast"
+	"go/token"
+
+	"github.com/Liam-Williams/i18n4go/common"
+)
+
+// the translation runtimes the rewritten packages can call, chosen with --backend
+const (
+	GO_I18N_BACKEND    = "go-i18n"
+	GO_I18N_V2_BACKEND = "go-i18n-v2"
+	X_TEXT_BACKEND     = "x-text"
+)
+
+const (
+	GO_I18N_V2_IMPORT_PATH = "github.com/nicksnyder/go-i18n/v2/i18n"
+	X_TEXT_IMPORT_PATH     = "golang.org/x/text/message"
+
+	GO_I18N_V2_EMBED_INIT_CODE_SNIPPET = `package __PACKAGE__NAME__
+
+import (
+	"embed"
+	"path/filepath"
+
+	i18n4go "github.com/Liam-Williams/i18n4go/i18n"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+//go:embed __EMBED_DIRNAME__
+var i18nResources embed.FS
+
+var Localizer *i18n.Localizer
+
+func init() {
+	translations, err := i18n4go.Translations(i18nResources, __FULL_IMPORT_PATH__, "__EMBED_DIRNAME__")
+	if err != nil {
+		panic(err)
+	}
+
+	bundle := i18n.NewBundle(language.Make(i18n4go.DEFAULT_LOCALE))
+	for locale, messages := range translations {
+		for id, translation := range messages {
+			bundle.AddMessages(language.Make(locale), &i18n.Message{ID: i18n4go.ReadableMessageID(id), Other: translation})
+		}
+	}
+	Localizer = i18n.NewLocalizer(bundle, i18n4go.UserLocale())
+}`
+
+	X_TEXT_EMBED_INIT_CODE_SNIPPET = `package __PACKAGE__NAME__
+
+import (
+	"embed"
+	"path/filepath"
+
+	i18n "github.com/Liam-Williams/i18n4go/i18n"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+//go:embed __EMBED_DIRNAME__
+var i18nResources embed.FS
+
+var Printer *message.Printer
+
+func init() {
+	translations, err := i18n.Translations(i18nResources, __FULL_IMPORT_PATH__, "__EMBED_DIRNAME__")
+	if err != nil {
+		panic(err)
+	}
+
+	builder := catalog.NewBuilder(catalog.Fallback(language.Make(i18n.DEFAULT_LOCALE)))
+	for locale, messages := range translations {
+		for id, translation := range messages {
+			builder.SetString(language.Make(locale), id, translation)
+		}
+	}
+	Printer = message.NewPrinter(language.Make(i18n.UserLocale()), message.Catalog(builder))
+}`
+)
+
+// rewriteBackend is the translation runtime the rewritten strings are translated by, it shapes the calls wrapping the
+// strings, encodes their args and generates the i18n_init.go declaring what the calls use
+type rewriteBackend interface {
+	// funcNames is the func the strings are translated with, e.g., T, or the names of the selector calling it
+	funcNames() []string
+
+	// formatsArgs is true when the messages are printf formats, their args are then passed in order rather than by name
+	formatsArgs() bool
+
+	// translateCall is the call translating message
+	translateCall(rp *rewritePackage, message translatedMessage) ast.Expr
+
+	// initCodeSnippet is the default i18n_init.go of a package, which loads the translations from the locale dir it
+	// embeds when embedded is true. It is empty when the backend can only load embedded translations
+	initCodeSnippet(embedded bool) string
+}
+
+// translatedMessage is a message a rewritten string is translated with, the args of a templated message are the map of
+// its arg names to their values, those of a printf format are its values in order
+type translatedMessage struct {
+	id      ast.Expr
+	context string
+
+	args       *ast.CompositeLit
+	formatArgs []ast.Expr
+}
+
+func newRewriteBackend(name string) (rewriteBackend, error) {
+	switch name {
+	case "", GO_I18N_BACKEND:
+		return goI18nBackend{}, nil
+	case GO_I18N_V2_BACKEND:
+		return goI18nV2Backend{}, nil
+	case X_TEXT_BACKEND:
+		return xTextBackend{}, nil
+	}
+
+	return nil, fmt.Errorf("i18n4go: unknown backend %s, expected %s, %s or %s", name, GO_I18N_BACKEND, GO_I18N_V2_BACKEND, X_TEXT_BACKEND)
+}
+
+// goI18nBackend translates with go-i18n v1, T(id, map[string]interface{}{...}) and TC(context, id)
+type goI18nBackend struct{}
+
+func (goI18nBackend) funcNames() []string {
+	return []string{"T"}
+}
+
+func (goI18nBackend) formatsArgs() bool {
+	return false
+}
+
+func (goI18nBackend) translateCall(rp *rewritePackage, message translatedMessage) ast.Expr {
+	if message.context != "" {
+		contextLit := &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(message.context)}
+		return &ast.CallExpr{Fun: rp.tFunc(common.CONTEXT_FUNC), Args: []ast.Expr{contextLit, message.id}}
+	}
+
+	args := []ast.Expr{message.id}
+	if message.args != nil {
+		args = append(args, message.args)
+	}
+
+	return &ast.CallExpr{Fun: rp.tFunc("T"), Args: args}
+}
+
+func (goI18nBackend) initCodeSnippet(embedded bool) string {
+	if embedded {
+		return EMBED_INIT_CODE_SNIPPET
+	}
+
+	return INIT_CODE_SNIPPET
+}
+
+// goI18nV2Backend translates with go-i18n v2, Localizer.MustLocalize(&i18n.LocalizeConfig{MessageID: id, TemplateData: ...}),
+// go-i18n v2 has no contexts, the ID of a message in a context is prefixed with its context and a dot, e.g., menu.Open
+type goI18nV2Backend struct{}
+
+func (goI18nV2Backend) funcNames() []string {
+	return []string{"Localizer", "MustLocalize"}
+}
+
+func (goI18nV2Backend) formatsArgs() bool {
+	return false
+}
+
+func (b goI18nV2Backend) translateCall(rp *rewritePackage, message translatedMessage) ast.Expr {
+	elts := []ast.Expr{&ast.KeyValueExpr{Key: &ast.Ident{Name: "MessageID"}, Value: contextMessageID(message, common.READABLE_CONTEXT_SEPARATOR)}}
+	if message.args != nil {
+		elts = append(elts, &ast.KeyValueExpr{Key: &ast.Ident{Name: "TemplateData"}, Value: message.args})
+	}
+
+	config := &ast.CompositeLit{Type: rp.packageFunc(GO_I18N_V2_IMPORT_PATH, "LocalizeConfig"), Elts: elts}
+	return &ast.CallExpr{Fun: rp.tFunc(b.funcNames()...), Args: []ast.Expr{&ast.UnaryExpr{Op: token.AND, X: config}}}
+}
+
+func (goI18nV2Backend) initCodeSnippet(embedded bool) string {
+	if embedded {
+		return GO_I18N_V2_EMBED_INIT_CODE_SNIPPET
+	}
+
+	return ""
+}
+
+// xTextBackend translates with golang.org/x/text/message, Printer.Sprintf(format, args...), the messages are printf
+// formats. A message in a context is looked up by its message key, with the message as fallback,
+// Printer.Sprintf(message.Key("menu\x04Open", "Open"))
+type xTextBackend struct{}
+
+func (xTextBackend) funcNames() []string {
+	return []string{"Printer", "Sprintf"}
+}
+
+func (xTextBackend) formatsArgs() bool {
+	return true
+}
+
+func (b xTextBackend) translateCall(rp *rewritePackage, message translatedMessage) ast.Expr {
+	format := message.id
+	if message.context != "" {
+		format = &ast.CallExpr{Fun: rp.packageFunc(X_TEXT_IMPORT_PATH, "Key"), Args: []ast.Expr{contextMessageID(message, common.CONTEXT_SEPARATOR), message.id}}
+	}
+
+	args := append([]ast.Expr{format}, message.formatArgs...)
+	return &ast.CallExpr{Fun: rp.tFunc(b.funcNames()...), Args: args}
+}
+
+func (xTextBackend) initCodeSnippet(embedded bool) string {
+	if embedded {
+		return X_TEXT_EMBED_INIT_CODE_SNIPPET
+	}
+
+	return ""
+}
+
+// contextMessageID is the ID of message prefixed with its context and separator, the way the runtime keys the
+// translations in a context
+func contextMessageID(message translatedMessage, separator string) ast.Expr {
+	if message.context == "" {
+		return message.id
+	}
+
+	id := message.id
+	if basicLit, ok := id.(*ast.BasicLit); ok {
+		if value, err := strconv.Unquote(basicLit.Value); err == nil {
+			return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(message.context + separator + value)}
+		}
+	}
+
+	contextLit := &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(message.context + separator)}
+	return &ast.BinaryExpr{X: contextLit, Op: token.ADD, Y: id}
+}
+
+// templateToFormat turns the templated string value into a printf format, each arg becomes a %v verb of the value of its
+// call at the index of templateArgs, the % of the string are escaped
+func templateToFormat(value string, templateArgs []common.TemplateArg) string {
+	format := escapeFormat(value)
+	for _, templateArg := range templateArgs {
+		verb := fmt.Sprintf("%%[%d]v", templateArg.ArgIndexes[0]+1)
+		format = strings.Replace(format, "{{."+templateArg.Name+"}}", verb, -1)
+	}
+
+	return format
+}
+
+// escapeFormat escapes the % of value so that formatting it with printf gives it back
+func escapeFormat(value string) string {
+	return strings.Replace(value, "%", "%%", -1)
+}
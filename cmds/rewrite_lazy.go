@@ -0,0 +1,552 @@
+package cmds
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/Liam-Williams/i18n4go/common"
+)
+
+// lazyDecl is a package level declaration whose specs, constants or variables initialized before init() assigns T(),
+// are turned into funcs translating their strings when they are called, the uses of their names become calls
+type lazyDecl struct {
+	genDecl *ast.GenDecl
+	span    sourceSpan
+
+	specs       []*ast.ValueSpec
+	specSpans   map[*ast.ValueSpec]sourceSpan
+	resultTypes map[*ast.ValueSpec][]string
+}
+
+// packageObjects is the type information of a package, the constants and variables its identifiers declare and use,
+// found by the position of the identifiers since the files being rewritten are parsed again
+type packageObjects struct {
+	path string
+
+	defs       map[objectPos]types.Object
+	useObjects map[objectPos]types.Object
+	uses       map[types.Object][]objectUse
+}
+
+// objectPos is the position of an identifier in the files of a package
+type objectPos struct {
+	fileName string
+	offset   int
+}
+
+type objectUse struct {
+	objectPos
+
+	// the use is run while the package is initialized, by a package level variable or by init()
+	initializing bool
+	// the use is part of a constant expression
+	inConst bool
+	// the use is assigned, incremented, has its address taken or is deleted from
+	assigned bool
+}
+
+// packageDeclTFunc wraps the strings of the package level constants, and of the variables initialized before T() is,
+// with T() and turns the specs wrapping strings into funcs, a spec that cannot become a func is reported and its
+// strings are left untranslated, except in its func literals
+func (rp *rewritePackage) packageDeclTFunc(genDecl *ast.GenDecl) {
+	decl := &lazyDecl{
+		genDecl:     genDecl,
+		span:        sourceSpan{pos: genDecl.Pos(), end: genDecl.End()},
+		specSpans:   make(map[*ast.ValueSpec]sourceSpan),
+		resultTypes: make(map[*ast.ValueSpec][]string),
+	}
+
+	implicitValues := false
+	for _, spec := range genDecl.Specs {
+		if valueSpec := spec.(*ast.ValueSpec); genDecl.Tok == token.CONST && len(valueSpec.Values) == 0 {
+			implicitValues = true
+		}
+	}
+
+	for _, spec := range genDecl.Specs {
+		valueSpec := spec.(*ast.ValueSpec)
+		span := rp.specSpan(genDecl, valueSpec)
+
+		resultTypes, ident, reason := rp.lazyResultTypes(genDecl.Tok, valueSpec, implicitValues)
+		if reason != "" {
+			if rp.hasTranslatableStrings(valueSpec) {
+				rp.reportUnfixable(genDecl.Tok, ident, reason)
+			}
+			rp.inspectFuncLits(valueSpec)
+			continue
+		}
+
+		rp.inspectTFunc(valueSpec)
+		if rp.wrapsEagerly(valueSpec) {
+			decl.specs = append(decl.specs, valueSpec)
+			decl.specSpans[valueSpec] = span
+			decl.resultTypes[valueSpec] = resultTypes
+			for _, name := range valueSpec.Names {
+				rp.lazyObjects[rp.definedObject(name)] = true
+			}
+		}
+	}
+
+	if len(decl.specs) > 0 {
+		rp.lazyDecls = append(rp.lazyDecls, decl)
+	}
+}
+
+// localConstTFunc wraps the strings of a constant declared in a func with T() and declares it a variable, the declaration
+// is reported and its strings left untranslated when one of its constants cannot become a variable
+func (rp *rewritePackage) localConstTFunc(genDecl *ast.GenDecl) {
+	for _, spec := range genDecl.Specs {
+		valueSpec := spec.(*ast.ValueSpec)
+		for _, name := range valueSpec.Names {
+			reason := ""
+			if len(valueSpec.Values) != len(valueSpec.Names) {
+				reason = "it has the implicit value of a constant block"
+			} else {
+				reason = rp.lazyReason(token.CONST, name, true)
+			}
+
+			if reason != "" {
+				if rp.hasTranslatableStrings(genDecl) {
+					rp.reportUnfixable(token.CONST, name, reason)
+				}
+				return
+			}
+		}
+	}
+
+	tokEnd := genDecl.TokPos + token.Pos(len(genDecl.Tok.String()))
+	rp.inspectTFunc(genDecl)
+	if rp.wrapsEagerly(genDecl) {
+		rp.editor.rewriteSource(genDecl.TokPos, tokEnd, token.VAR.String())
+	}
+}
+
+// lazyResultTypes returns the types of the funcs the names of valueSpec become, or the name that cannot become a func and why
+func (rp *rewritePackage) lazyResultTypes(tok token.Token, valueSpec *ast.ValueSpec, implicitValues bool) ([]string, *ast.Ident, string) {
+	var resultTypes []string
+	for _, name := range valueSpec.Names {
+		switch {
+		case tok == token.CONST && implicitValues:
+			return nil, name, "it is declared in a constant block with implicit values"
+		case len(valueSpec.Values) != len(valueSpec.Names):
+			return nil, name, "it is not declared with a value of its own"
+		}
+
+		if reason := rp.lazyReason(tok, name, false); reason != "" {
+			return nil, name, reason
+		}
+
+		resultType, ok := rp.typeName(types.Default(rp.definedObject(name).Type()))
+		if !ok {
+			return nil, name, "its type " + resultType + " cannot be named in the file"
+		}
+		resultTypes = append(resultTypes, resultType)
+	}
+
+	return resultTypes, nil, ""
+}
+
+// lazyReason is why the constant or variable declared by ident cannot be turned into a func, or into a variable when it
+// is local, or "" when it can
+func (rp *rewritePackage) lazyReason(tok token.Token, ident *ast.Ident, local bool) string {
+	objects := rp.loadPackageObjects()
+	if objects == nil {
+		return "its package could not be type checked"
+	}
+
+	obj := rp.definedObject(ident)
+	switch {
+	case obj == nil:
+		return "it has no type information"
+	case ident.Name == "_":
+		return "it is blank"
+	case !local && obj.Exported():
+		return "it is exported, other packages may use it"
+	}
+
+	if basic, ok := types.Default(obj.Type()).(*types.Basic); tok == token.CONST && (!ok || basic.Kind() != types.String) {
+		return "its type " + obj.Type().String() + " is not string"
+	}
+
+	uses := objects.uses[obj]
+	if local && len(uses) == 0 {
+		return "it is not used"
+	}
+
+	fileName := filepath.Base(rp.fileName)
+	for _, use := range uses {
+		switch {
+		case use.fileName != fileName:
+			return "it is used in " + use.fileName
+		case use.inConst:
+			return "it is used in a constant expression"
+		case use.initializing && !local && !rp.qualified():
+			return "it is used while the package is initialized"
+		case use.assigned:
+			return "it is assigned"
+		}
+	}
+
+	return ""
+}
+
+// rewriteLazyDecls turns the uses of the lazy constants and variables into calls and their specs into funcs, a
+// declaration is replaced by the funcs of its specs, or followed by them when it keeps other specs
+func (rp *rewritePackage) rewriteLazyDecls(astFile *ast.File) {
+	if len(rp.lazyDecls) == 0 {
+		return
+	}
+
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok && rp.editor.original[ident] {
+			if obj := rp.loadPackageObjects().useObjects[rp.objectPos(ident)]; obj != nil && rp.lazyObjects[obj] {
+				rp.editor.rewrite(ident, ident.Pos(), ident.End(), ident.Name+"()")
+			}
+		}
+		return true
+	})
+
+	for _, decl := range rp.lazyDecls {
+		var funcs, names []string
+		for _, valueSpec := range decl.specs {
+			funcs = append(funcs, rp.lazyFuncs(decl, valueSpec)...)
+			for _, name := range valueSpec.Names {
+				names = append(names, name.Name)
+			}
+		}
+		rp.Println("i18n4go: turning into funcs translating their strings when they are called:", strings.Join(names, ", "))
+
+		if len(decl.specs) == len(decl.genDecl.Specs) {
+			rp.editor.rewrite(decl.genDecl, decl.span.pos, decl.span.end, strings.Join(funcs, "\n\n"))
+			continue
+		}
+
+		for _, valueSpec := range decl.specs {
+			rp.editor.rewrite(valueSpec, decl.specSpans[valueSpec].pos, decl.specSpans[valueSpec].end, "")
+		}
+		rp.editor.rewriteSource(decl.span.end, decl.span.end, "\n\n"+strings.Join(funcs, "\n\n"))
+	}
+}
+
+// lazyFuncs returns the funcs returning the values of the names of valueSpec, the doc and the comment of a spec of a
+// parenthesized declaration go along
+func (rp *rewritePackage) lazyFuncs(decl *lazyDecl, valueSpec *ast.ValueSpec) []string {
+	doc, comment := "", ""
+	if decl.genDecl.Lparen.IsValid() {
+		if valueSpec.Doc != nil {
+			doc = rp.editor.sourceText(valueSpec.Doc.Pos(), valueSpec.Doc.End()) + "\n"
+		}
+		if valueSpec.Comment != nil {
+			comment = " " + rp.editor.sourceText(valueSpec.Comment.Pos(), valueSpec.Comment.End())
+		}
+	}
+
+	var funcs []string
+	for i, name := range valueSpec.Names {
+		funcText := "func " + name.Name + "() " + decl.resultTypes[valueSpec][i] + " {\n\treturn " + rp.editor.text(valueSpec.Values[i]) + comment + "\n}"
+		if i == 0 {
+			funcText = doc + funcText
+		}
+		funcs = append(funcs, funcText)
+	}
+
+	return funcs
+}
+
+// specSpan is the source of valueSpec, the whole lines of its doc and comment when it is part of a parenthesized
+// declaration, it is taken before its values are wrapped with T()
+func (rp *rewritePackage) specSpan(genDecl *ast.GenDecl, valueSpec *ast.ValueSpec) sourceSpan {
+	if !genDecl.Lparen.IsValid() {
+		return sourceSpan{pos: valueSpec.Pos(), end: valueSpec.End()}
+	}
+
+	pos, end := valueSpec.Pos(), valueSpec.End()
+	if valueSpec.Doc != nil {
+		pos = valueSpec.Doc.Pos()
+	}
+	if valueSpec.Comment != nil {
+		end = valueSpec.Comment.End()
+	}
+
+	return rp.editor.lineSpan(pos, end)
+}
+
+// inspectFuncLits wraps the strings of the func literals of node with T(), they are run after T() is assigned
+func (rp *rewritePackage) inspectFuncLits(node ast.Node) {
+	ast.Inspect(node, func(node ast.Node) bool {
+		if funcLit, ok := node.(*ast.FuncLit); ok {
+			rp.inspectTFunc(funcLit.Body)
+			return false
+		}
+		return true
+	})
+}
+
+// wrapsEagerly is true when a T() call was inserted in node outside of its func literals
+func (rp *rewritePackage) wrapsEagerly(node ast.Node) bool {
+	wraps := false
+	ast.Inspect(node, func(node ast.Node) bool {
+		if _, ok := node.(*ast.FuncLit); ok || wraps {
+			return false
+		}
+		if _, ok := rp.editor.replaced[node]; ok {
+			wraps = true
+		}
+		return !wraps
+	})
+
+	return wraps
+}
+
+// hasTranslatableStrings is true when node has strings outside of its func literals that would be wrapped with T()
+func (rp *rewritePackage) hasTranslatableStrings(node ast.Node) bool {
+	translatable := false
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.FuncLit, *ast.StructType:
+			return false
+		case *ast.BasicLit:
+			value, err := strconv.Unquote(x.Value)
+			if x.Kind != token.STRING || err != nil {
+				return false
+			}
+
+			_, ok := rp.ExtractedStrings[common.MessageKey(rp.directives.Context(x), value)]
+			if rp.directives.Translates(x) || !rp.directives.Ignores(x) && (ok || rp.ExtractedStrings == nil) {
+				translatable = true
+			}
+		}
+		return !translatable
+	})
+
+	return translatable
+}
+
+func (rp *rewritePackage) reportUnfixable(tok token.Token, ident *ast.Ident, reason string) {
+	rp.TotalUnfixable++
+
+	kind := "variable"
+	if tok == token.CONST {
+		kind = "constant"
+	}
+
+	position := rp.editor.file.Position(ident.Pos())
+	fmt.Fprintf(rp.output(), "i18n4go: WARNING unfixable %s %s at %s:%d:%d, its strings are not translated: %s\n", kind, ident.Name, rp.fileName, position.Line, position.Column, reason)
+}
+
+// typeName is the name of typ in the file being rewritten, false when the file does not import a package it refers to
+func (rp *rewritePackage) typeName(typ types.Type) (string, bool) {
+	named := true
+	typeName := types.TypeString(typ, func(pkg *types.Package) string {
+		if pkg.Path() == rp.loadPackageObjects().path {
+			return ""
+		}
+
+		name, ok := rp.fileImports[pkg.Path()]
+		if !ok || name == "." || name == "_" {
+			named = false
+			return pkg.Name()
+		}
+		if name == "" {
+			return pkg.Name()
+		}
+		return name
+	})
+
+	return typeName, named
+}
+
+// fileImportNames returns the names the imports of astFile are given, by import path, "" for an unnamed import
+func fileImportNames(astFile *ast.File) map[string]string {
+	importNames := make(map[string]string)
+	for _, importSpec := range astFile.Imports {
+		importPath, err := strconv.Unquote(importSpec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		importNames[importPath] = ""
+		if importSpec.Name != nil {
+			importNames[importPath] = importSpec.Name.Name
+		}
+	}
+
+	return importNames
+}
+
+func (rp *rewritePackage) definedObject(ident *ast.Ident) types.Object {
+	objects := rp.loadPackageObjects()
+	if objects == nil {
+		return nil
+	}
+
+	return objects.defs[rp.objectPos(ident)]
+}
+
+func (rp *rewritePackage) objectPos(ident *ast.Ident) objectPos {
+	return objectPos{fileName: filepath.Base(rp.fileName), offset: rp.editor.file.Offset(ident.Pos())}
+}
+
+func (rp *rewritePackage) packageDir() string {
+	dirName, err := filepath.Abs(filepath.Dir(rp.fileName))
+	if err != nil {
+		return filepath.Dir(rp.fileName)
+	}
+
+	return dirName
+}
+
+// loadPackageObjects type checks the package of the file being rewritten, once per directory, nil when it cannot be
+func (rp *rewritePackage) loadPackageObjects() *packageObjects {
+	dirName := rp.packageDir()
+	if objects, ok := rp.packageObjects[dirName]; ok {
+		return objects
+	}
+
+	typedPackage, err := rp.loader().LoadDir(dirName)
+	if err != nil {
+		rp.Println("i18n4go: WARNING could not type check the package in dir:", dirName, err.Error())
+		rp.packageObjects[dirName] = nil
+		return nil
+	}
+	if len(typedPackage.TypeErrors) > 0 {
+		rp.Printf("i18n4go: WARNING package %s has %d type errors\n", typedPackage.Name, len(typedPackage.TypeErrors))
+	}
+
+	objects := newPackageObjects(rp.loader().Fset, typedPackage)
+	rp.packageObjects[dirName] = objects
+	return objects
+}
+
+func (rp *rewritePackage) loader() *common.PackageLoader {
+	if rp.packageLoader == nil {
+		rp.packageLoader = common.NewPackageLoader(token.NewFileSet(), rp.options.TagsFlag)
+	}
+
+	return rp.packageLoader
+}
+
+func newPackageObjects(fset *token.FileSet, typedPackage *common.TypedPackage) *packageObjects {
+	objects := &packageObjects{
+		path:       typedPackage.Name,
+		defs:       make(map[objectPos]types.Object),
+		useObjects: make(map[objectPos]types.Object),
+		uses:       make(map[types.Object][]objectUse),
+	}
+
+	if typedPackage.Types != nil {
+		objects.path = typedPackage.Types.Path()
+	}
+
+	for ident, obj := range typedPackage.Info.Defs {
+		if obj != nil {
+			objects.defs[identPos(fset, ident)] = obj
+		}
+	}
+
+	for _, astFile := range typedPackage.Files {
+		assigned := assignedIdents(astFile, typedPackage.Info)
+		for _, decl := range astFile.Decls {
+			switch x := decl.(type) {
+			case *ast.FuncDecl:
+				objects.addUses(fset, typedPackage.Info, x, assigned, x.Recv == nil && x.Name.Name == "init", false)
+			case *ast.GenDecl:
+				objects.addUses(fset, typedPackage.Info, x, assigned, x.Tok == token.VAR, x.Tok == token.CONST)
+			}
+		}
+	}
+
+	return objects
+}
+
+// addUses records the uses of the constants and variables in node
+func (objects *packageObjects) addUses(fset *token.FileSet, info *types.Info, node ast.Node, assigned map[*ast.Ident]bool, initializing, inConst bool) {
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.GenDecl:
+			if x.Tok == token.CONST && !inConst {
+				objects.addUses(fset, info, x, assigned, initializing, true)
+				return false
+			}
+		case *ast.Ident:
+			obj := info.Uses[x]
+			switch obj.(type) {
+			case *types.Const, *types.Var:
+				pos := identPos(fset, x)
+				objects.useObjects[pos] = obj
+				objects.uses[obj] = append(objects.uses[obj], objectUse{
+					objectPos:    pos,
+					initializing: initializing,
+					inConst:      inConst,
+					assigned:     assigned[x],
+				})
+			}
+		}
+		return true
+	})
+}
+
+// assignedIdents returns the identifiers of astFile whose value changes, the operands of assignments, increments and
+// decrements, of the & operator and the maps deleted from
+func assignedIdents(astFile *ast.File, info *types.Info) map[*ast.Ident]bool {
+	assigned := make(map[*ast.Ident]bool)
+	assign := func(expr ast.Expr) {
+		for expr != nil {
+			switch x := expr.(type) {
+			case *ast.Ident:
+				assigned[x] = true
+				expr = nil
+			case *ast.ParenExpr:
+				expr = x.X
+			case *ast.IndexExpr:
+				expr = x.X
+			case *ast.SelectorExpr:
+				expr = x.X
+			case *ast.StarExpr:
+				expr = x.X
+			default:
+				expr = nil
+			}
+		}
+	}
+
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range x.Lhs {
+				assign(lhs)
+			}
+		case *ast.IncDecStmt:
+			assign(x.X)
+		case *ast.UnaryExpr:
+			if x.Op == token.AND {
+				assign(x.X)
+			}
+		case *ast.RangeStmt:
+			if x.Tok == token.ASSIGN {
+				assign(x.Key)
+				assign(x.Value)
+			}
+		case *ast.CallExpr:
+			if ident, ok := x.Fun.(*ast.Ident); ok && ident.Name == "delete" && len(x.Args) > 0 {
+				if _, ok := info.Uses[ident].(*types.Builtin); ok {
+					assign(x.Args[0])
+				}
+			}
+		}
+		return true
+	})
+
+	return assigned
+}
+
+func identPos(fset *token.FileSet, ident *ast.Ident) objectPos {
+	position := fset.Position(ident.Pos())
+	return objectPos{fileName: filepath.Base(position.Filename), offset: position.Offset}
+}
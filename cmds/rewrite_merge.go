@@ -0,0 +1,212 @@
+package cmds
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go/ast"
+	"go/token"
+
+	"github.com/Liam-Williams/i18n4go/common"
+)
+
+// concatenationFragment is a run of adjacent constant operands of a concatenation, a fragment of its message
+type concatenationFragment struct {
+	value string
+	lits  []*ast.BasicLit
+}
+
+// mergeConcatenationTFunc wraps a chain of + mixing strings and other expressions with one templated message whose args
+// are the expressions, named after their values, e.g., "Deleted " + name + " from org " + org becomes
+// T("Deleted {{.Name}} from org {{.Org}}", map[string]interface{}{"Name": name, "Org": org}), so that translators can
+// reorder them. It returns nil when the chain is not merged, its fragments are then wrapped on their own
+func (rp *rewritePackage) mergeConcatenationTFunc(binaryExpr *ast.BinaryExpr) ast.Expr {
+	if !rp.options.MergeConcatenationsFlag || rp.concatenationJoins[binaryExpr] {
+		return nil
+	}
+	rp.markConcatenation(binaryExpr)
+
+	operands, _ := common.Concatenation(binaryExpr)
+	folder := common.NewConstFolder(nil)
+
+	message := ""
+	var fragments []concatenationFragment
+	var args []ast.Expr
+	var templateArgs []common.TemplateArg
+	taken := make(map[string]bool)
+	constant := false
+	for _, operand := range operands {
+		value, ok := folder.Fold(operand)
+		if !ok {
+			name := common.TemplateArgName(operand)
+			if name == "" {
+				name = fmt.Sprintf("Arg%d", len(args))
+			}
+			name = common.UniqueTemplateArgName(name, taken)
+
+			message += "{{." + name + "}}"
+			templateArgs = append(templateArgs, common.TemplateArg{Name: name, ArgIndexes: []int{len(args)}})
+			args = append(args, operand)
+			constant = false
+			continue
+		}
+
+		if !constant {
+			fragments = append(fragments, concatenationFragment{})
+		}
+		fragment := &fragments[len(fragments)-1]
+		fragment.value += value
+		ast.Inspect(operand, func(n ast.Node) bool {
+			if basicLit, ok := n.(*ast.BasicLit); ok {
+				fragment.lits = append(fragment.lits, basicLit)
+			}
+			return true
+		})
+		message += value
+		constant = true
+	}
+
+	if len(args) == 0 || !rp.mergesFragments(binaryExpr, fragments) {
+		return nil
+	}
+
+	rp.TotalStrings++
+	if rp.backend.formatsArgs() {
+		message = templateToFormat(message, templateArgs)
+	}
+	rp.addMergedMessage(message, fragments)
+	idLit := &ast.BasicLit{ValuePos: binaryExpr.Pos(), Kind: token.STRING, Value: strconv.Quote(rp.messageID(message, ""))}
+
+	for i, arg := range args {
+		args[i] = rp.wrapArgValue(arg)
+	}
+
+	var tCallExpr ast.Expr
+	if rp.backend.formatsArgs() {
+		tCallExpr = rp.backend.translateCall(rp, translatedMessage{id: idLit, formatArgs: args})
+	} else {
+		var elts []ast.Expr
+		for i, templateArg := range templateArgs {
+			quotedArgName := &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(templateArg.Name)}
+			elts = append(elts, &ast.KeyValueExpr{Key: quotedArgName, Value: args[i]})
+		}
+
+		mapInterfaceType := &ast.InterfaceType{Methods: &ast.FieldList{}}
+		mapType := &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: mapInterfaceType}
+		compositeLit := &ast.CompositeLit{Type: mapType, Elts: elts}
+		tCallExpr = rp.backend.translateCall(rp, translatedMessage{id: idLit, args: compositeLit})
+	}
+
+	return rp.editor.replace(tCallExpr, binaryExpr.Pos(), binaryExpr.End())
+}
+
+// markConcatenation records the joins of the chain of + of binaryExpr but itself, its parts are not merged on their own
+func (rp *rewritePackage) markConcatenation(binaryExpr *ast.BinaryExpr) {
+	_, joins := common.Concatenation(binaryExpr)
+	for _, join := range joins {
+		if join != binaryExpr {
+			rp.concatenationJoins[join] = true
+		}
+	}
+}
+
+// mergesFragments is true when the fragments of the chain binaryExpr are merged into one message: one of them has a word
+// and is translated, none is ignored, translated in a context or templated, and the chain has no comments
+func (rp *rewritePackage) mergesFragments(binaryExpr *ast.BinaryExpr, fragments []concatenationFragment) bool {
+	for _, commentGroup := range rp.astFile.Comments {
+		if commentGroup.Pos() >= binaryExpr.Pos() && commentGroup.End() <= binaryExpr.End() {
+			return false
+		}
+	}
+
+	translated := false
+	for _, fragment := range fragments {
+		if strings.Contains(fragment.value, "{{") {
+			return false
+		}
+
+		for _, basicLit := range fragment.lits {
+			if rp.directives.Ignores(basicLit) || rp.directives.Context(basicLit) != "" {
+				return false
+			}
+			translated = translated || rp.directives.Translates(basicLit)
+		}
+
+		if strings.IndexFunc(fragment.value, unicode.IsLetter) < 0 {
+			continue
+		}
+		if _, ok := rp.ExtractedStrings[fragment.value]; ok || rp.ExtractedStrings == nil {
+			translated = true
+		}
+	}
+
+	return translated
+}
+
+// addMergedMessage adds the message of a merged concatenation to the i18n strings, the fragments it replaces are
+// removed from them at the end of the rewrite, unless they are also translated on their own
+func (rp *rewritePackage) addMergedMessage(message string, fragments []concatenationFragment) {
+	if rp.ExtractedStrings == nil {
+		return
+	}
+
+	if _, ok := rp.ExtractedStrings[message]; !ok {
+		i18nStringInfo := common.I18nStringInfo{ID: rp.idGenerator.ID(rp.funcName, "", message), Translation: message}
+		rp.ExtractedStrings[message] = i18nStringInfo
+		rp.UpdatedExtractedStrings[message] = i18nStringInfo
+		rp.SaveExtractedStrings = true
+	}
+
+	for _, fragment := range fragments {
+		if _, ok := rp.ExtractedStrings[fragment.value]; ok {
+			addStringKey(rp.mergedFragments, rp.I18nStringsFilename, fragment.value)
+		}
+	}
+}
+
+// removeMergedFragments removes the fragments of the merged concatenations from the i18n strings files, but those also
+// translated on their own
+func (rp *rewritePackage) removeMergedFragments() error {
+	var fileNames []string
+	for fileName := range rp.mergedFragments {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	for _, fileName := range fileNames {
+		i18nStringInfos, err := rp.loadI18nStringInfos(fileName)
+		if err != nil {
+			return err
+		}
+
+		var keptStringInfos []common.I18nStringInfo
+		for _, i18nStringInfo := range i18nStringInfos {
+			key := rp.lookupKey(i18nStringInfo)
+			if rp.mergedFragments[fileName][key] && !rp.wrappedStrings[fileName][key] {
+				rp.Println("i18n4go: removing the fragment of a merged concatenation:", strconv.Quote(i18nStringInfo.Translation), "from:", fileName)
+				continue
+			}
+			keptStringInfos = append(keptStringInfos, i18nStringInfo)
+		}
+
+		if len(keptStringInfos) < len(i18nStringInfos) {
+			err = rp.saveI18nStringInfos(keptStringInfos, fileName)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// addStringKey records the key of a string of the i18n strings file fileName
+func addStringKey(stringKeys map[string]map[string]bool, fileName, key string) {
+	if stringKeys[fileName] == nil {
+		stringKeys[fileName] = make(map[string]bool)
+	}
+	stringKeys[fileName][key] = true
+}
@@ -1,16 +1,17 @@
 package cmds
 
 import (
-	"bytes"
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 
 	"go/ast"
-	"go/build"
-	"go/format"
 	"go/parser"
 	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
 	"io/ioutil"
 
 	"github.com/Liam-Williams/i18n4go/common"
@@ -31,9 +32,32 @@ import (
 )
 
 var T goi18n.TranslateFunc
+var TC i18n.TranslateContextFunc
 
 func init() {
 	T = i18n.Init(__FULL_IMPORT_PATH__, i18n.GetResourcesPath())
+	TC = i18n.ContextTfunc(T)
+}`
+
+	EMBED_INIT_CODE_SNIPPET = `package __PACKAGE__NAME__
+
+import (
+	"embed"
+	"path/filepath"
+
+	i18n "github.com/Liam-Williams/i18n4go/i18n"
+	goi18n "github.com/nicksnyder/go-i18n/i18n"
+)
+
+//go:embed __EMBED_DIRNAME__
+var i18nResources embed.FS
+
+var T goi18n.TranslateFunc
+var TC i18n.TranslateContextFunc
+
+func init() {
+	T = i18n.InitFS(i18nResources, __FULL_IMPORT_PATH__, "__EMBED_DIRNAME__")
+	TC = i18n.ContextTfunc(T)
 }`
 )
 
@@ -46,6 +70,7 @@ type rewritePackage struct {
 	I18nStringsDirname      string
 	RootPath                string
 	InitCodeSnippetFilename string
+	EmbedDirname            string
 
 	Dirname string
 	Recurse bool
@@ -54,8 +79,49 @@ type rewritePackage struct {
 	UpdatedExtractedStrings map[string]common.I18nStringInfo
 	SaveExtractedStrings    bool
 
-	TotalStrings int
-	TotalFiles   int
+	// the messages given an ID without an i18n strings file, saved to the source language file
+	SourceStrings map[string]common.I18nStringInfo
+
+	directives   *common.Directives
+	editor       *sourceEditor
+	idGenerators map[string]*common.IDGenerator
+	idGenerator  *common.IDGenerator
+	funcName     string
+	qualifier    string
+
+	// the translation runtime the rewritten strings call
+	backend rewriteBackend
+
+	// the packages the rewritten expressions of the file being rewritten refer to, e.g., fmt, by import path, they get
+	// imported when the file does not import them
+	astFile     *ast.File
+	packageRefs map[string]*packageRef
+
+	// the joins of the chains of + of the file whose parts are not merged into one message with --merge-concatenations,
+	// and the keys of the strings the merged chains replace and of those wrapped on their own, by i18n strings file
+	concatenationJoins map[*ast.BinaryExpr]bool
+	mergedFragments    map[string]map[string]bool
+	wrappedStrings     map[string]map[string]bool
+
+	// the type information of the packages, by directory, it tells the constants and package level variables whose strings
+	// are translated when they are used, by turning them into funcs, from those left untranslated
+	packageLoader  *common.PackageLoader
+	packageObjects map[string]*packageObjects
+	fileName       string
+	fileImports    map[string]string
+	lazyDecls      []*lazyDecl
+	lazyObjects    map[types.Object]bool
+
+	// the files that change, in the order they were rewritten, they are written once the rewritten packages compile
+	changes       []common.FileChange
+	changeIndexes map[string]int
+
+	// the source files the rewritten Go files replace, by output path, their packages are type checked with them
+	sourceFiles map[string]string
+
+	TotalStrings   int
+	TotalFiles     int
+	TotalUnfixable int
 
 	IgnoreRegexp *regexp.Regexp
 }
@@ -77,11 +143,22 @@ func NewRewritePackage(options common.Options) rewritePackage {
 		I18nStringsDirname:      options.I18nStringsDirnameFlag,
 		RootPath:                options.RootPathFlag,
 		InitCodeSnippetFilename: options.InitCodeSnippetFilenameFlag,
+		EmbedDirname:            filepath.ToSlash(options.EmbedDirnameFlag),
 
 		ExtractedStrings:        nil,
 		UpdatedExtractedStrings: nil,
 		SaveExtractedStrings:    false,
 
+		SourceStrings: make(map[string]common.I18nStringInfo),
+		idGenerators:  make(map[string]*common.IDGenerator),
+		changeIndexes: make(map[string]int),
+		sourceFiles:   make(map[string]string),
+
+		packageObjects: make(map[string]*packageObjects),
+
+		mergedFragments: make(map[string]map[string]bool),
+		wrappedStrings:  make(map[string]map[string]bool),
+
 		Dirname:      options.DirnameFlag,
 		Recurse:      options.RecurseFlag,
 		IgnoreRegexp: compiledRegexp,
@@ -94,7 +171,7 @@ func (rp *rewritePackage) Options() common.Options {
 
 func (rp *rewritePackage) Println(a ...interface{}) (int, error) {
 	if rp.options.VerboseFlag {
-		return fmt.Println(a...)
+		return fmt.Fprintln(rp.output(), a...)
 	}
 
 	return 0, nil
@@ -102,14 +179,42 @@ func (rp *rewritePackage) Println(a ...interface{}) (int, error) {
 
 func (rp *rewritePackage) Printf(msg string, a ...interface{}) (int, error) {
 	if rp.options.VerboseFlag {
-		return fmt.Printf(msg, a...)
+		return fmt.Fprintf(rp.output(), msg, a...)
 	}
 
 	return 0, nil
 }
 
+// output is where the messages are printed, stderr while the changes are previewed so that stdout is only the diff
+func (rp *rewritePackage) output() io.Writer {
+	if rp.previewing() {
+		return os.Stderr
+	}
+
+	return os.Stdout
+}
+
 func (rp *rewritePackage) Run() error {
-	var err error
+	err := common.CheckIDStrategy(rp.idStrategy())
+	if err != nil {
+		return err
+	}
+
+	err = rp.checkEmbedDirname()
+	if err != nil {
+		return err
+	}
+
+	rp.backend, err = newRewriteBackend(rp.options.BackendFlag)
+	if err != nil {
+		return err
+	}
+
+	// the generated i18n_init.go of go-i18n v2 and x/text load the translations the package embeds, the binaries would
+	// otherwise look for them in the dir they are run from
+	if rp.options.TranslatorPackageFlag == "" && rp.InitCodeSnippetFilename == "" && rp.EmbedDirname == "" && rp.backend.initCodeSnippet(false) == "" {
+		return fmt.Errorf("i18n4go: the %s backend requires --embed-dirname, the locale dir the i18n_init.go of each package embeds", rp.options.BackendFlag)
+	}
 
 	if rp.options.FilenameFlag != "" {
 		if err = rp.loadStringsToBeTranslated(rp.I18nStringsFilename); err != nil {
@@ -120,15 +225,71 @@ func (rp *rewritePackage) Run() error {
 		err = rp.processDir(rp.options.DirnameFlag, rp.options.RecurseFlag)
 	}
 
+	if err == nil && len(rp.mergedFragments) > 0 {
+		err = rp.removeMergedFragments()
+	}
+
+	if err == nil && len(rp.SourceStrings) > 0 {
+		err = rp.saveSourceStrings()
+	}
+
+	if err == nil && rp.previewing() {
+		err = rp.reportChanges()
+	} else if err == nil {
+		err = rp.commitChanges()
+	}
+
 	rp.Println()
 	rp.Println("Total files parsed:", rp.TotalFiles)
 	rp.Println("Total rewritten strings:", rp.TotalStrings)
+	if rp.TotalUnfixable > 0 {
+		rp.Println("Total unfixable constants and variables:", rp.TotalUnfixable)
+	}
 	return err
 }
 
+// previewing is true when the changes are printed, checked or saved to a patch rather than written to the files
+func (rp *rewritePackage) previewing() bool {
+	return rp.options.DiffFlag || rp.options.CheckFlag || rp.options.PatchFlag != ""
+}
+
+// reportChanges prints the diff of the changes with --diff, saves their patch with --patch
+// and fails with --check when any file would change
+func (rp *rewritePackage) reportChanges() error {
+	var patch strings.Builder
+	for _, change := range rp.changes {
+		if rp.options.DiffFlag {
+			fmt.Print(change.Diff())
+		}
+		patch.WriteString(change.Patch())
+	}
+
+	if rp.options.PatchFlag != "" {
+		rp.Println("i18n4go: saving the patch of the changes to:", rp.options.PatchFlag)
+		err := ioutil.WriteFile(rp.options.PatchFlag, []byte(patch.String()), 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	if rp.options.CheckFlag && len(rp.changes) > 0 {
+		// the files that would change are the report of --check, unless stdout is the diff
+		report := io.Writer(os.Stdout)
+		if rp.options.DiffFlag {
+			report = os.Stderr
+		}
+		for _, change := range rp.changes {
+			fmt.Fprintln(report, "i18n4go: would rewrite", change.FileName)
+		}
+		return fmt.Errorf("i18n4go: %d files would be rewritten", len(rp.changes))
+	}
+
+	return nil
+}
+
 func (rp *rewritePackage) loadStringsToBeTranslated(fileName string) error {
 	if fileName != "" {
-		stringList, err := common.LoadI18nStringInfos(fileName)
+		stringList, err := rp.loadI18nStringInfos(fileName)
 		if err != nil {
 			return err
 		}
@@ -138,6 +299,14 @@ func (rp *rewritePackage) loadStringsToBeTranslated(fileName string) error {
 			return err
 		}
 
+		if rp.usesIDs() {
+			// the strings are looked up by their source text, the ID is what T() is called with
+			rp.ExtractedStrings = make(map[string]common.I18nStringInfo, len(stringList))
+			for _, i18nStringInfo := range stringList {
+				rp.ExtractedStrings[common.MessageKey(i18nStringInfo.Context, i18nStringInfo.Translation)] = i18nStringInfo
+			}
+		}
+
 		rp.UpdatedExtractedStrings = common.CopyI18nStringInfoMap(rp.ExtractedStrings)
 	}
 
@@ -148,14 +317,28 @@ func (rp *rewritePackage) processDir(dirName string, recursive bool) error {
 	rp.Printf("i18n4go: rewriting strings in dir %s, recursive: %t\n", dirName, recursive)
 	rp.Println()
 
+	// files excluded by the build tags are left untouched, unless the go command cannot load the package
+	var buildFiles map[string]bool
+	listedPackage, err := common.FindPackage(dirName, rp.options.TagsFlag)
+	if err == nil && listedPackage.Error == nil {
+		buildFiles = make(map[string]bool)
+		for _, fileName := range listedPackage.SourceFiles(false) {
+			buildFiles[filepath.Base(fileName)] = true
+		}
+	}
+
 	fileInfos, _ := ioutil.ReadDir(dirName)
 	for _, fileInfo := range fileInfos {
 		if fileInfo.IsDir() {
-			if recursive {
-				rp.processDir(filepath.Join(dirName, fileInfo.Name()), recursive)
+			if recursive && fileInfo.Name() != "vendor" && fileInfo.Name() != "testdata" {
+				if err := rp.processDir(filepath.Join(dirName, fileInfo.Name()), recursive); err != nil {
+					return err
+				}
 			} else {
 				continue
 			}
+		} else if buildFiles != nil && strings.HasSuffix(fileInfo.Name(), ".go") && !strings.HasSuffix(fileInfo.Name(), "_test.go") && !buildFiles[fileInfo.Name()] {
+			rp.Println("i18n4go: skipping file excluded by build constraints:", filepath.Join(dirName, fileInfo.Name()))
 		} else if rp.ignoreFile(filepath.Base(fileInfo.Name())) {
 			i18nFilename := rp.I18nStringsFilename
 			if rp.I18nStringsDirname != "" {
@@ -209,7 +392,13 @@ func (rp *rewritePackage) processFilename(fileName string) error {
 		absFilePath = filepath.Join(os.Getenv("PWD"), absFilePath)
 	}
 
-	astFile, err := parser.ParseFile(fileSet, absFilePath, nil, parser.ParseComments|parser.AllErrors)
+	src, err := ioutil.ReadFile(absFilePath)
+	if err != nil {
+		rp.Println(err)
+		return err
+	}
+
+	astFile, err := parser.ParseFile(fileSet, absFilePath, src, parser.ParseComments|parser.AllErrors)
 	if err != nil {
 		rp.Println(err)
 		return err
@@ -220,6 +409,27 @@ func (rp *rewritePackage) processFilename(fileName string) error {
 		return nil
 	}
 
+	rp.directives = common.ParseDirectives(fileSet, astFile)
+	rp.editor = newSourceEditor(fileSet, astFile, src)
+	rp.fileName = fileName
+	if rp.directives.IgnoreFile {
+		rp.Println("i18n4go: ignoring file with an ignore-file directive:", fileName)
+		return nil
+	}
+
+	// the IDs are unique per package, files of the same package share a generator, which is not given the IDs the
+	// i18n strings already have
+	rp.idGenerator = rp.idGenerators[astFile.Name.Name]
+	if rp.idGenerator == nil {
+		rp.idGenerator = common.NewIDGenerator(rp.idStrategy(), astFile.Name.Name)
+		for _, i18nStringInfo := range rp.ExtractedStrings {
+			if i18nStringInfo.ID != i18nStringInfo.Translation {
+				rp.idGenerator.Reserve(i18nStringInfo.Context, i18nStringInfo.Translation, i18nStringInfo.ID)
+			}
+		}
+		rp.idGenerators[astFile.Name.Name] = rp.idGenerator
+	}
+
 	importPath, err := rp.determineImportPath(absFilePath)
 	if err != nil {
 		rp.Println("i18n4go: error determining the import path:", err.Error())
@@ -230,21 +440,47 @@ func (rp *rewritePackage) processFilename(fileName string) error {
 		rp.OutputDirname = filepath.Dir(fileName)
 	}
 
-	outputDir := filepath.Join(rp.OutputDirname, filepath.Dir(rp.relativePathForFile(fileName)))
-	err = rp.addInitFuncToPackage(astFile.Name.Name, outputDir, importPath)
-	if err != nil {
-		rp.Println("i18n4go: error adding init() func to package:", err.Error())
-		return err
+	// T() is declared by the central translator package rather than by an init file of each package
+	if rp.options.TranslatorPackageFlag == "" {
+		outputDir := filepath.Join(rp.OutputDirname, filepath.Dir(rp.relativePathForFile(fileName)))
+		err = rp.addInitFuncToPackage(astFile.Name.Name, outputDir, importPath)
+		if err != nil {
+			rp.Println("i18n4go: error adding init() func to package:", err.Error())
+			return err
+		}
+		rp.addSourceFile(filepath.Join(outputDir, "i18n_init.go"), filepath.Join(filepath.Dir(absFilePath), "i18n_init.go"))
 	}
 
+	qualifier, addImport := rp.fileQualifier(astFile)
+	rp.qualifier = qualifier
+	rp.astFile = astFile
+	rp.packageRefs = make(map[string]*packageRef)
+	rp.concatenationJoins = make(map[*ast.BinaryExpr]bool)
+
+	totalStrings := rp.TotalStrings
 	err = rp.insertTFuncCall(astFile)
 	if err != nil {
 		rp.Println("i18n4go: error appending T() to AST file:", err.Error())
 		return err
 	}
+	rp.rewriteLazyDecls(astFile)
+
+	if addImport && rp.TotalStrings > totalStrings {
+		rp.Println("i18n4go: importing the translator package:", rp.options.TranslatorPackageFlag, "as", qualifier)
+		rp.editor.addImport(rp.importName(qualifier), rp.options.TranslatorPackageFlag)
+	}
+
+	// without translator package the qualified T() calls need a package named like the qualifier
+	if rp.options.TranslatorPackageFlag == "" && qualifier != "" && rp.TotalStrings > totalStrings {
+		if _, ok := common.FileImports(astFile)[qualifier]; !ok {
+			err = fmt.Errorf("i18n4go: %s does not import a package named %s, use --translator-package with the import path of the package of T()", fileName, qualifier)
+			rp.Println("i18n4go: error qualifying T() in file:", err.Error())
+			return err
+		}
+	}
 
 	relativeFilePath := rp.relativePathForFile(fileName)
-	err = rp.saveASTFile(relativeFilePath, fileName, astFile, fileSet)
+	err = rp.saveASTFile(relativeFilePath, fileName, astFile)
 	if err != nil {
 		rp.Println("i18n4go: error saving AST file:", err.Error())
 		return err
@@ -252,7 +488,7 @@ func (rp *rewritePackage) processFilename(fileName string) error {
 
 	if rp.SaveExtractedStrings {
 		i18nStringInfos := common.I18nStringInfoMapValues2Array(rp.UpdatedExtractedStrings)
-		err := common.SaveI18nStringInfos(rp, rp.Options(), i18nStringInfos, rp.I18nStringsFilename)
+		err := rp.saveI18nStringInfos(i18nStringInfos, rp.I18nStringsFilename)
 		if err != nil {
 			rp.Println("i18n4go: error saving updated i18n strings file:", err.Error())
 			return err
@@ -262,31 +498,200 @@ func (rp *rewritePackage) processFilename(fileName string) error {
 	return err
 }
 
+// qualified is true when T() is called with a qualifier, e.g., i18n.T(), rather than declared in each package
+func (rp *rewritePackage) qualified() bool {
+	return rp.options.QualifierFlag != "" || rp.options.TranslatorPackageFlag != ""
+}
+
+// fileQualifier returns the name the translator package is known by in astFile, the name of its import when the file
+// imports it already, otherwise the -q qualifier, or the name of the package, numbered when another import has that
+// name, addImport is then true. Without translator package it is the -q qualifier, a file calling T() must then import
+// a package named after it
+func (rp *rewritePackage) fileQualifier(astFile *ast.File) (string, bool) {
+	if !rp.qualified() {
+		return "", false
+	}
+
+	imports := common.FileImports(astFile)
+	translatorPackage := rp.options.TranslatorPackageFlag
+	if translatorPackage == "" {
+		return rp.options.QualifierFlag, false
+	}
+
+	for _, importSpec := range astFile.Imports {
+		if importPath, err := strconv.Unquote(importSpec.Path.Value); err == nil && importPath == translatorPackage {
+			if importSpec.Name == nil {
+				return common.ImportName(translatorPackage), false
+			}
+			if importSpec.Name.Name == "." {
+				return "", false
+			}
+			if importSpec.Name.Name != "_" {
+				return importSpec.Name.Name, false
+			}
+		}
+	}
+
+	qualifier := rp.options.QualifierFlag
+	if qualifier == "" {
+		qualifier = common.ImportName(translatorPackage)
+	}
+
+	name := qualifier
+	for i := 2; imports[name] != ""; i++ {
+		name = fmt.Sprintf("%s%d", qualifier, i)
+	}
+
+	return name, true
+}
+
+// importName is the name of the import of the translator package, empty when it is the name of the package
+func (rp *rewritePackage) importName(qualifier string) string {
+	if qualifier == common.ImportName(rp.options.TranslatorPackageFlag) {
+		return ""
+	}
+
+	return qualifier
+}
+
+// tFunc is the func that wraps the strings, e.g., T, or the selector of names, e.g., Localizer.MustLocalize, qualified
+// by the name of the translator package when there is one
+func (rp *rewritePackage) tFunc(names ...string) ast.Expr {
+	var expr ast.Expr = &ast.Ident{Name: names[0]}
+	if rp.qualifier != "" {
+		expr = &ast.SelectorExpr{X: &ast.Ident{Name: rp.qualifier}, Sel: &ast.Ident{Name: names[0]}}
+	}
+
+	for _, name := range names[1:] {
+		expr = &ast.SelectorExpr{X: expr, Sel: &ast.Ident{Name: name}}
+	}
+
+	return expr
+}
+
+// packageRef is the name a file refers to a package by, empty for a dot import, add is true when the file does not
+// import the package yet
+type packageRef struct {
+	path string
+	name string
+	add  bool
+}
+
+// filePackageRef returns the name the package importPath is imported by in astFile, or the name of the package when
+// astFile does not import it, numbered when another import or a name of taken has it
+func filePackageRef(astFile *ast.File, importPath string, taken ...string) *packageRef {
+	for _, importSpec := range astFile.Imports {
+		if path, err := strconv.Unquote(importSpec.Path.Value); err != nil || path != importPath {
+			continue
+		}
+		if importSpec.Name == nil {
+			return &packageRef{path: importPath, name: common.ImportName(importPath)}
+		}
+		if importSpec.Name.Name == "." {
+			return &packageRef{path: importPath}
+		}
+		if importSpec.Name.Name != "_" {
+			return &packageRef{path: importPath, name: importSpec.Name.Name}
+		}
+	}
+
+	imports := common.FileImports(astFile)
+	for _, name := range taken {
+		imports[name] = name
+	}
+
+	packageName := common.ImportName(importPath)
+	name := packageName
+	for i := 2; imports[name] != ""; i++ {
+		name = fmt.Sprintf("%s%d", packageName, i)
+	}
+
+	return &packageRef{path: importPath, name: name, add: true}
+}
+
+// packageFunc is the func, or type, of the package importPath named name, the package is imported by the file being
+// rewritten when it is not yet
+func (rp *rewritePackage) packageFunc(importPath, name string) ast.Expr {
+	ref, ok := rp.packageRefs[importPath]
+	if !ok {
+		ref = filePackageRef(rp.astFile, importPath, rp.qualifier)
+		rp.packageRefs[importPath] = ref
+	}
+
+	if ref.add {
+		importName := ""
+		if ref.name != common.ImportName(importPath) {
+			importName = ref.name
+		}
+		rp.editor.addImport(importName, importPath)
+	}
+
+	if ref.name == "" {
+		return &ast.Ident{Name: name}
+	}
+
+	return &ast.SelectorExpr{X: &ast.Ident{Name: ref.name}, Sel: &ast.Ident{Name: name}}
+}
+
+// fmtFunc is the func of package fmt named name, package fmt is imported by the file being rewritten when it is not yet
+func (rp *rewritePackage) fmtFunc(name string) ast.Expr {
+	return rp.packageFunc("fmt", name)
+}
+
+// isTCall is true for the calls of the func of the backend, e.g., T(), qualified or not, their strings are already translated
+func (rp *rewritePackage) isTCall(callExpr *ast.CallExpr) bool {
+	names := rp.backend.funcNames()
+	fun := callExpr.Fun
+	for i := len(names) - 1; i > 0; i-- {
+		selectorExpr, ok := fun.(*ast.SelectorExpr)
+		if !ok || selectorExpr.Sel.Name != names[i] {
+			return false
+		}
+		fun = selectorExpr.X
+	}
+
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		return fun.Name == names[0]
+	case *ast.SelectorExpr:
+		ident, ok := fun.X.(*ast.Ident)
+		return ok && rp.qualifier != "" && ident.Name == rp.qualifier && fun.Sel.Name == names[0]
+	}
+
+	return false
+}
+
 func (rp *rewritePackage) determineImportPath(filePath string) (string, error) {
 	dirName := filepath.Dir(filePath)
-	if rp.options.RootPathFlag == "" {
-		rp.Println("i18n4go: using the PWD as the rootPath:", os.Getenv("PWD"))
-		rp.RootPath = os.Getenv("PWD")
-	}
-	rp.Println("i18n4go: determining import path using root path:", rp.RootPath)
-	pkg, err := build.Default.ImportDir(rp.RootPath, build.ImportMode(1))
+
+	otherPkg, err := common.FindPackage(dirName, rp.options.TagsFlag)
 	if err != nil {
 		rp.Println("i18n4go: error getting root path import:", err.Error())
 		return "", err
 	}
-	rp.Println("i18n4go: got a root pkg with import path:", pkg.ImportPath)
+	rp.Println("i18n4go: got a pkg with import:", otherPkg.ImportPath)
+
+	if rp.options.RootPathFlag == "" {
+		if otherPkg.Module != nil && otherPkg.Module.Dir != "" {
+			rp.Println("i18n4go: using the module root as the rootPath:", otherPkg.Module.Dir)
+			rp.RootPath = otherPkg.Module.Dir
+		} else {
+			rp.Println("i18n4go: using the PWD as the rootPath:", os.Getenv("PWD"))
+			rp.RootPath = os.Getenv("PWD")
+		}
+	}
+	rp.Println("i18n4go: determining import path using root path:", rp.RootPath)
 
-	otherPkg, err := build.Default.ImportDir(dirName, build.ImportMode(0))
+	pkg, err := common.FindPackage(rp.RootPath, rp.options.TagsFlag)
 	if err != nil {
 		rp.Println("i18n4go: error getting root path import:", err.Error())
 		return "", err
 	}
-	rp.Println("i18n4go: got a pkg with import:", otherPkg.ImportPath)
+	rp.Println("i18n4go: got a root pkg with import path:", pkg.ImportPath)
 
-	importPath := otherPkg.ImportPath
-	importPath = strings.Replace(importPath, pkg.ImportPath, "", 1)
-	if strings.HasPrefix(importPath, "/") {
-		importPath = strings.TrimLeft(importPath, "/")
+	importPath, relative := common.RelativeImportPath(pkg, otherPkg)
+	if !relative {
+		rp.Println("i18n4go: the package is not below the root path, using its full import path")
 	}
 	rp.Println("i18n4go: using import path as:", importPath)
 
@@ -302,75 +707,151 @@ func (rp *rewritePackage) insertTFuncCall(astFile *ast.File) error {
 		declarations = astFile.Decls[0:]
 	}
 
+	rp.fileImports = fileImportNames(astFile)
+	rp.lazyDecls = nil
+	rp.lazyObjects = make(map[types.Object]bool)
 	for _, decl := range declarations {
-		ast.Inspect(decl, func(node ast.Node) bool {
-			switch node.(type) {
-			case *ast.CallExpr:
-				if !rp.callExprTFunc(node.(*ast.CallExpr)) {
-					return false // don't recurse infinitely
-				}
-			case *ast.AssignStmt:
-				rp.assignStmtTFunc(node.(*ast.AssignStmt))
-			case *ast.ValueSpec:
-				rp.valueSpecTFunc(node.(*ast.ValueSpec))
-			case *ast.CompositeLit:
-				rp.compositeLitTFunc(node.(*ast.CompositeLit))
-			case *ast.KeyValueExpr:
-				rp.keyValueExprTFunc(node.(*ast.KeyValueExpr))
-			case *ast.ReturnStmt:
-				rp.returnStmtTFunc(node.(*ast.ReturnStmt))
-			case *ast.BinaryExpr:
-				rp.binaryExprTFunc(node.(*ast.BinaryExpr))
-			case *ast.IndexExpr:
-				rp.indexExprTFunc(node.(*ast.IndexExpr))
-			}
+		rp.funcName = ""
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			rp.funcName = common.FuncDeclName(funcDecl)
+		}
 
-			return true
-		})
+		// T() is assigned by init(), after the package level variables are initialized
+		if genDecl, ok := decl.(*ast.GenDecl); ok && (genDecl.Tok == token.CONST || genDecl.Tok == token.VAR && !rp.qualified()) {
+			rp.packageDeclTFunc(genDecl)
+			continue
+		}
+
+		rp.inspectTFunc(decl)
 	}
 
 	return nil
 }
 
+// inspectTFunc wraps the strings of node that need to be translated with T()
+func (rp *rewritePackage) inspectTFunc(node ast.Node) {
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.CallExpr:
+			if !rp.callExprTFunc(node.(*ast.CallExpr)) {
+				return false // don't recurse infinitely
+			}
+		case *ast.AssignStmt:
+			rp.assignStmtTFunc(node.(*ast.AssignStmt))
+		case *ast.DeclStmt:
+			if genDecl, ok := node.(*ast.DeclStmt).Decl.(*ast.GenDecl); ok && genDecl.Tok == token.CONST {
+				rp.localConstTFunc(genDecl)
+				return false
+			}
+		case *ast.ValueSpec:
+			rp.valueSpecTFunc(node.(*ast.ValueSpec))
+		case *ast.CompositeLit:
+			rp.compositeLitTFunc(node.(*ast.CompositeLit))
+		case *ast.KeyValueExpr:
+			rp.keyValueExprTFunc(node.(*ast.KeyValueExpr))
+		case *ast.ReturnStmt:
+			rp.returnStmtTFunc(node.(*ast.ReturnStmt))
+		case *ast.BinaryExpr:
+			rp.binaryExprTFunc(node.(*ast.BinaryExpr))
+		case *ast.IndexExpr:
+			rp.indexExprTFunc(node.(*ast.IndexExpr))
+		}
+
+		return true
+	})
+}
+
 func (rp *rewritePackage) indexExprTFunc(indexExpr *ast.IndexExpr) {
-	if index, ok := indexExpr.Index.(*ast.BasicLit); ok {
-		indexExpr.Index = rp.wrapBasicLitWithT(index)
-	}
+	indexExpr.Index = rp.wrapExprWithT(indexExpr.Index)
 }
 
 func (rp *rewritePackage) binaryExprTFunc(binaryExpr *ast.BinaryExpr) {
-	if x, ok := binaryExpr.X.(*ast.BasicLit); ok {
-		binaryExpr.X = rp.wrapBasicLitWithT(x)
+	if binaryExpr.Op == token.ADD {
+		rp.markConcatenation(binaryExpr)
+		if rp.concatenationTFunc(binaryExpr) {
+			return
+		}
+	}
+
+	binaryExpr.X = rp.wrapExprWithT(binaryExpr.X)
+	binaryExpr.Y = rp.wrapExprWithT(binaryExpr.Y)
+}
+
+// concatenationTFunc wraps each run of adjacent constant operands of a chain of + with one T() call,
+// e.g., "Failed to " + "connect: " + reason becomes T("Failed to " + "connect: ") + reason
+func (rp *rewritePackage) concatenationTFunc(binaryExpr *ast.BinaryExpr) bool {
+	foldedStrings := common.NewConstFolder(nil).FoldConcatenation(binaryExpr)
+	if len(foldedStrings) == 0 {
+		return false
+	}
+
+	operands, joins := common.Concatenation(binaryExpr)
+	if len(foldedStrings[0].Operands) == len(operands) {
+		// the whole chain is one string, it is wrapped by wrapExprWithT from its parent
+		return true
+	}
+
+	runs := make(map[ast.Expr]common.FoldedString)
+	for _, foldedString := range foldedStrings {
+		runs[foldedString.Operands[0]] = foldedString
+	}
+
+	var newOperands []ast.Expr
+	var opPositions []token.Pos
+	for i := 0; i < len(operands); i++ {
+		if i > 0 {
+			opPositions = append(opPositions, joins[i-1].OpPos)
+		}
+
+		foldedString, ok := runs[operands[i]]
+		if !ok {
+			newOperands = append(newOperands, rp.wrapExprWithT(operands[i]))
+			continue
+		}
+
+		runExpr := foldedString.Operands[0]
+		for j, operand := range foldedString.Operands[1:] {
+			runExpr = &ast.BinaryExpr{X: runExpr, OpPos: joins[i+j].OpPos, Op: token.ADD, Y: operand}
+		}
+		i += len(foldedString.Operands) - 1
+
+		runPos, runEnd := foldedString.Operands[0].Pos(), foldedString.Operands[len(foldedString.Operands)-1].End()
+		rp.editor.regroup(runExpr, runPos, runEnd)
+		if rp.shouldTranslateFolded(foldedString) {
+			rp.TotalStrings++
+			value := rp.formatMessage(foldedString.Lits, foldedString.Value, "")
+			tCallExpr := rp.backend.translateCall(rp, translatedMessage{id: rp.messageIDExpr(runExpr, value, "")})
+			runExpr = rp.editor.replace(tCallExpr, runPos, runEnd)
+		}
+		newOperands = append(newOperands, runExpr)
 	}
 
-	if y, ok := binaryExpr.Y.(*ast.BasicLit); ok {
-		binaryExpr.Y = rp.wrapBasicLitWithT(y)
+	// the chain is rebuilt left to right, the outermost binary expression is kept since its parent refers to it
+	expr := newOperands[0]
+	for i, operand := range newOperands[1 : len(newOperands)-1] {
+		expr = &ast.BinaryExpr{X: expr, OpPos: opPositions[i], Op: token.ADD, Y: operand}
 	}
+	binaryExpr.X = expr
+	binaryExpr.OpPos = opPositions[len(opPositions)-1]
+	binaryExpr.Y = newOperands[len(newOperands)-1]
+
+	return true
 }
 
 func (rp *rewritePackage) returnStmtTFunc(returnStmt *ast.ReturnStmt) {
 	for index, arg := range returnStmt.Results {
-		if asLit, ok := arg.(*ast.BasicLit); ok {
-			returnStmt.Results[index] = rp.wrapBasicLitWithT(asLit)
-		}
+		returnStmt.Results[index] = rp.wrapExprWithT(arg)
 	}
 }
 
 func (rp *rewritePackage) keyValueExprTFunc(keyValueExpr *ast.KeyValueExpr) {
-	if key, ok := keyValueExpr.Key.(*ast.BasicLit); ok {
-		keyValueExpr.Key = rp.wrapBasicLitWithT(key)
-	}
-
-	if value, ok := keyValueExpr.Value.(*ast.BasicLit); ok {
-		keyValueExpr.Value = rp.wrapBasicLitWithT(value)
-	}
+	keyValueExpr.Key = rp.wrapExprWithT(keyValueExpr.Key)
+	keyValueExpr.Value = rp.wrapExprWithT(keyValueExpr.Value)
 }
 
 func (rp *rewritePackage) compositeLitTFunc(compositeLit *ast.CompositeLit) bool {
 	for index, arg := range compositeLit.Elts {
-		if asLit, ok := arg.(*ast.BasicLit); ok {
-			compositeLit.Elts[index] = rp.wrapBasicLitWithT(asLit)
-		}
+		compositeLit.Elts[index] = rp.wrapExprWithT(arg)
 	}
 
 	return true
@@ -378,9 +859,7 @@ func (rp *rewritePackage) compositeLitTFunc(compositeLit *ast.CompositeLit) bool
 
 func (rp *rewritePackage) assignStmtTFunc(assignStmt *ast.AssignStmt) bool {
 	for index, arg := range assignStmt.Rhs {
-		if asLit, ok := arg.(*ast.BasicLit); ok {
-			assignStmt.Rhs[index] = rp.wrapBasicLitWithT(asLit)
-		}
+		assignStmt.Rhs[index] = rp.wrapExprWithT(arg)
 	}
 
 	return true
@@ -388,17 +867,17 @@ func (rp *rewritePackage) assignStmtTFunc(assignStmt *ast.AssignStmt) bool {
 
 func (rp *rewritePackage) valueSpecTFunc(valueSpec *ast.ValueSpec) bool {
 	for index, arg := range valueSpec.Values {
-		if asLit, ok := arg.(*ast.BasicLit); ok {
-			valueSpec.Values[index] = rp.wrapBasicLitWithT(asLit)
-		}
+		valueSpec.Values[index] = rp.wrapExprWithT(arg)
 	}
 
 	return true
 }
 
 func (rp *rewritePackage) callExprTFunc(callExpr *ast.CallExpr) bool {
-	callFuncIdent, ok := callExpr.Fun.(*ast.Ident)
-	if ok && (callFuncIdent.Name == "T") { // yeah, not the best
+	if rp.isTCall(callExpr) {
+		return false
+	}
+	if common.IsContextCall(callExpr) {
 		return false
 	}
 
@@ -406,9 +885,7 @@ func (rp *rewritePackage) callExprTFunc(callExpr *ast.CallExpr) bool {
 	case 0:
 		return false
 	case 1:
-		if basicLit, ok := callExpr.Args[0].(*ast.BasicLit); ok {
-			callExpr.Args[0] = rp.wrapBasicLitWithT(basicLit)
-		}
+		callExpr.Args[0] = rp.wrapExprWithT(callExpr.Args[0])
 	default:
 		rp.wrapMultiArgsCallExpr(callExpr)
 	}
@@ -423,7 +900,7 @@ func (rp *rewritePackage) wrapMultiArgsCallExpr(callExpr *ast.CallExpr) {
 				valueWithoutQuotes, _ := strconv.Unquote(basicLit.Value) //basicLit.Value[1 : len(basicLit.Value)-1]
 
 				if common.IsTemplatedString(valueWithoutQuotes) {
-					rp.wrapCallExprWithTemplatedT(basicLit, callExpr, i)
+					rp.wrapCallExprWithTemplatedT(basicLit, callExpr, i, templatedStringArgs(valueWithoutQuotes))
 				} else if common.IsInterpolatedString(valueWithoutQuotes) {
 					rp.wrapCallExprWithInterpolatedT(basicLit, callExpr, i)
 				} else {
@@ -433,6 +910,8 @@ func (rp *rewritePackage) wrapMultiArgsCallExpr(callExpr *ast.CallExpr) {
 			} else {
 				rp.wrapExprArgs(callExpr.Args)
 			}
+		} else if _, ok := arg.(*ast.BinaryExpr); ok {
+			callExpr.Args[i] = rp.wrapExprWithT(arg)
 		}
 	}
 }
@@ -440,25 +919,40 @@ func (rp *rewritePackage) wrapMultiArgsCallExpr(callExpr *ast.CallExpr) {
 func (rp *rewritePackage) wrapCallExprWithInterpolatedT(basicLit *ast.BasicLit, callExpr *ast.CallExpr, argIndex int) {
 	valueWithoutQuotes, _ := strconv.Unquote(basicLit.Value)
 
-	i18nStringInfo, ok := rp.ExtractedStrings[valueWithoutQuotes]
-	if !ok && rp.ExtractedStrings != nil {
+	templatedString, templateArgs := common.ConvertFormatToTemplate(valueWithoutQuotes)
+	if !rp.shouldTranslate(basicLit, valueWithoutQuotes) || !templateArgsFit(templateArgs, callExpr.Args, argIndex) {
 		rp.wrapExprArgs(callExpr.Args)
 		return
 	}
 
-	templatedString := common.ConvertToTemplatedString(valueWithoutQuotes)
+	// the format is the message of the backends formatting the args
+	if rp.backend.formatsArgs() {
+		rp.wrapCallExprWithTemplatedT(basicLit, callExpr, argIndex, templateArgs)
+		return
+	}
+	i18nStringInfo := rp.ExtractedStrings[valueWithoutQuotes]
+
+	templatedString, templateArgs = namedTemplateArgs(templatedString, templateArgs, callExpr.Args, argIndex)
 	basicLit.Value = strconv.Quote(templatedString)
 
 	if rp.ExtractedStrings != nil {
 		rp.updateExtractedStrings(i18nStringInfo, templatedString)
 	}
 
-	rp.wrapCallExprWithTemplatedT(basicLit, callExpr, argIndex)
+	rp.wrapCallExprWithTemplatedT(basicLit, callExpr, argIndex, templateArgs)
 }
 
-func (rp *rewritePackage) wrapCallExprWithTemplatedT(basicLit *ast.BasicLit, callExpr *ast.CallExpr, argIndex int) {
-	templatedCallExpr := rp.wrapBasicLitWithTemplatedT(basicLit, callExpr.Args, callExpr, argIndex)
+func (rp *rewritePackage) wrapCallExprWithTemplatedT(basicLit *ast.BasicLit, callExpr *ast.CallExpr, argIndex int, templateArgs []common.TemplateArg) {
+	if !templateArgsFit(templateArgs, callExpr.Args, argIndex) {
+		rp.wrapExprArgs(callExpr.Args)
+		return
+	}
+
+	// the T() call takes the place of the templated string and of the args that follow it
+	argsPos := callExpr.Args[argIndex].Pos()
+	templatedCallExpr := rp.wrapBasicLitWithTemplatedT(basicLit, callExpr.Args, callExpr, argIndex, templateArgs)
 	if templatedCallExpr != callExpr {
+		rp.editor.replace(templatedCallExpr, argsPos, callExpr.Rparen)
 		newArgs := []ast.Expr{}
 
 		if argIndex != 0 {
@@ -473,58 +967,257 @@ func (rp *rewritePackage) wrapCallExprWithTemplatedT(basicLit *ast.BasicLit, cal
 	}
 }
 
+// namedTemplateArgs names the args of the templated string converted from a printf format string after the args of its
+// call, e.g., "{{.OrgName}}" for org.Name, an arg keeps its positional name when its value is not named, e.g., a literal
+func namedTemplateArgs(templatedString string, templateArgs []common.TemplateArg, args []ast.Expr, argIndex int) (string, []common.TemplateArg) {
+	renames := make(map[string]string)
+	taken := make(map[string]bool)
+	for i, templateArg := range templateArgs {
+		// the value of a verb with a "*" width or precision is its last arg
+		name := common.TemplateArgName(args[argIndex+templateArg.ArgIndexes[len(templateArg.ArgIndexes)-1]+1])
+		if name == "" {
+			name = templateArg.Name
+		}
+
+		templateArgs[i].Name = common.UniqueTemplateArgName(name, taken)
+		renames[templateArg.Name] = templateArgs[i].Name
+	}
+
+	return common.RenameTemplateArgs(templatedString, renames), templateArgs
+}
+
+// templatedStringArgs are the args of the templated string value, the args of its call following it in their order
+func templatedStringArgs(value string) []common.TemplateArg {
+	var templateArgs []common.TemplateArg
+	for i, argName := range common.GetTemplatedStringArgs(value) {
+		templateArgs = append(templateArgs, common.TemplateArg{Name: argName, ArgIndexes: []int{i}})
+	}
+
+	return templateArgs
+}
+
+// templateArgsFit is true when the call has the args of templateArgs after its templated string at argIndex
+func templateArgsFit(templateArgs []common.TemplateArg, args []ast.Expr, argIndex int) bool {
+	for _, templateArg := range templateArgs {
+		for _, index := range templateArg.ArgIndexes {
+			if argIndex+index+1 >= len(args) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 func (rp *rewritePackage) wrapExprArgs(exprArgs []ast.Expr) {
 	for i, _ := range exprArgs {
-		if basicLit, ok := exprArgs[i].(*ast.BasicLit); ok {
-			exprArgs[i] = rp.wrapBasicLitWithT(basicLit)
-		} else if callExpr, ok := exprArgs[i].(*ast.CallExpr); ok {
+		if callExpr, ok := exprArgs[i].(*ast.CallExpr); ok {
 			rp.callExprTFunc(callExpr)
+		} else {
+			exprArgs[i] = rp.wrapExprWithT(exprArgs[i])
 		}
 	}
 }
 
-func (rp *rewritePackage) wrapBasicLitWithTemplatedT(basicLit *ast.BasicLit, args []ast.Expr, callExpr *ast.CallExpr, argIndex int) ast.Expr {
+func (rp *rewritePackage) wrapBasicLitWithTemplatedT(basicLit *ast.BasicLit, args []ast.Expr, callExpr *ast.CallExpr, argIndex int, templateArgs []common.TemplateArg) ast.Expr {
 	valueWithoutQuotes, _ := strconv.Unquote(basicLit.Value) //basicLit.Value[1 : len(basicLit.Value)-1]
 
-	_, ok := rp.ExtractedStrings[valueWithoutQuotes]
-	if !ok && rp.ExtractedStrings != nil {
+	if !rp.shouldTranslate(basicLit, valueWithoutQuotes) {
 		return callExpr
 	}
 
 	rp.TotalStrings++
-	tIdent := &ast.Ident{Name: "T"}
-	argNames := common.GetTemplatedStringArgs(valueWithoutQuotes)
+	if rp.backend.formatsArgs() {
+		return rp.wrapBasicLitWithFormatT(basicLit, args, callExpr, argIndex, templateArgs)
+	}
 
 	compositeExpr := []ast.Expr{}
 	processedArgsMap := make(map[string]bool)
 
-	for i, argName := range argNames {
-		if callExpr, ok := args[argIndex+i+1].(*ast.CallExpr); ok {
-			rp.callExprTFunc(callExpr)
-		} else if basicLit, ok := args[argIndex+i+1].(*ast.BasicLit); ok {
-			args[argIndex+i] = rp.wrapBasicLitWithT(basicLit)
+	// the comments and line breaks between the args are kept in the map of the T() call
+	argSpans := make([]sourceSpan, len(args))
+	for j, arg := range args {
+		argSpans[j] = sourceSpan{pos: arg.Pos(), end: rp.editor.end(arg)}
+	}
+	var gaps []string
+
+	// the args are wrapped once, even when several template args share them
+	wrappedArgs := make(map[int]ast.Expr)
+	wrapArg := func(index int) ast.Expr {
+		if wrappedArg, ok := wrappedArgs[index]; ok {
+			return wrappedArg
 		}
 
-		if processedArgsMap[argName] != true {
-			quotedArgName := "\"" + argName + "\""
-			basicLit.ValuePos = 0
+		valueExpr := rp.wrapArgValue(args[index])
+		wrappedArgs[index] = valueExpr
+
+		return valueExpr
+	}
+
+	lastArg := argIndex
+	for _, templateArg := range templateArgs {
+		if processedArgsMap[templateArg.Name] == true {
+			continue
+		}
 
-			valueExpr := args[argIndex+i+1]
-			if basicLit, ok := args[argIndex+i+1].(*ast.BasicLit); ok {
-				valueExpr = rp.wrapBasicLitWithT(basicLit)
+		var valueArgs []ast.Expr
+		for _, index := range templateArg.ArgIndexes {
+			valueArgs = append(valueArgs, wrapArg(argIndex+index+1))
+			if argIndex+index+1 > lastArg {
+				lastArg = argIndex + index + 1
 			}
+		}
 
-			keyValueExpr := &ast.KeyValueExpr{Key: &ast.BasicLit{Kind: 9, Value: quotedArgName}, Value: valueExpr}
-			processedArgsMap[argName] = true
-			compositeExpr = append(compositeExpr, keyValueExpr)
+		// the formatting of the verb is kept by formatting the value before it reaches the template
+		valueExpr := valueArgs[len(valueArgs)-1]
+		if templateArg.Format != "" {
+			formatLit := &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(templateArg.Format)}
+			valueExpr = &ast.CallExpr{Fun: rp.fmtFunc("Sprintf"), Args: append([]ast.Expr{formatLit}, valueArgs...)}
 		}
+
+		quotedArgName := "\"" + templateArg.Name + "\""
+		keyValueExpr := &ast.KeyValueExpr{Key: &ast.BasicLit{Kind: token.STRING, Value: quotedArgName}, Value: valueExpr}
+
+		first := argIndex + templateArg.ArgIndexes[0] + 1
+		gaps = append(gaps, rp.editor.gap(argSpans[first-1].end, argSpans[first].pos, len(compositeExpr) == 0))
+		processedArgsMap[templateArg.Name] = true
+		compositeExpr = append(compositeExpr, keyValueExpr)
 	}
 
-	mapInterfaceType := &ast.InterfaceType{Interface: 142, Methods: &ast.FieldList{List: nil, Opening: 1, Closing: 2}, Incomplete: false}
-	mapType := &ast.MapType{Map: 131, Key: &ast.Ident{Name: "string"}, Value: mapInterfaceType}
+	if rp.usesIDs() {
+		basicLit.Value = strconv.Quote(rp.messageID(valueWithoutQuotes, ""))
+	}
+
+	mapInterfaceType := &ast.InterfaceType{Methods: &ast.FieldList{}}
+	mapType := &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: mapInterfaceType}
 	compositeLit := &ast.CompositeLit{Type: mapType, Elts: compositeExpr}
 
-	return &ast.CallExpr{Fun: tIdent, Args: []ast.Expr{basicLit, compositeLit}}
+	closing := ""
+	if lastArg == len(args)-1 {
+		closing = rp.editor.closing(argSpans[len(args)-1].end, callExpr.Rparen)
+	}
+	rp.editor.keepGaps(compositeLit, gaps, closing)
+
+	return rp.backend.translateCall(rp, translatedMessage{id: basicLit, args: compositeLit})
+}
+
+// wrapBasicLitWithFormatT wraps the string at argIndex of the args of callExpr with the call of a backend formatting the
+// args, e.g., Printer.Sprintf(format, args...), a templated string is turned into a format whose verbs are the values of
+// its args in order
+func (rp *rewritePackage) wrapBasicLitWithFormatT(basicLit *ast.BasicLit, args []ast.Expr, callExpr *ast.CallExpr, argIndex int, templateArgs []common.TemplateArg) ast.Expr {
+	valueWithoutQuotes, _ := strconv.Unquote(basicLit.Value)
+
+	format := valueWithoutQuotes
+	if common.IsTemplatedString(valueWithoutQuotes) {
+		format = templateToFormat(valueWithoutQuotes, templateArgs)
+	}
+
+	lastArg := argIndex
+	for _, templateArg := range templateArgs {
+		for _, index := range templateArg.ArgIndexes {
+			if argIndex+index+1 > lastArg {
+				lastArg = argIndex + index + 1
+			}
+		}
+	}
+
+	// the comments and line breaks between the args are kept in the call
+	formatArgs := []ast.Expr{}
+	gaps := []string{""}
+	for i := argIndex + 1; i <= lastArg; i++ {
+		gaps = append(gaps, rp.editor.gap(rp.editor.end(args[i-1]), args[i].Pos(), false))
+		formatArgs = append(formatArgs, rp.wrapArgValue(args[i]))
+	}
+
+	closing := ""
+	if lastArg == len(args)-1 {
+		closing = rp.editor.closing(rp.editor.end(args[len(args)-1]), callExpr.Rparen)
+	}
+
+	if format != valueWithoutQuotes && rp.ExtractedStrings != nil {
+		rp.updateExtractedStrings(rp.ExtractedStrings[valueWithoutQuotes], format)
+	}
+	if rp.usesIDs() {
+		basicLit.Value = strconv.Quote(rp.messageID(format, ""))
+	} else if format != valueWithoutQuotes {
+		basicLit.Value = strconv.Quote(format)
+	}
+
+	tCallExpr := rp.backend.translateCall(rp, translatedMessage{id: basicLit, formatArgs: formatArgs})
+	rp.editor.keepGaps(tCallExpr, gaps, closing)
+
+	return tCallExpr
+}
+
+// wrapArgValue wraps the value of an arg of a templated string with T() when it is a string, the strings of a call are
+// wrapped in place
+func (rp *rewritePackage) wrapArgValue(valueExpr ast.Expr) ast.Expr {
+	if callExpr, ok := valueExpr.(*ast.CallExpr); ok {
+		rp.callExprTFunc(callExpr)
+	} else if basicLit, ok := valueExpr.(*ast.BasicLit); ok {
+		valueExpr = rp.wrapBasicLitWithT(basicLit)
+	}
+
+	return valueExpr
+}
+
+// formatMessage escapes the % of the literals of a message without args for the backends whose messages are printf
+// formats, the message is renamed in the i18n strings and returned escaped
+func (rp *rewritePackage) formatMessage(basicLits []*ast.BasicLit, value, context string) string {
+	if !rp.backend.formatsArgs() || !strings.Contains(value, "%") {
+		return value
+	}
+
+	for _, basicLit := range basicLits {
+		if litValue, err := strconv.Unquote(basicLit.Value); err == nil {
+			basicLit.Value = strconv.Quote(escapeFormat(litValue))
+		}
+	}
+
+	format := escapeFormat(value)
+	key := common.MessageKey(context, value)
+	if i18nStringInfo, ok := rp.ExtractedStrings[key]; ok {
+		if !rp.usesIDs() {
+			i18nStringInfo.ID = format
+		}
+		i18nStringInfo.Translation = format
+
+		delete(rp.UpdatedExtractedStrings, key)
+		rp.ExtractedStrings[common.MessageKey(context, format)] = i18nStringInfo
+		rp.UpdatedExtractedStrings[common.MessageKey(context, format)] = i18nStringInfo
+		rp.SaveExtractedStrings = true
+	}
+
+	return format
+}
+
+// wrapExprWithT wraps a string literal, or a concatenation that folds into one constant string, with T()
+func (rp *rewritePackage) wrapExprWithT(expr ast.Expr) ast.Expr {
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		return rp.wrapBasicLitWithT(x)
+	case *ast.BinaryExpr:
+		if x.Op != token.ADD {
+			return expr
+		}
+		if merged := rp.mergeConcatenationTFunc(x); merged != nil {
+			return merged
+		}
+
+		operands, _ := common.Concatenation(x)
+		foldedStrings := common.NewConstFolder(nil).FoldConcatenation(x)
+		if len(foldedStrings) != 1 || len(foldedStrings[0].Operands) != len(operands) || !rp.shouldTranslateFolded(foldedStrings[0]) {
+			return expr
+		}
+
+		rp.TotalStrings++
+		end := x.End()
+		value := rp.formatMessage(foldedStrings[0].Lits, foldedStrings[0].Value, "")
+		tCallExpr := rp.backend.translateCall(rp, translatedMessage{id: rp.messageIDExpr(x, value, "")})
+		return rp.editor.replace(tCallExpr, x.Pos(), end)
+	}
+
+	return expr
 }
 
 func (rp *rewritePackage) wrapBasicLitWithT(basicLit *ast.BasicLit) ast.Expr {
@@ -533,21 +1226,64 @@ func (rp *rewritePackage) wrapBasicLitWithT(basicLit *ast.BasicLit) ast.Expr {
 	}
 
 	valueWithoutQuotes, _ := strconv.Unquote(basicLit.Value) //basicLit.Value[1 : len(basicLit.Value)-1]
-	_, ok := rp.ExtractedStrings[valueWithoutQuotes]
-	if !ok && rp.ExtractedStrings != nil {
+	context := rp.directives.Context(basicLit)
+	if !rp.shouldTranslateValue(valueWithoutQuotes, context, rp.directives.Translates(basicLit), rp.directives.Ignores(basicLit)) {
 		return basicLit
 	}
 
 	rp.TotalStrings++
-	tIdent := &ast.Ident{Name: "T"}
-	return &ast.CallExpr{Fun: tIdent, Args: []ast.Expr{basicLit}}
+	valueWithoutQuotes = rp.formatMessage([]*ast.BasicLit{basicLit}, valueWithoutQuotes, context)
+	idExpr := rp.messageIDExpr(basicLit, valueWithoutQuotes, context)
+	tCallExpr := rp.backend.translateCall(rp, translatedMessage{id: idExpr, context: context})
+	return rp.editor.replace(tCallExpr, basicLit.Pos(), rp.editor.end(basicLit))
+}
+
+// shouldTranslate is false for the strings ignored by a directive and for the strings missing from the i18n strings file,
+// unless a translate directive forces them in, they are then added to the i18n strings file
+func (rp *rewritePackage) shouldTranslate(basicLit *ast.BasicLit, value string) bool {
+	return rp.shouldTranslateValue(value, "", rp.directives.Translates(basicLit), rp.directives.Ignores(basicLit))
+}
+
+// shouldTranslateFolded is shouldTranslate for the literals of a folded concatenation, a directive on any of them applies
+func (rp *rewritePackage) shouldTranslateFolded(foldedString common.FoldedString) bool {
+	translated, ignored := false, false
+	for _, basicLit := range foldedString.Lits {
+		translated = translated || rp.directives.Translates(basicLit)
+		ignored = ignored || rp.directives.Ignores(basicLit)
+	}
+
+	return rp.shouldTranslateValue(foldedString.Value, "", translated, ignored)
+}
+
+// shouldTranslateValue looks the string up by its message key, a string with a context directive is only
+// translated when the i18n strings file has it in that context
+func (rp *rewritePackage) shouldTranslateValue(value, context string, translated, ignored bool) bool {
+	key := common.MessageKey(context, value)
+	if translated {
+		if _, ok := rp.ExtractedStrings[key]; !ok && rp.ExtractedStrings != nil {
+			i18nStringInfo := common.I18nStringInfo{ID: rp.idGenerator.ID(rp.funcName, context, value), Translation: value, Context: context}
+			rp.ExtractedStrings[key] = i18nStringInfo
+			rp.UpdatedExtractedStrings[key] = i18nStringInfo
+			rp.SaveExtractedStrings = true
+		}
+		addStringKey(rp.wrappedStrings, rp.I18nStringsFilename, key)
+		return true
+	}
+
+	if ignored {
+		return false
+	}
+
+	_, ok := rp.ExtractedStrings[key]
+	if ok {
+		addStringKey(rp.wrappedStrings, rp.I18nStringsFilename, key)
+	}
+	return ok || rp.ExtractedStrings == nil
 }
 
 func (rp *rewritePackage) addInitFuncToPackage(packageName, outputDir, importPath string) error {
 	rp.Println("i18n4go: adding init func to package:", packageName, " to output dir:", outputDir)
 
-	common.CreateOutputDirsIfNeeded(outputDir)
-
 	pieces := strings.Split(importPath, "/")
 	for index, str := range pieces {
 		pieces[index] = `"` + str + `"`
@@ -556,11 +1292,32 @@ func (rp *rewritePackage) addInitFuncToPackage(packageName, outputDir, importPat
 	joinedImportPath := "filepath.Join(" + strings.Join(pieces, ", ") + ")"
 	content := rp.getInitFuncCodeSnippetContent(packageName, joinedImportPath)
 
-	return ioutil.WriteFile(filepath.Join(outputDir, "i18n_init.go"), []byte(content), 0666)
+	if rp.EmbedDirname != "" {
+		embedDir := filepath.Join(outputDir, filepath.FromSlash(rp.EmbedDirname))
+		if _, err := os.Stat(embedDir); err != nil {
+			fmt.Fprintf(rp.output(), "i18n4go: WARNING the package %s does not compile without the locale dir %s it embeds\n", packageName, embedDir)
+		}
+	}
+
+	return rp.writeFile(filepath.Join(outputDir, "i18n_init.go"), []byte(content), 0666)
+}
+
+// checkEmbedDirname checks that the locale dir given to embed is a path go:embed accepts, relative to the package and
+// without . or .. elements
+func (rp *rewritePackage) checkEmbedDirname() error {
+	if rp.EmbedDirname == "" {
+		return nil
+	}
+
+	if !fs.ValidPath(rp.EmbedDirname) || rp.EmbedDirname == "." || strings.ContainsAny(rp.EmbedDirname, " \"'`") {
+		return fmt.Errorf("i18n4go: invalid --embed-dirname %s, it must be a dir of the package, relative to it", rp.EmbedDirname)
+	}
+
+	return nil
 }
 
 func (rp *rewritePackage) getInitFuncCodeSnippetContent(packageName, importPath string) string {
-	snippetContent := INIT_CODE_SNIPPET
+	snippetContent := rp.backend.initCodeSnippet(rp.EmbedDirname != "")
 	if rp.InitCodeSnippetFilename != "" {
 		bytes, err := ioutil.ReadFile(rp.InitCodeSnippetFilename)
 		if err != nil {
@@ -572,12 +1329,14 @@ func (rp *rewritePackage) getInitFuncCodeSnippetContent(packageName, importPath
 
 	content := strings.Replace(snippetContent, "__PACKAGE__NAME__", packageName, -1)
 	content = strings.Replace(content, "__FULL_IMPORT_PATH__", importPath, -1)
+	content = strings.Replace(content, "__EMBED_DIRNAME__", rp.EmbedDirname, -1)
 	return content
 }
 
-func (rp *rewritePackage) saveASTFile(relativeFilePath, fileName string, astFile *ast.File, fileSet *token.FileSet) error {
-	var buffer bytes.Buffer
-	if err := format.Node(&buffer, fileSet, astFile); err != nil {
+// saveASTFile saves the source of the rewritten file, only the spans of the rewritten expressions differ from the original
+func (rp *rewritePackage) saveASTFile(relativeFilePath, fileName string, astFile *ast.File) error {
+	content, err := rp.editor.Source(astFile)
+	if err != nil {
 		return err
 	}
 
@@ -587,12 +1346,9 @@ func (rp *rewritePackage) saveASTFile(relativeFilePath, fileName string, astFile
 		return err
 	}
 
-	common.CreateOutputDirsIfNeeded(filepath.Dir(pathToFile))
-
 	rp.Println("saving file to path", pathToFile)
-	ioutil.WriteFile(pathToFile, buffer.Bytes(), fileInfo.Mode())
-
-	return nil
+	rp.addSourceFile(pathToFile, fileName)
+	return rp.writeFile(pathToFile, content, fileInfo.Mode())
 }
 
 func (rp *rewritePackage) relativePathForFile(fileName string) string {
@@ -604,14 +1360,177 @@ func (rp *rewritePackage) relativePathForFile(fileName string) string {
 }
 
 func (rp *rewritePackage) updateExtractedStrings(i18nStringInfo common.I18nStringInfo, templatedString string) {
-	oldID := i18nStringInfo.ID
+	oldKey := rp.lookupKey(i18nStringInfo)
 
-	i18nStringInfo.ID = templatedString
+	i18nStringInfo.ID = rp.idGenerator.ID(rp.funcName, i18nStringInfo.Context, templatedString)
 	i18nStringInfo.Translation = templatedString
 
 	rp.ExtractedStrings[templatedString] = i18nStringInfo
 	rp.UpdatedExtractedStrings[templatedString] = i18nStringInfo
-	delete(rp.UpdatedExtractedStrings, oldID)
+	delete(rp.UpdatedExtractedStrings, oldKey)
 
 	rp.SaveExtractedStrings = true
 }
+
+func (rp *rewritePackage) idStrategy() string {
+	if rp.options.IdStrategyFlag == "" {
+		return common.SOURCE_ID_STRATEGY
+	}
+
+	return rp.options.IdStrategyFlag
+}
+
+// usesIDs is true when T() is called with IDs rather than with the source text
+func (rp *rewritePackage) usesIDs() bool {
+	return rp.idStrategy() != common.SOURCE_ID_STRATEGY
+}
+
+// lookupKey is the key of a string in ExtractedStrings, the strings are keyed by their source text when T() is called with IDs
+func (rp *rewritePackage) lookupKey(i18nStringInfo common.I18nStringInfo) string {
+	if rp.usesIDs() {
+		return common.MessageKey(i18nStringInfo.Context, i18nStringInfo.Translation)
+	}
+
+	return i18nStringInfo.Key()
+}
+
+// messageID returns the ID T() is called with for the source text value, the ID of the i18n strings file when it has one
+// that follows the ID strategy, otherwise a new ID that is saved to the i18n strings file, or to the source language file
+// when there is none
+func (rp *rewritePackage) messageID(value, context string) string {
+	if !rp.usesIDs() {
+		return value
+	}
+
+	key := common.MessageKey(context, value)
+	if i18nStringInfo, ok := rp.SourceStrings[key]; ok {
+		return i18nStringInfo.ID
+	}
+
+	i18nStringInfo, ok := rp.ExtractedStrings[key]
+	if ok && i18nStringInfo.ID != value && common.MatchesIDStrategy(rp.idStrategy(), i18nStringInfo.ID) {
+		return i18nStringInfo.ID
+	}
+
+	id := rp.idGenerator.ID(rp.funcName, context, value)
+	if ok {
+		i18nStringInfo.ID = id
+		rp.ExtractedStrings[key] = i18nStringInfo
+		rp.UpdatedExtractedStrings[key] = i18nStringInfo
+		rp.SaveExtractedStrings = true
+	} else {
+		rp.SourceStrings[key] = common.I18nStringInfo{ID: id, Context: context, Translation: value}
+	}
+
+	return id
+}
+
+// messageIDExpr is expr, the source text of a message, or a literal of its ID when T() is called with IDs
+func (rp *rewritePackage) messageIDExpr(expr ast.Expr, value, context string) ast.Expr {
+	if !rp.usesIDs() {
+		return expr
+	}
+
+	return &ast.BasicLit{ValuePos: expr.Pos(), Kind: token.STRING, Value: strconv.Quote(rp.messageID(value, context))}
+}
+
+// saveSourceStrings adds the messages given an ID without an i18n strings file to the source language file of the output dir
+func (rp *rewritePackage) saveSourceStrings() error {
+	fileName := filepath.Join(rp.OutputDirname, rp.options.SourceLanguageFlag+".all.json")
+	rp.Println("i18n4go: saving the messages given an ID to the source language file:", fileName)
+
+	sourceStrings := make(map[string]common.I18nStringInfo)
+	if i18nStringInfos, err := rp.loadI18nStringInfos(fileName); err == nil {
+		for _, i18nStringInfo := range i18nStringInfos {
+			sourceStrings[i18nStringInfo.Key()] = i18nStringInfo
+		}
+	}
+
+	for _, i18nStringInfo := range rp.SourceStrings {
+		sourceStrings[i18nStringInfo.Key()] = i18nStringInfo
+	}
+
+	i18nStringInfos := common.I18nStringInfoMapValues2Array(sourceStrings)
+	sort.Slice(i18nStringInfos, func(i, j int) bool {
+		return i18nStringInfos[i].Key() < i18nStringInfos[j].Key()
+	})
+
+	return rp.saveI18nStringInfos(i18nStringInfos, fileName)
+}
+
+// saveI18nStringInfos records the change of an i18n strings file, it is not saved with --dry-run
+func (rp *rewritePackage) saveI18nStringInfos(i18nStringInfos []common.I18nStringInfo, fileName string) error {
+	if len(i18nStringInfos) == 0 || rp.options.DryRunFlag && !rp.previewing() {
+		return nil
+	}
+
+	jsonData, err := common.MarshalI18nStringInfos(i18nStringInfos)
+	if err != nil {
+		return err
+	}
+
+	return rp.writeFile(fileName, jsonData, 0644)
+}
+
+// writeFile records the change of fileName to content, the changes are written together once the rewritten packages
+// compile, or reported when they are previewed
+func (rp *rewritePackage) writeFile(fileName string, content []byte, mode os.FileMode) error {
+	change := common.FileChange{FileName: rp.changeFileName(fileName), Path: fileName, Mode: mode, New: content}
+	oldContent, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		change.Created = true
+	} else if err != nil {
+		return err
+	}
+	change.Old = oldContent
+
+	// a file written again, e.g., the i18n_init.go of a package, is one change
+	index, ok := rp.changeIndexes[change.FileName]
+	if !change.Changed() {
+		if ok {
+			rp.changes = append(rp.changes[:index], rp.changes[index+1:]...)
+			rp.reindexChanges()
+		}
+		return nil
+	}
+
+	if ok {
+		change.Old, change.Created = rp.changes[index].Old, rp.changes[index].Created
+		rp.changes[index] = change
+	} else {
+		rp.changeIndexes[change.FileName] = len(rp.changes)
+		rp.changes = append(rp.changes, change)
+	}
+
+	return nil
+}
+
+func (rp *rewritePackage) reindexChanges() {
+	rp.changeIndexes = make(map[string]int, len(rp.changes))
+	for index, change := range rp.changes {
+		rp.changeIndexes[change.FileName] = index
+	}
+}
+
+// changeFileName is the name of a changed file in diffs and patches, relative to the root path,
+// or to the working directory for files out of it, with slashes
+func (rp *rewritePackage) changeFileName(fileName string) string {
+	absFileName, err := filepath.Abs(fileName)
+	if err != nil {
+		return filepath.ToSlash(fileName)
+	}
+
+	for _, root := range []string{rp.RootPath, os.Getenv("PWD")} {
+		if root == "" {
+			continue
+		}
+		if root, err = filepath.Abs(root); err != nil {
+			continue
+		}
+		if relFileName, err := filepath.Rel(root, absFileName); err == nil && !strings.HasPrefix(relFileName, "..") {
+			return filepath.ToSlash(relFileName)
+		}
+	}
+
+	return filepath.ToSlash(fileName)
+}
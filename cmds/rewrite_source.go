@@ -0,0 +1,518 @@
+package cmds
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"go/ast"
+	"go/format"
+	"go/printer"
+	"go/token"
+
+	"github.com/Liam-Williams/i18n4go/common"
+)
+
+// sourceEditor turns the changes made to the AST of a file into edits of its source, only the spans of the rewritten
+// expressions change, the comments and the layout of the rest of the file are kept byte for byte
+type sourceEditor struct {
+	file     *token.File
+	src      []byte
+	comments []*ast.CommentGroup
+
+	// the nodes parsed from the source and the values their string literals had
+	original map[ast.Node]bool
+	values   map[*ast.BasicLit]string
+
+	// the new nodes that replace a span of the source, e.g., the T() call wrapping a string literal
+	replaced map[ast.Node]sourceSpan
+	// the new nodes that regroup original nodes and print as the span of the source they cover
+	regrouped map[ast.Node]sourceSpan
+
+	// the source kept before the elements of a new composite literal, or the args of a new call, and before its closing
+	gaps     map[ast.Node][]string
+	closings map[ast.Node]string
+
+	// the original nodes whose source is rewritten as a whole, e.g., a constant turned into a func
+	rewritten map[ast.Node]sourceRewrite
+	// the source rewritten outside of the nodes, e.g., the keyword of a declaration
+	rewrites []sourceRewrite
+
+	// the imports added to the source
+	imports []sourceImport
+}
+
+type sourceRewrite struct {
+	span sourceSpan
+	text string
+}
+
+type sourceImport struct {
+	name string
+	path string
+}
+
+type sourceSpan struct {
+	pos token.Pos
+	end token.Pos
+}
+
+func newSourceEditor(fileSet *token.FileSet, astFile *ast.File, src []byte) *sourceEditor {
+	editor := &sourceEditor{
+		file:      fileSet.File(astFile.Pos()),
+		src:       src,
+		comments:  astFile.Comments,
+		original:  make(map[ast.Node]bool),
+		values:    make(map[*ast.BasicLit]string),
+		replaced:  make(map[ast.Node]sourceSpan),
+		regrouped: make(map[ast.Node]sourceSpan),
+		gaps:      make(map[ast.Node][]string),
+		closings:  make(map[ast.Node]string),
+		rewritten: make(map[ast.Node]sourceRewrite),
+	}
+
+	ast.Inspect(astFile, func(node ast.Node) bool {
+		if node != nil {
+			editor.original[node] = true
+			if basicLit, ok := node.(*ast.BasicLit); ok {
+				editor.values[basicLit] = basicLit.Value
+			}
+		}
+		return true
+	})
+
+	return editor
+}
+
+// replace records that the new node expr takes the place of the source from pos up to end, expr is returned
+func (e *sourceEditor) replace(expr ast.Expr, pos, end token.Pos) ast.Expr {
+	e.replaced[expr] = sourceSpan{pos: pos, end: end}
+	return expr
+}
+
+// regroup records that the new node expr is made of the original nodes from pos up to end, expr is returned
+func (e *sourceEditor) regroup(expr ast.Expr, pos, end token.Pos) ast.Expr {
+	e.regrouped[expr] = sourceSpan{pos: pos, end: end}
+	return expr
+}
+
+// rewrite records that the source of the original node, from pos up to end, becomes text
+func (e *sourceEditor) rewrite(node ast.Node, pos, end token.Pos, text string) {
+	e.rewritten[node] = sourceRewrite{span: sourceSpan{pos: pos, end: end}, text: text}
+}
+
+// rewriteSource records that the source from pos up to end becomes text, the span must not hold a changed node,
+// an empty span inserts text
+func (e *sourceEditor) rewriteSource(pos, end token.Pos, text string) {
+	e.rewrites = append(e.rewrites, sourceRewrite{span: sourceSpan{pos: pos, end: end}, text: text})
+}
+
+// sourceText is the original source from pos up to end
+func (e *sourceEditor) sourceText(pos, end token.Pos) string {
+	return string(e.src[e.file.Offset(pos):e.file.Offset(end)])
+}
+
+// lineSpan extends the span from pos up to end to the whole lines it is on, the line break of its last line included
+func (e *sourceEditor) lineSpan(pos, end token.Pos) sourceSpan {
+	lineStart := e.file.LineStart(e.file.Line(pos))
+	if line := e.file.Line(end); line < e.file.LineCount() {
+		return sourceSpan{pos: lineStart, end: e.file.LineStart(line + 1)}
+	}
+
+	return sourceSpan{pos: lineStart, end: token.Pos(e.file.Base() + e.file.Size())}
+}
+
+// keepGaps records the source kept before each element of the new composite literal, or each arg of the new call, node
+// and before its closing brace or parenthesis
+func (e *sourceEditor) keepGaps(node ast.Node, gaps []string, closing string) {
+	e.gaps[node] = gaps
+	e.closings[node] = closing
+}
+
+// gap is the source from pos up to end when it has comments or line breaks, otherwise the separator of the elements of
+// a composite literal, the comma of the source before the first element is dropped
+func (e *sourceEditor) gap(pos, end token.Pos, first bool) string {
+	gap := string(e.src[e.file.Offset(pos):e.file.Offset(end)])
+	separator := ", "
+	if first {
+		gap = strings.TrimPrefix(strings.TrimLeft(gap, " \t"), ",")
+		separator = ""
+	}
+
+	if !keepsLayout(gap) {
+		return separator
+	}
+
+	return gap
+}
+
+// closing is the source from pos up to end kept before the closing brace of a composite literal, when it has
+// comments or line breaks
+func (e *sourceEditor) closing(pos, end token.Pos) string {
+	closing := string(e.src[e.file.Offset(pos):e.file.Offset(end)])
+	if !keepsLayout(closing) {
+		return ""
+	}
+
+	return closing
+}
+
+func keepsLayout(s string) bool {
+	return strings.Contains(s, "\n") || strings.Contains(s, "//") || strings.Contains(s, "/*")
+}
+
+// addImport records the import of importPath named name, or unnamed when name is empty, it is added to the source
+func (e *sourceEditor) addImport(name, importPath string) {
+	for _, newImport := range e.imports {
+		if newImport.path == importPath {
+			return
+		}
+	}
+
+	e.imports = append(e.imports, sourceImport{name: name, path: importPath})
+}
+
+func (i sourceImport) spec() string {
+	if i.name == "" {
+		return strconv.Quote(i.path)
+	}
+
+	return i.name + " " + strconv.Quote(i.path)
+}
+
+// importEdits adds each recorded import after the import sharing the longest prefix with it, in a group of its own when
+// it is the first import in or out of the standard library, like astutil does. A declaration of one import gets
+// parenthesized, a file without imports gets them after its package clause
+func (e *sourceEditor) importEdits(astFile *ast.File) []common.TextEdit {
+	if len(e.imports) == 0 {
+		return nil
+	}
+
+	var importDecl *ast.GenDecl
+	for _, decl := range astFile.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			importDecl = genDecl
+		}
+	}
+
+	if importDecl == nil {
+		offset := e.file.Offset(astFile.Name.End())
+		return []common.TextEdit{{Offset: offset, End: offset, Text: "\n\nimport " + importGroups(nil, e.imports)}}
+	}
+
+	if !importDecl.Lparen.IsValid() {
+		spec := importDecl.Specs[0].(*ast.ImportSpec)
+		offset, end := e.file.Offset(spec.Pos()), e.file.Offset(spec.End())
+		existing := string(e.src[offset:end])
+		return []common.TextEdit{{Offset: offset, End: end, Text: importGroups([]string{existing}, e.imports)}}
+	}
+
+	var edits []common.TextEdit
+	for _, newImport := range e.imports {
+		var after, sameKind *ast.ImportSpec
+		bestMatch := -1
+		for _, spec := range importDecl.Specs {
+			importSpec := spec.(*ast.ImportSpec)
+			specPath, _ := strconv.Unquote(importSpec.Path.Value)
+			if match := importPathMatch(specPath, newImport.path); match >= bestMatch {
+				after, bestMatch = importSpec, match
+			}
+			if isStandardImport(specPath) == isStandardImport(newImport.path) {
+				sameKind = importSpec
+			}
+		}
+
+		separator := "\n\t"
+		if bestMatch == 0 {
+			if sameKind != nil {
+				after = sameKind
+			} else if isStandardImport(newImport.path) {
+				// the first import of the standard library goes before the others
+				offset := e.file.Offset(importDecl.Specs[0].Pos())
+				edits = append(edits, common.TextEdit{Offset: offset, End: offset, Text: newImport.spec() + "\n\n\t"})
+				continue
+			} else {
+				separator = "\n\n\t"
+			}
+		}
+
+		// the import goes on the next line, after the comment of the import it follows
+		offset := e.file.Offset(after.End())
+		if newline := bytes.IndexByte(e.src[offset:], '\n'); newline != -1 {
+			offset += newline
+		}
+		edits = append(edits, common.TextEdit{Offset: offset, End: offset, Text: separator + newImport.spec()})
+	}
+
+	return edits
+}
+
+// importGroups is the parenthesized import specs of a declaration, the imports of the standard library first, a single
+// import is not parenthesized
+func importGroups(existing []string, newImports []sourceImport) string {
+	var standard, others []string
+	for _, spec := range existing {
+		if importPath, err := strconv.Unquote(spec[strings.LastIndex(spec, " ")+1:]); err == nil && !isStandardImport(importPath) {
+			others = append(others, spec)
+		} else {
+			standard = append(standard, spec)
+		}
+	}
+	for _, newImport := range newImports {
+		if isStandardImport(newImport.path) {
+			standard = append(standard, newImport.spec())
+		} else {
+			others = append(others, newImport.spec())
+		}
+	}
+
+	var groups []string
+	for _, group := range [][]string{standard, others} {
+		if len(group) > 0 {
+			groups = append(groups, strings.Join(group, "\n\t"))
+		}
+	}
+
+	if len(standard)+len(others) == 1 {
+		return groups[0]
+	}
+
+	return "(\n\t" + strings.Join(groups, "\n\n\t") + "\n)"
+}
+
+// importPathMatch is the number of leading elements two import paths share
+func importPathMatch(path1, path2 string) int {
+	elements1, elements2 := strings.Split(path1, "/"), strings.Split(path2, "/")
+	match := 0
+	for match < len(elements1) && match < len(elements2) && elements1[match] == elements2[match] {
+		match++
+	}
+
+	return match
+}
+
+// isStandardImport is true for the import paths of the standard library, their first element has no dot
+func isStandardImport(importPath string) bool {
+	return !strings.Contains(strings.Split(importPath, "/")[0], ".")
+}
+
+// end is the end of node in the source, a string literal whose value was rewritten ends where its original value did
+func (e *sourceEditor) end(node ast.Node) token.Pos {
+	if basicLit, ok := node.(*ast.BasicLit); ok && e.original[node] {
+		return basicLit.Pos() + token.Pos(len(e.values[basicLit]))
+	}
+
+	return node.End()
+}
+
+// Source returns the source of the file with the changes made to its AST, the edited lines of a file formatted with
+// gofmt are formatted again so the spacing of the rewritten expressions stays gofmt-ed, the other lines are kept as is
+func (e *sourceEditor) Source(astFile *ast.File) ([]byte, error) {
+	edits := append(e.edits(astFile, 0, len(e.src)), e.importEdits(astFile)...)
+	for _, rewrite := range e.rewrites {
+		edits = append(edits, e.edit(rewrite.span, rewrite.text, 0))
+	}
+
+	src, err := common.ApplyTextEdits(e.src, edits)
+	if err != nil {
+		return nil, err
+	}
+
+	if formatted, err := format.Source(e.src); err == nil && bytes.Equal(formatted, e.src) {
+		if formatted, err = format.Source(src); err == nil {
+			return common.ChangeLines(src, formatted, common.EditedLines(e.src, edits)), nil
+		}
+	}
+
+	return src, nil
+}
+
+// edits returns the edits of the source in node, their offsets are relative to base and they end before limit
+func (e *sourceEditor) edits(node ast.Node, base, limit int) []common.TextEdit {
+	var edits []common.TextEdit
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+
+		if rewrite, ok := e.rewritten[n]; ok {
+			edits = append(edits, e.edit(rewrite.span, rewrite.text, base))
+			return false
+		}
+
+		if span, ok := e.replaced[n]; ok {
+			text := e.text(n)
+			edits = append(edits, e.commentEdits(span, &text, base, limit)...)
+			edits = append(edits, e.edit(span, text, base))
+			return false
+		}
+
+		if basicLit, ok := n.(*ast.BasicLit); ok && e.original[n] && basicLit.Value != e.values[basicLit] {
+			edits = append(edits, e.edit(sourceSpan{pos: basicLit.Pos(), end: e.end(basicLit)}, basicLit.Value, base))
+			return false
+		}
+
+		return true
+	})
+
+	return edits
+}
+
+// commentEdits keeps the comments of the replaced span that its new text dropped, e.g., the comments between the strings
+// of a concatenation replaced by an ID. The block comments follow the new text, the line comments are moved to the end
+// of the line the span ends on, before its own line comment if it has one
+func (e *sourceEditor) commentEdits(span sourceSpan, text *string, base, limit int) []common.TextEdit {
+	var lineComments []string
+	for _, commentGroup := range e.comments {
+		if commentGroup.Pos() < span.pos || commentGroup.End() > span.end {
+			continue
+		}
+
+		for _, comment := range commentGroup.List {
+			if strings.Contains(*text, comment.Text) {
+				continue
+			}
+
+			if strings.HasPrefix(comment.Text, "/*") {
+				*text += " " + comment.Text
+			} else {
+				lineComments = append(lineComments, comment.Text)
+			}
+		}
+	}
+
+	if len(lineComments) == 0 {
+		return nil
+	}
+
+	offset, beforeComment := e.lineCommentOffset(span.end)
+	if offset > limit {
+		// the end of the line is out of the edited source, the line comments become block comments following the new text
+		for _, lineComment := range lineComments {
+			*text += " /*" + strings.TrimPrefix(lineComment, "//") + " */"
+		}
+		return nil
+	}
+
+	moved := " " + strings.Join(lineComments, " ")
+	if beforeComment {
+		moved = strings.Join(lineComments, " ") + " "
+	}
+
+	return []common.TextEdit{{Offset: offset - base, End: offset - base, Text: moved}}
+}
+
+// lineCommentOffset is the offset where line comments are moved to the end of the line of pos, the start of the line
+// comment of that line, true, otherwise the end of the line, past the block comments running over it
+func (e *sourceEditor) lineCommentOffset(pos token.Pos) (int, bool) {
+	offset := e.file.Offset(pos)
+	lineEnd := len(e.src)
+	if newline := bytes.IndexByte(e.src[offset:], '\n'); newline != -1 {
+		lineEnd = offset + newline
+	}
+
+	for _, commentGroup := range e.comments {
+		for _, comment := range commentGroup.List {
+			start, end := e.file.Offset(comment.Pos()), e.file.Offset(comment.End())
+			if start < offset || start >= lineEnd {
+				continue
+			}
+
+			if strings.HasPrefix(comment.Text, "//") {
+				return start, true
+			}
+
+			if end > lineEnd {
+				lineEnd = len(e.src)
+				if newline := bytes.IndexByte(e.src[end:], '\n'); newline != -1 {
+					lineEnd = end + newline
+				}
+			}
+		}
+	}
+
+	return lineEnd, false
+}
+
+func (e *sourceEditor) edit(span sourceSpan, text string, base int) common.TextEdit {
+	return common.TextEdit{Offset: e.file.Offset(span.pos) - base, End: e.file.Offset(span.end) - base, Text: text}
+}
+
+// text is the source of node, an original node keeps its source with the edits of its children,
+// a new node is printed on one line around the source of the original nodes it holds
+func (e *sourceEditor) text(node ast.Node) string {
+	if span, ok := e.regrouped[node]; ok {
+		return e.spanText(node, span)
+	}
+
+	if e.original[node] {
+		if basicLit, ok := node.(*ast.BasicLit); ok {
+			return basicLit.Value
+		}
+		return e.spanText(node, sourceSpan{pos: node.Pos(), end: e.end(node)})
+	}
+
+	switch x := node.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.BasicLit:
+		return x.Value
+	case *ast.SelectorExpr:
+		return e.text(x.X) + "." + x.Sel.Name
+	case *ast.CallExpr:
+		if gaps, ok := e.gaps[x]; ok && len(gaps) == len(x.Args) {
+			text := e.text(x.Fun) + "("
+			for i, arg := range x.Args {
+				text += gaps[i] + e.text(arg)
+			}
+			return text + e.closings[x] + ")"
+		}
+
+		var args []string
+		for _, arg := range x.Args {
+			args = append(args, e.text(arg))
+		}
+		return e.text(x.Fun) + "(" + strings.Join(args, ", ") + ")"
+	case *ast.BinaryExpr:
+		return e.text(x.X) + " " + x.Op.String() + " " + e.text(x.Y)
+	case *ast.UnaryExpr:
+		return x.Op.String() + e.text(x.X)
+	case *ast.KeyValueExpr:
+		return e.text(x.Key) + ": " + e.text(x.Value)
+	case *ast.CompositeLit:
+		if gaps, ok := e.gaps[x]; ok && len(gaps) == len(x.Elts) {
+			text := e.text(x.Type) + "{"
+			for i, elt := range x.Elts {
+				text += gaps[i] + e.text(elt)
+			}
+			return text + e.closings[x] + "}"
+		}
+
+		var elts []string
+		for _, elt := range x.Elts {
+			elts = append(elts, e.text(elt))
+		}
+		return e.text(x.Type) + "{" + strings.Join(elts, ", ") + "}"
+	case *ast.MapType:
+		return "map[" + e.text(x.Key) + "]" + e.text(x.Value)
+	case *ast.InterfaceType:
+		if len(x.Methods.List) == 0 {
+			return "interface{}"
+		}
+	}
+
+	var buffer bytes.Buffer
+	printer.Fprint(&buffer, token.NewFileSet(), node)
+	return buffer.String()
+}
+
+// spanText is the source of the span of node with the edits of the children of node
+func (e *sourceEditor) spanText(node ast.Node, span sourceSpan) string {
+	offset, end := e.file.Offset(span.pos), e.file.Offset(span.end)
+	text, err := common.ApplyTextEdits(e.src[offset:end], e.edits(node, offset, end))
+	if err != nil {
+		return string(e.src[offset:end])
+	}
+
+	return string(text)
+}
@@ -0,0 +1,222 @@
+package cmds
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go/types"
+
+	"github.com/Liam-Williams/i18n4go/common"
+)
+
+// addSourceFile records that the rewritten Go file outputFileName replaces sourceFileName in its package
+func (rp *rewritePackage) addSourceFile(outputFileName, sourceFileName string) {
+	absOutputFileName, err := filepath.Abs(outputFileName)
+	if err != nil {
+		return
+	}
+	absSourceFileName, err := filepath.Abs(sourceFileName)
+	if err != nil {
+		return
+	}
+
+	rp.sourceFiles[absOutputFileName] = absSourceFileName
+}
+
+// loadI18nStringInfos loads an i18n strings file, with the content the rewrite gave it when it changed
+func (rp *rewritePackage) loadI18nStringInfos(fileName string) ([]common.I18nStringInfo, error) {
+	if index, ok := rp.changeIndexes[rp.changeFileName(fileName)]; ok {
+		return common.ParseI18nStringInfos(rp.changes[index].New)
+	}
+
+	return common.LoadI18nStringInfos(fileName)
+}
+
+// commitChanges writes the changes once the rewritten packages compile, the journal to undo them is saved first to the
+// output dir. The files already written are restored when one cannot be
+func (rp *rewritePackage) commitChanges() error {
+	if len(rp.changes) == 0 {
+		return nil
+	}
+
+	err := rp.verifyChanges()
+	if err != nil {
+		return err
+	}
+
+	journal, err := common.NewRewriteJournal(rp.changes)
+	if err != nil {
+		return err
+	}
+
+	journalFileName := filepath.Join(rp.OutputDirname, common.REWRITE_JOURNAL_FILENAME)
+	rp.Println("i18n4go: saving the undo journal of the rewrite to:", journalFileName)
+	err = common.CreateOutputDirsIfNeeded(rp.OutputDirname)
+	if err != nil {
+		return err
+	}
+	err = common.SaveRewriteJournal(journal, journalFileName)
+	if err != nil {
+		return err
+	}
+
+	for index, change := range rp.changes {
+		err = common.CreateOutputDirsIfNeeded(filepath.Dir(change.Path))
+		if err == nil {
+			err = ioutil.WriteFile(change.Path, change.New, change.Mode)
+		}
+
+		if err != nil {
+			rp.Println("i18n4go: error writing file:", change.Path, err.Error())
+			restoreChanges(rp.changes[:index])
+			os.Remove(journalFileName)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreChanges restores the content the files had before the changes, the files they created are removed
+func restoreChanges(changes []common.FileChange) {
+	for _, change := range changes {
+		if change.Created {
+			os.Remove(change.Path)
+		} else {
+			ioutil.WriteFile(change.Path, change.Old, change.Mode)
+		}
+	}
+}
+
+// verifyChanges type checks each package with rewritten Go files as it is after the rewrite, with the files the
+// rewrite gives it in place of its sources. The type errors the rewrite introduces are printed with their positions
+// in the rewritten files and none of the changes are written
+func (rp *rewritePackage) verifyChanges() error {
+	overlays := make(map[string]map[string][]byte)
+	outputFileNames := make(map[string]string)
+	for _, change := range rp.changes {
+		outputFileName, err := filepath.Abs(change.Path)
+		if err != nil {
+			continue
+		}
+		sourceFileName, ok := rp.sourceFiles[outputFileName]
+		if !ok {
+			continue
+		}
+
+		dirName := filepath.Dir(sourceFileName)
+		if overlays[dirName] == nil {
+			overlays[dirName] = make(map[string][]byte)
+		}
+		overlays[dirName][filepath.Base(sourceFileName)] = change.New
+		outputFileNames[sourceFileName] = outputFileName
+	}
+
+	var dirNames []string
+	for dirName := range overlays {
+		dirNames = append(dirNames, dirName)
+	}
+	sort.Strings(dirNames)
+
+	var typeErrors []string
+	for _, dirName := range dirNames {
+		rp.Println("i18n4go: type checking the rewritten package in dir:", dirName)
+		before, err := rp.loader().LoadDir(dirName)
+		if err != nil {
+			rp.Println("i18n4go: WARNING could not type check the package in dir:", dirName, err.Error())
+			continue
+		}
+
+		after, err := rp.loader().LoadDirOverlay(dirName, overlays[dirName])
+		if err != nil {
+			typeErrors = append(typeErrors, err.Error())
+			continue
+		}
+
+		typeErrors = append(typeErrors, rp.newTypeErrors(before, after, outputFileNames)...)
+	}
+
+	if len(typeErrors) == 0 {
+		return nil
+	}
+
+	for _, typeError := range typeErrors {
+		fmt.Fprintln(rp.output(), typeError)
+	}
+	return fmt.Errorf("i18n4go: the rewritten packages do not compile, the rewrite of %d files was rolled back", len(rp.changes))
+}
+
+// newTypeErrors returns the type errors of the package after the rewrite that it did not have before. The messages
+// quote the rewritten expressions, a file only gets new type errors when it has more than before, they are those whose
+// messages are new, given the positions of the rewritten files. The imports that cannot be type checked are left out
+func (rp *rewritePackage) newTypeErrors(before, after *common.TypedPackage, outputFileNames map[string]string) []string {
+	knownErrors := make(map[string]map[string]int)
+	for _, typeError := range rp.verifiedTypeErrors(before) {
+		fileName := typeError.Fset.Position(typeError.Pos).Filename
+		if knownErrors[fileName] == nil {
+			knownErrors[fileName] = make(map[string]int)
+		}
+		knownErrors[fileName][typeError.Msg]++
+	}
+
+	fileErrors := make(map[string][]types.Error)
+	var fileNames []string
+	for _, typeError := range rp.verifiedTypeErrors(after) {
+		fileName := typeError.Fset.Position(typeError.Pos).Filename
+		if fileErrors[fileName] == nil {
+			fileNames = append(fileNames, fileName)
+		}
+		fileErrors[fileName] = append(fileErrors[fileName], typeError)
+	}
+
+	var newErrors []string
+	for _, fileName := range fileNames {
+		knownCount := 0
+		for _, count := range knownErrors[fileName] {
+			knownCount += count
+		}
+		if len(fileErrors[fileName]) <= knownCount {
+			continue
+		}
+
+		for _, typeError := range fileErrors[fileName] {
+			if knownErrors[fileName][typeError.Msg] > 0 {
+				knownErrors[fileName][typeError.Msg]--
+				continue
+			}
+
+			position := typeError.Fset.Position(typeError.Pos)
+			outputFileName := fileName
+			if name, ok := outputFileNames[fileName]; ok {
+				outputFileName = name
+			}
+			newErrors = append(newErrors, fmt.Sprintf("%s:%d:%d: %s", rp.changeFileName(outputFileName), position.Line, position.Column, typeError.Msg))
+		}
+	}
+
+	return newErrors
+}
+
+// verifiedTypeErrors returns the type errors of typedPackage but for the imports that cannot be type checked here,
+// e.g., of a package with generated files
+func (rp *rewritePackage) verifiedTypeErrors(typedPackage *common.TypedPackage) []types.Error {
+	var typeErrors []types.Error
+	for _, err := range typedPackage.TypeErrors {
+		typeError, ok := err.(types.Error)
+		if !ok {
+			continue
+		}
+
+		if strings.HasPrefix(typeError.Msg, "could not import") {
+			rp.Println("i18n4go: WARNING could not verify an import of the package:", typeError.Msg)
+			continue
+		}
+		typeErrors = append(typeErrors, typeError)
+	}
+
+	return typeErrors
+}
@@ -0,0 +1,93 @@
+package cmds
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/Liam-Williams/i18n4go/common"
+)
+
+// RewriteUndo restores the files of the last rewrite-package run from the undo journal saved in its output dir
+type RewriteUndo struct {
+	options common.Options
+
+	Directory string
+
+	TotalRestoredFiles int
+}
+
+func NewRewriteUndo(options common.Options) RewriteUndo {
+	directory := options.DirnameFlag
+	if directory == "" {
+		directory = options.OutputDirFlag
+	}
+	if directory == "" {
+		directory = "."
+	}
+
+	return RewriteUndo{
+		options:   options,
+		Directory: directory,
+	}
+}
+
+func (ru *RewriteUndo) Options() common.Options {
+	return ru.options
+}
+
+func (ru *RewriteUndo) Println(a ...interface{}) (int, error) {
+	if ru.options.VerboseFlag {
+		return fmt.Println(a...)
+	}
+
+	return 0, nil
+}
+
+func (ru *RewriteUndo) Printf(msg string, a ...interface{}) (int, error) {
+	if ru.options.VerboseFlag {
+		return fmt.Printf(msg, a...)
+	}
+
+	return 0, nil
+}
+
+// Run restores the content every file had before the rewrite and removes the files it created, nothing is restored when
+// a file was changed since the rewrite, its changes would be lost
+func (ru *RewriteUndo) Run() error {
+	journalFileName := filepath.Join(ru.Directory, common.REWRITE_JOURNAL_FILENAME)
+	journal, err := common.LoadRewriteJournal(journalFileName)
+	if err != nil {
+		return err
+	}
+
+	for _, journalFile := range journal.Files {
+		content, err := ioutil.ReadFile(journalFile.Path)
+		if err != nil {
+			return fmt.Errorf("i18n4go: could not read the rewritten file %s: %w", journalFile.Path, err)
+		}
+		if common.ContentDigest(content) != journalFile.NewDigest {
+			return fmt.Errorf("i18n4go: the file %s changed since the rewrite, no file was restored", journalFile.Path)
+		}
+	}
+
+	for _, journalFile := range journal.Files {
+		if journalFile.Created {
+			ru.Println("i18n4go: removing the file created by the rewrite:", journalFile.Path)
+			err = os.Remove(journalFile.Path)
+		} else {
+			ru.Println("i18n4go: restoring the file:", journalFile.Path)
+			err = ioutil.WriteFile(journalFile.Path, journalFile.Old, journalFile.Mode)
+		}
+		if err != nil {
+			return err
+		}
+
+		ru.TotalRestoredFiles++
+	}
+
+	ru.Println("Total restored files:", ru.TotalRestoredFiles)
+
+	return os.Remove(journalFileName)
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 
 	"go/ast"
@@ -122,6 +121,13 @@ func (sms *ShowMissingStrings) inspectFile(filename string) error {
 }
 
 func (sms *ShowMissingStrings) extractString(f *ast.File, fset *token.FileSet, filename string) error {
+	constFolder := common.NewConstFolder(nil)
+	directives := common.ParseDirectives(fset, f)
+	if directives.IgnoreFile {
+		sms.Println("i18n4go: ignoring file with an ignore-file directive:", filename)
+		return nil
+	}
+
 	ast.Inspect(f, func(n ast.Node) bool {
 		switch x := n.(type) {
 		case *ast.CallExpr:
@@ -130,16 +136,22 @@ func (sms *ShowMissingStrings) extractString(f *ast.File, fset *token.FileSet, f
 				funName := x.Fun.(*ast.Ident).Name
 
 				if funName == "T" || funName == "t" {
-					if stringArg, ok := x.Args[0].(*ast.BasicLit); ok {
-						translatedString, err := strconv.Unquote(stringArg.Value)
-						if err != nil {
-							panic(err.Error())
-						}
+					if translatedString, ok := constFolder.Fold(x.Args[0]); ok && !directives.Ignores(x.Args[0]) {
 
 						sms.Println("Adding to translated strings:", translatedString)
 						sms.TranslatedStrings = append(sms.TranslatedStrings, filename+": "+translatedString)
 					}
 				}
+
+				// the strings of TC(context, message) are kept as message keys
+				if funName == common.CONTEXT_FUNC && common.IsContextCall(x) && !directives.Ignores(x.Args[1]) {
+					context, contextOk := constFolder.Fold(x.Args[0])
+					translatedString, ok := constFolder.Fold(x.Args[1])
+					if contextOk && ok {
+						sms.Println("Adding to translated strings:", common.DescribeMessageKey(common.MessageKey(context, translatedString)))
+						sms.TranslatedStrings = append(sms.TranslatedStrings, filename+": "+common.MessageKey(context, translatedString))
+					}
+				}
 			default:
 				//Skip!
 			}
@@ -154,7 +166,7 @@ func (sms *ShowMissingStrings) showMissingTranslatedStrings() error {
 	missingStrings := false
 	for _, codeString := range sms.TranslatedStrings {
 		if !sms.stringInStringInfos(codeString, sms.I18nStringInfos) {
-			fmt.Println("Missing:", codeString)
+			fmt.Println("Missing:", common.DescribeMessageKey(codeString))
 			missingStrings = true
 		}
 	}
@@ -174,8 +186,8 @@ func splitFilePathAndString(str string) (string, string) {
 func (sms *ShowMissingStrings) stringInStringInfos(str string, list []common.I18nStringInfo) bool {
 	_, translatedStr := splitFilePathAndString(str)
 	for _, stringInfo := range list {
-		if translatedStr == stringInfo.ID {
-			sms.Println("Found", stringInfo.ID, "UNDER", str)
+		if translatedStr == stringInfo.Key() {
+			sms.Println("Found", common.DescribeMessageKey(stringInfo.Key()), "UNDER", common.DescribeMessageKey(str))
 			return true
 		}
 	}
@@ -187,8 +199,8 @@ func (sms *ShowMissingStrings) stringInStringInfos(str string, list []common.I18
 func (sms *ShowMissingStrings) showExtraStrings() error {
 	additionalStrings := false
 	for _, stringInfo := range sms.I18nStringInfos {
-		if !stringInTranslatedStrings(stringInfo.ID, sms.TranslatedStrings) {
-			fmt.Println("Additional:", stringInfo.ID)
+		if !stringInTranslatedStrings(stringInfo.Key(), sms.TranslatedStrings) {
+			fmt.Println("Additional:", common.DescribeMessageKey(stringInfo.Key()))
 			additionalStrings = true
 		}
 	}
@@ -60,6 +60,12 @@ func (vs *verifyStrings) Printf(msg string, a ...interface{}) (int, error) {
 }
 
 func (vs *verifyStrings) Run() error {
+	if vs.options.IdStrategyFlag != "" {
+		if err := common.CheckIDStrategy(vs.options.IdStrategyFlag); err != nil {
+			return err
+		}
+	}
+
 	fileName, filePath, err := common.CheckFile(vs.InputFilename)
 	if err != nil {
 		vs.Println("i18n4go: Error checking input filename: ", vs.InputFilename)
@@ -95,10 +101,15 @@ func (vs *verifyStrings) determineTargetFilenames(inputFilename string, inputFil
 
 type I18nStringInfo struct {
 	ID          string      `json:"id"`
+	Context     string      `json:"context,omitempty"`
 	Translation interface{} `json:"translation"`
 	Modified    bool        `json:"modified"`
 }
 
+func (info I18nStringInfo) Key() string {
+	return common.MessageKey(info.Context, info.ID)
+}
+
 func (info I18nStringInfo) Translations() (translations []string) {
 	switch v := info.Translation.(type) {
 	case string:
@@ -141,8 +152,10 @@ func CreateI18nStringInfoMap(i18nStringInfos []I18nStringInfo) (map[string]I18nS
 
 	for _, i18nStringInfo := range i18nStringInfos {
 
-		if _, ok := inputMap[i18nStringInfo.ID]; !ok {
-			inputMap[i18nStringInfo.ID] = i18nStringInfo
+		if _, ok := inputMap[i18nStringInfo.Key()]; !ok {
+			inputMap[i18nStringInfo.Key()] = i18nStringInfo
+		} else if i18nStringInfo.Context != "" {
+			return nil, errors.New("Duplicated key found: " + i18nStringInfo.ID + " in context " + i18nStringInfo.Context)
 		} else {
 			return nil, errors.New("Duplicated key found: " + i18nStringInfo.ID)
 		}
@@ -197,12 +210,15 @@ func (vs *verifyStrings) verify(inputFilename string, targetFilename string) err
 
 	var targetExtraStringInfos, targetInvalidStringInfos []I18nStringInfo
 	for _, stringInfo := range targetI18nStringInfos {
-		if _, ok := inputMap[stringInfo.ID]; ok {
-			if common.IsTemplatedString(stringInfo.ID) && vs.isTemplatedStringTranslationInvalid(stringInfo) {
+		if inputStringInfo, ok := inputMap[stringInfo.Key()]; ok {
+			if vs.isTemplatedStringTranslationInvalid(stringInfo, vs.sourceText(inputStringInfo)) {
 				vs.Println("i18n4go: WARNING target file has invalid templated translations with key ID: ", stringInfo.ID)
 				targetInvalidStringInfos = append(targetInvalidStringInfos, stringInfo)
+			} else if vs.isInterpolatedStringTranslationInvalid(stringInfo, vs.sourceText(inputStringInfo)) {
+				vs.Println("i18n4go: WARNING target file has invalid interpolated translations with key ID: ", stringInfo.ID)
+				targetInvalidStringInfos = append(targetInvalidStringInfos, stringInfo)
 			}
-			delete(inputMap, stringInfo.ID)
+			delete(inputMap, stringInfo.Key())
 		} else {
 			vs.Println("i18n4go: WARNING target file has extra key with ID: ", stringInfo.ID)
 			targetExtraStringInfos = append(targetExtraStringInfos, stringInfo)
@@ -249,8 +265,21 @@ func (vs *verifyStrings) verify(inputFilename string, targetFilename string) err
 	return verficationError
 }
 
-func (vs *verifyStrings) isTemplatedStringTranslationInvalid(stringInfo I18nStringInfo) bool {
-	if !common.IsTemplatedString(stringInfo.ID) {
+// sourceText is the text the translations of a string are checked against, its ID unless the IDs are not the source text
+func (vs *verifyStrings) sourceText(inputStringInfo I18nStringInfo) string {
+	if vs.options.IdStrategyFlag == "" || vs.options.IdStrategyFlag == common.SOURCE_ID_STRATEGY {
+		return inputStringInfo.ID
+	}
+
+	if translation, ok := inputStringInfo.Translation.(string); ok {
+		return translation
+	}
+
+	return inputStringInfo.ID
+}
+
+func (vs *verifyStrings) isTemplatedStringTranslationInvalid(stringInfo I18nStringInfo, sourceText string) bool {
+	if !common.IsTemplatedString(sourceText) {
 		return false
 	}
 	translations := stringInfo.Translations()
@@ -265,7 +294,7 @@ func (vs *verifyStrings) isTemplatedStringTranslationInvalid(stringInfo I18nStri
 			argsMap[translationArg] = translationArg
 		}
 
-		idArgs := common.GetTemplatedStringArgs(stringInfo.ID)
+		idArgs := common.GetTemplatedStringArgs(sourceText)
 		idArgMap := make(map[string]string, len(idArgs))
 		for _, idArg := range idArgs {
 			idArgMap[idArg] = idArg
@@ -299,10 +328,71 @@ func (vs *verifyStrings) isTemplatedStringTranslationInvalid(stringInfo I18nStri
 	return false
 }
 
+// isInterpolatedStringTranslationInvalid is true when a translation of a printf format string does not format the args
+// of the source text the way it does, the translation may format them in another order with explicit indexes, e.g., "%[2]s"
+func (vs *verifyStrings) isInterpolatedStringTranslationInvalid(stringInfo I18nStringInfo, sourceText string) bool {
+	if !common.IsInterpolatedString(sourceText) {
+		return false
+	}
+
+	sourceVerbs := indexedFormatVerbs(sourceText)
+	for _, translation := range stringInfo.Translations() {
+		translationVerbs := indexedFormatVerbs(translation)
+
+		var missingVerbs []string
+		for _, verb := range sourceVerbs {
+			if !containsString(translationVerbs, verb) {
+				missingVerbs = append(missingVerbs, verb)
+			}
+		}
+
+		if len(missingVerbs) > 0 {
+			vs.Println("i18n4go: interpolated string is invalid, missing verbs in translation:", strings.Join(missingVerbs, ","))
+			return true
+		}
+
+		var excessVerbs []string
+		for _, verb := range translationVerbs {
+			if !containsString(sourceVerbs, verb) {
+				excessVerbs = append(excessVerbs, verb)
+			}
+		}
+
+		if len(excessVerbs) > 0 {
+			vs.Println("i18n4go: interpolated string is invalid, excess verbs in translation:", strings.Join(excessVerbs, ","))
+			return true
+		}
+	}
+
+	return false
+}
+
+// indexedFormatVerbs are the verbs of format with explicit indexes, see FormatVerb.Indexed, once each
+func indexedFormatVerbs(format string) []string {
+	var verbs []string
+	for _, verb := range common.ParseFormat(format) {
+		if !verb.Literal() && !containsString(verbs, verb.Indexed()) {
+			verbs = append(verbs, verb.Indexed())
+		}
+	}
+
+	return verbs
+}
+
+func containsString(values []string, value string) bool {
+	for _, aValue := range values {
+		if aValue == value {
+			return true
+		}
+	}
+
+	return false
+}
+
 func keysForI18nStringInfos(in18nStringInfos []I18nStringInfo) []string {
 	var keys []string
 	for _, stringInfo := range in18nStringInfos {
-		keys = append(keys, stringInfo.ID)
+		keys = append(keys, common.DescribeMessageKey(stringInfo.Key()))
 	}
 	return keys
 }
@@ -310,7 +400,7 @@ func keysForI18nStringInfos(in18nStringInfos []I18nStringInfo) []string {
 func keysForI18nStringInfoMap(inputMap map[string]I18nStringInfo) []string {
 	var keys []string
 	for k, _ := range inputMap {
-		keys = append(keys, k)
+		keys = append(keys, common.DescribeMessageKey(k))
 	}
 	return keys
 }
@@ -0,0 +1,155 @@
+package common
+
+import (
+	"go/ast"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// the names given to the args of a printf format string converted into a templated string, e.g., "Arg0" or "Arg0_1"
+var positionalArgNameRegexp = regexp.MustCompile(`^Arg[0-9]+(?:_[0-9]+)?$`)
+
+// the args of a templated string, with the spacing around their names
+var templateArgRegexp = regexp.MustCompile(`\{\{(\s*)\.([[:alpha:]_][[:alnum:]_]*)(\s*)\}\}`)
+
+// IsPositionalArgName is true for the names a converted printf format string gives to its args, e.g., "Arg1"
+func IsPositionalArgName(name string) bool {
+	return positionalArgNameRegexp.MatchString(name)
+}
+
+// IsTemplateArgName is true for the names a templated string arg can have, e.g., "OrgName"
+func IsTemplateArgName(name string) bool {
+	return templateArgRegexp.MatchString("{{." + name + "}}")
+}
+
+// TemplateArgName returns the name of the templated string arg whose value is expr, made of the last two names of
+// expr, e.g., "OrgName" for org.Name, cmd.org.Name() or org.GetName(), or "" when expr is not named, e.g., a literal.
+// The value of a call to fmt.Sprintf, T() or TC() is named after their last arg, of Error() and String() after their receiver
+func TemplateArgName(expr ast.Expr) string {
+	var names []string
+	for expr != nil && len(names) < 2 {
+		switch x := expr.(type) {
+		case *ast.Ident:
+			if x.Name != "nil" && x.Name != "true" && x.Name != "false" {
+				names = append(names, x.Name)
+			}
+			expr = nil
+		case *ast.SelectorExpr:
+			names = append(names, x.Sel.Name)
+			expr = x.X
+		case *ast.CallExpr:
+			if formatsLastArg(x) || isConversion(x) {
+				expr = x.Args[len(x.Args)-1]
+			} else if selectorExpr, ok := x.Fun.(*ast.SelectorExpr); ok && isStringer(x) {
+				expr = selectorExpr.X
+			} else if ok && strings.HasPrefix(selectorExpr.Sel.Name, "Get") && len(selectorExpr.Sel.Name) > len("Get") {
+				names = append(names, strings.TrimPrefix(selectorExpr.Sel.Name, "Get"))
+				expr = selectorExpr.X
+			} else {
+				expr = x.Fun
+			}
+		case *ast.StarExpr:
+			expr = x.X
+		case *ast.UnaryExpr:
+			expr = x.X
+		case *ast.ParenExpr:
+			expr = x.X
+		case *ast.IndexExpr:
+			expr = x.X
+		default:
+			expr = nil
+		}
+	}
+
+	name := ""
+	for i := len(names) - 1; i >= 0; i-- {
+		name += exportedName(names[i])
+	}
+
+	return name
+}
+
+// formatsLastArg is true for the calls of fmt.Sprintf, T() and TC() whose value is their last arg formatted or translated
+func formatsLastArg(callExpr *ast.CallExpr) bool {
+	if len(callExpr.Args) == 0 {
+		return false
+	}
+
+	name := ""
+	switch fun := callExpr.Fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.SelectorExpr:
+		name = fun.Sel.Name
+	}
+
+	return name == "Sprintf" || name == "T" || name == "TC"
+}
+
+// isStringer is true for the calls of Error() and String(), their value is named after their receiver, e.g., "Err" for err.Error()
+func isStringer(callExpr *ast.CallExpr) bool {
+	selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	return ok && len(callExpr.Args) == 0 && (selectorExpr.Sel.Name == "Error" || selectorExpr.Sel.Name == "String")
+}
+
+// isConversion is true for the calls converting one arg to a basic type, e.g., string(name)
+func isConversion(callExpr *ast.CallExpr) bool {
+	ident, ok := callExpr.Fun.(*ast.Ident)
+	if !ok || len(callExpr.Args) != 1 {
+		return false
+	}
+
+	switch ident.Name {
+	case "string", "int", "int32", "int64", "uint", "uint32", "uint64", "float32", "float64":
+		return true
+	}
+
+	return false
+}
+
+// exportedName is name with its first letter upper case and without the characters a template arg name cannot have
+func exportedName(name string) string {
+	var exported []rune
+	for _, r := range name {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			exported = append(exported, r)
+		}
+	}
+	if len(exported) == 0 || !unicode.IsLetter(exported[0]) {
+		return ""
+	}
+	exported[0] = unicode.ToUpper(exported[0])
+
+	return string(exported)
+}
+
+// UniqueTemplateArgName returns name, numbered when it is taken, e.g., "Name2", and takes it
+func UniqueTemplateArgName(name string, taken map[string]bool) string {
+	uniqueName := name
+	for i := 2; taken[uniqueName]; i++ {
+		uniqueName = name + strconv.Itoa(i)
+	}
+	taken[uniqueName] = true
+
+	return uniqueName
+}
+
+// RenameTemplateArgs renames the args of the templated string aString found in renames, e.g., "{{.Arg0}}" to "{{.Name}}"
+// for renames["Arg0"] == "Name", the other args are kept
+func RenameTemplateArgs(aString string, renames map[string]string) string {
+	if len(renames) == 0 || !strings.Contains(aString, "{{") {
+		return aString
+	}
+
+	return templateArgRegexp.ReplaceAllStringFunc(aString, func(arg string) string {
+		match := templateArgRegexp.FindStringSubmatch(arg)
+		newName, ok := renames[match[2]]
+		if !ok {
+			return arg
+		}
+
+		return "{{" + match[1] + "." + newName + match[3] + "}}"
+	})
+}
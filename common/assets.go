@@ -0,0 +1,706 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// the modes of the text and Markdown assets
+const (
+	// the whole file is one string
+	FILE_ASSET_MODE = "file"
+	// every paragraph, i.e., every run of lines between blank lines, is a string, Markdown headings are strings of their own
+	// and Markdown code blocks are left out
+	PARAGRAPH_ASSET_MODE = "paragraph"
+)
+
+// the extensions of the files extracted as assets with -f without an assets rule, JSON and YAML files are selected with paths,
+// the others are text
+var ASSET_EXTENSIONS = []string{".json", ".yml", ".yaml", ".md", ".markdown", ".txt"}
+
+// AssetRule is an entry of the assets rule, Files is a file name pattern, e.g., "help/*.md", the JSON and YAML files
+// it matches are selected with Paths and the text and Markdown files with Mode
+type AssetRule struct {
+	Files string   `json:"files" yaml:"files"`
+	Paths []string `json:"paths,omitempty" yaml:"paths"`
+	Mode  string   `json:"mode,omitempty" yaml:"mode"`
+}
+
+// AssetSelector selects the strings of the asset files of a rule, every string of a JSON or YAML file is selected when there
+// is no path and the paragraphs of a text file when there is no mode
+type AssetSelector struct {
+	Rule  AssetRule
+	Paths []AssetPath
+}
+
+// AssetString is a string selected in an asset file, Path is its JSONPath in a JSON or YAML file
+type AssetString struct {
+	Value  string
+	Path   string
+	Offset int
+	Line   int
+	Column int
+}
+
+// AssetPath is a JSONPath such as $.commands[*].description, $..title or $['key'][0], the $ can be left out as in YAML paths,
+// e.g., commands[*].description
+type AssetPath struct {
+	Pattern string
+
+	steps []assetPathStep
+}
+
+type assetPathStep struct {
+	key       string
+	index     int
+	wildcard  bool
+	recursive bool
+}
+
+// assetNode is a value of a JSON or YAML file with its offset in the file
+type assetNode struct {
+	// the string, number, bool or nil of a scalar
+	value  interface{}
+	keys   []string
+	fields map[string]*assetNode
+	items  []*assetNode
+	isList bool
+	offset int
+
+	// the position of a YAML value, both start at 0
+	line, column int
+}
+
+// IsAssetFile is true for the files extracted as assets with -f even without an assets rule
+func IsAssetFile(fileName string) bool {
+	extension := strings.ToLower(filepath.Ext(fileName))
+	for _, assetExtension := range ASSET_EXTENSIONS {
+		if extension == assetExtension {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsStructuredAsset is true for the JSON and YAML files, their strings are selected with paths
+func IsStructuredAsset(fileName string) bool {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json", ".yml", ".yaml":
+		return true
+	}
+
+	return false
+}
+
+func isMarkdownAsset(fileName string) bool {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".md", ".markdown":
+		return true
+	}
+
+	return false
+}
+
+// MatchesFilesPattern is true when the last elements of fileName match pattern, e.g., "help/*.md" matches /app/help/index.md
+func MatchesFilesPattern(pattern, fileName string) bool {
+	elements := strings.Split(filepath.ToSlash(fileName), "/")
+	count := strings.Count(pattern, "/") + 1
+	if count > len(elements) {
+		return false
+	}
+
+	ok, _ := path.Match(pattern, strings.Join(elements[len(elements)-count:], "/"))
+	return ok
+}
+
+// NewAssetSelector parses the paths of a validated assets rule
+func NewAssetSelector(rule AssetRule) AssetSelector {
+	selector := AssetSelector{Rule: rule}
+	for _, pattern := range rule.Paths {
+		assetPath, _ := ParseAssetPath(pattern)
+		selector.Paths = append(selector.Paths, assetPath)
+	}
+
+	return selector
+}
+
+// ParseAssetPath parses a JSONPath made of .key, ['key'], [index], [*], .* and ..key steps
+func ParseAssetPath(pattern string) (AssetPath, error) {
+	assetPath := AssetPath{Pattern: pattern}
+
+	s := strings.TrimSpace(pattern)
+	if strings.HasPrefix(s, "$") {
+		s = s[1:]
+	} else if s != "" && s[0] != '.' && s[0] != '[' {
+		s = "." + s
+	}
+
+	for s != "" {
+		step := assetPathStep{index: -1}
+		switch {
+		case strings.HasPrefix(s, ".."):
+			step.recursive = true
+			s = s[2:]
+		case s[0] == '.':
+			s = s[1:]
+		case s[0] != '[':
+			return AssetPath{}, fmt.Errorf("expected . or [ at %q", s)
+		}
+
+		if strings.HasPrefix(s, "[") {
+			end := strings.Index(s, "]")
+			if len(s) > 1 && (s[1] == '\'' || s[1] == '"') {
+				closing := strings.IndexByte(s[2:], s[1])
+				if closing == -1 {
+					return AssetPath{}, fmt.Errorf("unterminated key at %q", s)
+				}
+				end = 2 + closing + 1
+				if end >= len(s) || s[end] != ']' {
+					return AssetPath{}, fmt.Errorf("expected ] at %q", s)
+				}
+				step.key = s[2 : end-1]
+			} else if end == -1 {
+				return AssetPath{}, fmt.Errorf("expected ] at %q", s)
+			} else if inside := strings.TrimSpace(s[1:end]); inside == "*" {
+				step.wildcard = true
+			} else if index, err := strconv.Atoi(inside); err == nil && index >= 0 {
+				step.index = index
+			} else {
+				return AssetPath{}, fmt.Errorf("expected an index, * or a quoted key in %q", s[:end+1])
+			}
+			s = s[end+1:]
+		} else {
+			end := strings.IndexAny(s, ".[")
+			if end == -1 {
+				end = len(s)
+			}
+			name := s[:end]
+			if name == "" {
+				return AssetPath{}, fmt.Errorf("expected a key at %q", s)
+			}
+			if name == "*" {
+				step.wildcard = true
+			} else {
+				step.key = name
+			}
+			s = s[end:]
+		}
+
+		assetPath.steps = append(assetPath.steps, step)
+	}
+
+	return assetPath, nil
+}
+
+// AssetStrings returns the strings of an asset file selected by selector, in the order they appear in the file
+func AssetStrings(fileName string, content []byte, selector AssetSelector) ([]AssetString, error) {
+	var assetStrings []AssetString
+	if IsStructuredAsset(fileName) {
+		var root *assetNode
+		var err error
+		if strings.ToLower(filepath.Ext(fileName)) == ".json" {
+			root, err = parseJSONAsset(content)
+		} else {
+			root, err = parseYAMLAsset(content)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		paths := selector.Paths
+		if len(paths) == 0 {
+			paths = []AssetPath{{Pattern: "$"}}
+		}
+		assetStrings = selectAssetStrings(root, paths)
+	} else {
+		mode := selector.Rule.Mode
+		if mode == "" {
+			mode = PARAGRAPH_ASSET_MODE
+		}
+		assetStrings = textAssetStrings(string(content), mode, isMarkdownAsset(fileName))
+	}
+
+	for i := range assetStrings {
+		assetStrings[i].Line, assetStrings[i].Column = TemplateLineColumn(string(content), assetStrings[i].Offset)
+	}
+
+	return assetStrings, nil
+}
+
+// selectAssetStrings returns the strings below the nodes selected by paths, a string selected by more than one path once
+func selectAssetStrings(root *assetNode, paths []AssetPath) []AssetString {
+	selected := make(map[*assetNode]bool)
+	var assetStrings []AssetString
+	for _, assetPath := range paths {
+		for _, match := range assetPath.match(root, "$") {
+			match.node.walkStrings(match.path, func(node *assetNode, nodePath string) {
+				if !selected[node] {
+					selected[node] = true
+					assetStrings = append(assetStrings, AssetString{Value: node.value.(string), Path: nodePath, Offset: node.offset})
+				}
+			})
+		}
+	}
+
+	sort.SliceStable(assetStrings, func(i, j int) bool {
+		return assetStrings[i].Offset < assetStrings[j].Offset
+	})
+
+	return assetStrings
+}
+
+type assetMatch struct {
+	node *assetNode
+	path string
+}
+
+func (p AssetPath) match(root *assetNode, rootPath string) []assetMatch {
+	matches := []assetMatch{{root, rootPath}}
+	for _, step := range p.steps {
+		var next []assetMatch
+		for _, match := range matches {
+			candidates := []assetMatch{match}
+			if step.recursive {
+				candidates = match.node.descendants(match.path)
+			}
+
+			for _, candidate := range candidates {
+				next = append(next, candidate.node.children(candidate.path, step)...)
+			}
+		}
+		matches = next
+	}
+
+	return matches
+}
+
+// children returns the children of n selected by one step of a path
+func (n *assetNode) children(nodePath string, step assetPathStep) []assetMatch {
+	var matches []assetMatch
+	switch {
+	case n.fields != nil:
+		for _, key := range n.keys {
+			if step.wildcard || (step.index == -1 && key == step.key) {
+				matches = append(matches, assetMatch{n.fields[key], nodePath + keyPath(key)})
+			}
+		}
+	case n.isList:
+		for i, item := range n.items {
+			if step.wildcard || step.index == i {
+				matches = append(matches, assetMatch{item, fmt.Sprintf("%s[%d]", nodePath, i)})
+			}
+		}
+	}
+
+	return matches
+}
+
+// descendants returns n and every node below it
+func (n *assetNode) descendants(nodePath string) []assetMatch {
+	matches := []assetMatch{{n, nodePath}}
+	for _, child := range n.children(nodePath, assetPathStep{wildcard: true}) {
+		matches = append(matches, child.node.descendants(child.path)...)
+	}
+
+	return matches
+}
+
+// walkStrings calls f with n when it is a string or with the strings below it
+func (n *assetNode) walkStrings(nodePath string, f func(node *assetNode, nodePath string)) {
+	if _, ok := n.value.(string); ok {
+		f(n, nodePath)
+		return
+	}
+
+	for _, child := range n.children(nodePath, assetPathStep{wildcard: true}) {
+		child.node.walkStrings(child.path, f)
+	}
+}
+
+func keyPath(key string) string {
+	for _, r := range key {
+		if !(r == '_' || r == '-' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+			return "['" + key + "']"
+		}
+	}
+
+	return "." + key
+}
+
+// parseJSONAsset decodes a JSON file keeping the offsets of its values
+func parseJSONAsset(content []byte) (*assetNode, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	return decodeJSONNode(decoder, content)
+}
+
+func decodeJSONNode(decoder *json.Decoder, content []byte) (*assetNode, error) {
+	node := &assetNode{offset: jsonTokenOffset(content, int(decoder.InputOffset()))}
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		node.value = token
+		return node, nil
+	}
+
+	switch delim {
+	case '{':
+		node.fields = make(map[string]*assetNode)
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := keyToken.(string)
+
+			child, err := decodeJSONNode(decoder, content)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := node.fields[key]; !ok {
+				node.keys = append(node.keys, key)
+			}
+			node.fields[key] = child
+		}
+	case '[':
+		node.isList = true
+		for decoder.More() {
+			child, err := decodeJSONNode(decoder, content)
+			if err != nil {
+				return nil, err
+			}
+			node.items = append(node.items, child)
+		}
+	}
+
+	// the closing delimiter
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// jsonTokenOffset skips the separators between the end of the previous token and the next one
+func jsonTokenOffset(content []byte, offset int) int {
+	for offset < len(content) && strings.IndexByte(" \t\r\n,:", content[offset]) != -1 {
+		offset++
+	}
+
+	return offset
+}
+
+// parseYAMLAsset decodes a YAML file, the decoder does not keep the positions of the values so they are
+// found in the lines of the file, a value in flow style gets the position of its collection
+func parseYAMLAsset(content []byte) (*assetNode, error) {
+	var value interface{}
+	var mapSlice yaml.MapSlice
+	if err := yaml.Unmarshal(content, &mapSlice); err == nil {
+		value = mapSlice
+	} else if err := yaml.Unmarshal(content, &value); err != nil {
+		return nil, err
+	}
+
+	root := newYAMLNode(value)
+	lines := strings.Split(string(content), "\n")
+	locateYAMLNode(root, lines, 0, -1)
+
+	lineOffsets := make([]int, len(lines)+1)
+	for i, line := range lines {
+		lineOffsets[i+1] = lineOffsets[i] + len(line) + 1
+	}
+	root.setYAMLOffsets(lineOffsets)
+
+	return root, nil
+}
+
+func newYAMLNode(value interface{}) *assetNode {
+	node := &assetNode{}
+	switch x := value.(type) {
+	case yaml.MapSlice:
+		node.fields = make(map[string]*assetNode)
+		for _, item := range x {
+			key := fmt.Sprint(item.Key)
+			if _, ok := node.fields[key]; !ok {
+				node.keys = append(node.keys, key)
+			}
+			node.fields[key] = newYAMLNode(item.Value)
+		}
+	case map[interface{}]interface{}:
+		node.fields = make(map[string]*assetNode)
+		for key, item := range x {
+			node.keys = append(node.keys, fmt.Sprint(key))
+			node.fields[fmt.Sprint(key)] = newYAMLNode(item)
+		}
+		sort.Strings(node.keys)
+	case []interface{}:
+		node.isList = true
+		for _, item := range x {
+			node.items = append(node.items, newYAMLNode(item))
+		}
+	default:
+		node.value = x
+	}
+
+	return node
+}
+
+func (n *assetNode) setYAMLOffsets(lineOffsets []int) {
+	line := n.line
+	if line >= len(lineOffsets)-1 {
+		line = len(lineOffsets) - 2
+	}
+	n.offset = lineOffsets[line] + n.column
+	if n.offset >= lineOffsets[line+1] {
+		n.offset = lineOffsets[line]
+	}
+
+	for _, child := range n.children("", assetPathStep{wildcard: true}) {
+		child.node.setYAMLOffsets(lineOffsets)
+	}
+}
+
+// setYAMLPosition gives n and every node below it one position, for the values in flow style
+func (n *assetNode) setYAMLPosition(line, column int) {
+	n.line, n.column = line, column
+	for _, child := range n.children("", assetPathStep{wildcard: true}) {
+		child.node.setYAMLPosition(line, column)
+	}
+}
+
+// yamlLine splits a line into its indentation, the indentation of its content after the "- " of sequence items and its content
+func yamlLine(line string) (int, int, string) {
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	contentIndent := indent
+	content := line[indent:]
+	for strings.HasPrefix(content, "- ") || content == "-" {
+		trimmed := strings.TrimLeft(content[1:], " ")
+		contentIndent += len(content) - len(trimmed)
+		content = trimmed
+	}
+
+	return indent, contentIndent, strings.TrimRight(content, " \r")
+}
+
+func isYAMLBlank(content string) bool {
+	return content == "" || strings.HasPrefix(content, "#") || content == "---"
+}
+
+// locateYAMLNode finds the positions of the children of a collection that starts at line, whose entries are indented more than
+// parentIndent, the first line can be the "- " line of a sequence item
+func locateYAMLNode(n *assetNode, lines []string, start int, parentIndent int) {
+	if n.fields != nil {
+		locateYAMLMapping(n, lines, start, parentIndent)
+	} else if n.isList {
+		locateYAMLSequence(n, lines, start, parentIndent)
+	}
+}
+
+func locateYAMLMapping(n *assetNode, lines []string, start int, parentIndent int) {
+	keyIndent, end := -1, len(lines)
+	for i := start; i < len(lines); i++ {
+		indent, contentIndent, content := yamlLine(lines[i])
+		if isYAMLBlank(content) {
+			continue
+		}
+		if i > start && indent <= parentIndent {
+			end = i
+			break
+		}
+		if keyIndent == -1 {
+			keyIndent = contentIndent
+		}
+	}
+
+	for _, key := range n.keys {
+		for i := start; i < end; i++ {
+			_, contentIndent, content := yamlLine(lines[i])
+			if contentIndent != keyIndent {
+				continue
+			}
+
+			rest, ok := yamlKeyValue(content, key)
+			if !ok {
+				continue
+			}
+
+			child := n.fields[key]
+			valueColumn := contentIndent + len(content) - len(rest)
+			switch {
+			case rest == "" || strings.HasPrefix(rest, "#") || strings.HasPrefix(rest, "|") || strings.HasPrefix(rest, ">"):
+				child.line, child.column = nextYAMLLine(lines, i+1, contentIndent)
+				childIndent := contentIndent
+				if child.isList {
+					// the items of a sequence can be as indented as its key
+					childIndent--
+				}
+				locateYAMLNode(child, lines, i+1, childIndent)
+			default:
+				child.setYAMLPosition(i, valueColumn)
+			}
+			break
+		}
+	}
+}
+
+func locateYAMLSequence(n *assetNode, lines []string, start int, parentIndent int) {
+	item, itemIndent := 0, -1
+	for i := start; i < len(lines) && item < len(n.items); i++ {
+		indent, _, content := yamlLine(lines[i])
+		if isYAMLBlank(content) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "-") {
+			continue
+		}
+		if i > start && indent <= parentIndent {
+			break
+		}
+
+		dash := indent
+		if i == start {
+			// the first item can follow the "- " of the item of an outer sequence
+			dash = strings.Index(lines[i], "-")
+		}
+		if dash < 0 || !strings.HasPrefix(lines[i][dash:], "-") {
+			if itemIndent != -1 && indent <= itemIndent {
+				break
+			}
+			continue
+		}
+		if itemIndent == -1 {
+			itemIndent = dash
+		}
+		if dash != itemIndent {
+			continue
+		}
+
+		child := n.items[item]
+		item++
+
+		rest := strings.TrimLeft(strings.TrimRight(lines[i][dash+1:], " \r"), " ")
+		valueColumn := len(lines[i]) - len(strings.TrimLeft(lines[i][dash+1:], " "))
+		switch {
+		case rest == "" || strings.HasPrefix(rest, "#") || strings.HasPrefix(rest, "|") || strings.HasPrefix(rest, ">"):
+			child.line, child.column = nextYAMLLine(lines, i+1, dash)
+			locateYAMLNode(child, lines, i+1, dash)
+		case child.fields != nil && !strings.HasPrefix(rest, "{"):
+			child.line, child.column = i, valueColumn
+			locateYAMLMapping(child, lines, i, dash)
+		case child.isList && !strings.HasPrefix(rest, "["):
+			child.line, child.column = i, valueColumn
+			locateYAMLSequence(child, lines, i, dash)
+		default:
+			child.setYAMLPosition(i, valueColumn)
+		}
+	}
+}
+
+// yamlKeyValue returns what follows "key:" in content
+func yamlKeyValue(content string, key string) (string, bool) {
+	for _, quotedKey := range []string{key, `"` + key + `"`, "'" + key + "'"} {
+		if !strings.HasPrefix(content, quotedKey) {
+			continue
+		}
+
+		rest := strings.TrimLeft(content[len(quotedKey):], " ")
+		if rest == ":" || strings.HasPrefix(rest, ": ") {
+			return strings.TrimLeft(rest[1:], " "), true
+		}
+	}
+
+	return "", false
+}
+
+// nextYAMLLine returns the position of the first content line from start indented more than indent, or of start
+func nextYAMLLine(lines []string, start int, indent int) (int, int) {
+	for i := start; i < len(lines); i++ {
+		lineIndent, _, content := yamlLine(lines[i])
+		if isYAMLBlank(content) {
+			continue
+		}
+		if lineIndent >= indent {
+			return i, lineIndent
+		}
+		break
+	}
+
+	return start, 0
+}
+
+// textAssetStrings returns the whole text in file mode, or its paragraphs
+func textAssetStrings(text string, mode string, markdown bool) []AssetString {
+	if mode == FILE_ASSET_MODE {
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" {
+			return nil
+		}
+
+		return []AssetString{{Value: trimmed, Offset: strings.Index(text, trimmed)}}
+	}
+
+	var assetStrings []AssetString
+	var paragraph []string
+	paragraphOffset := 0
+	flush := func() {
+		value := strings.TrimSpace(strings.Join(paragraph, "\n"))
+		if value != "" {
+			assetStrings = append(assetStrings, AssetString{Value: value, Offset: paragraphOffset + strings.Index(paragraph[0], strings.TrimSpace(paragraph[0]))})
+		}
+		paragraph = nil
+	}
+
+	offset := 0
+	fence := ""
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lineOffset := offset
+		offset += len(line)
+		line = strings.TrimRight(line, " \t\r\n")
+		trimmed := strings.TrimSpace(line)
+
+		if markdown {
+			if fence != "" {
+				if strings.HasPrefix(trimmed, fence) {
+					fence = ""
+				}
+				continue
+			}
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				flush()
+				fence = trimmed[:3]
+				continue
+			}
+			if strings.HasPrefix(trimmed, "#") {
+				flush()
+				paragraph, paragraphOffset = []string{line}, lineOffset
+				flush()
+				continue
+			}
+		}
+
+		if trimmed == "" {
+			flush()
+			continue
+		}
+
+		if len(paragraph) == 0 {
+			paragraphOffset = lineOffset
+		}
+		paragraph = append(paragraph, line)
+	}
+	flush()
+
+	return assetStrings
+}
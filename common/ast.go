@@ -21,3 +21,29 @@ func ImportsForASTFile(astFile *ast.File) (*ast.GenDecl, error) {
 
 	return nil, errors.New(fmt.Sprintf("Could not find imports for root node:\n\t%#v\n", astFile))
 }
+
+// FuncDeclName returns the name of a function, or Type.Method for a method
+func FuncDeclName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return funcDecl.Name.Name
+	}
+
+	recvType := funcDecl.Recv.List[0].Type
+	for {
+		switch x := recvType.(type) {
+		case *ast.StarExpr:
+			recvType = x.X
+			continue
+		case *ast.IndexExpr:
+			recvType = x.X
+			continue
+		case *ast.IndexListExpr:
+			recvType = x.X
+			continue
+		case *ast.Ident:
+			return x.Name + "." + funcDecl.Name.Name
+		}
+
+		return funcDecl.Name.Name
+	}
+}
@@ -0,0 +1,180 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"sync"
+)
+
+// the version of i18n4go, cached results of other versions are never used
+const VERSION = "v0.2.7"
+
+// the cache directory below the user cache directory, e.g., ~/.cache/i18n4go on Linux
+const CACHE_DIRNAME = "i18n4go"
+
+// the cache directory used when the user cache directory is unknown, relative to the working directory
+const DEFAULT_CACHE_DIRNAME = ".i18n4go/cache"
+
+var (
+	buildDigest     string
+	buildDigestOnce sync.Once
+)
+
+// Cache stores the results of a command on disk, one file per key, it is safe for concurrent use
+type Cache struct {
+	Dirname string
+
+	digest string
+
+	mutex  sync.Mutex
+	hits   int
+	misses int
+}
+
+// NewCache creates a cache whose keys also depend on the version, the build info and the build of i18n4go and on settings,
+// e.g., the content of the excluded JSON file, so changing any of them invalidates every entry
+func NewCache(dirname string, settings ...[]byte) *Cache {
+	return &Cache{
+		Dirname: dirname,
+		digest:  Digest(append([][]byte{[]byte(VERSION), []byte(BuildInfo()), []byte(BuildDigest())}, settings...)...),
+	}
+}
+
+// DefaultCacheDirname is the directory of a cache named name below the user cache directory,
+// or below DEFAULT_CACHE_DIRNAME when there is none
+func DefaultCacheDirname(name string) string {
+	userCacheDirname, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(DEFAULT_CACHE_DIRNAME, name)
+	}
+
+	return filepath.Join(userCacheDirname, CACHE_DIRNAME, name)
+}
+
+// BuildInfo returns the build info of i18n4go, e.g., its module version, its VCS revision and its build settings, it is
+// empty when the executable has none
+func BuildInfo() string {
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	return buildInfo.String()
+}
+
+// BuildDigest returns the digest of the executable of i18n4go, a new build changes the results of the commands even when
+// VERSION is unchanged, it is empty when the executable cannot be read
+func BuildDigest() string {
+	buildDigestOnce.Do(func() {
+		executable, err := os.Executable()
+		if err != nil {
+			return
+		}
+
+		file, err := os.Open(executable)
+		if err != nil {
+			return
+		}
+		defer file.Close()
+
+		hash := sha256.New()
+		if _, err = io.Copy(hash, file); err == nil {
+			buildDigest = hex.EncodeToString(hash.Sum(nil))
+		}
+	})
+
+	return buildDigest
+}
+
+// Digest returns the hex encoded sha256 of the parts, each part is length prefixed so the parts cannot run into each other
+func Digest(parts ...[]byte) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(strconv.Itoa(len(part)) + ":"))
+		hash.Write(part)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Key returns the key of the entry for the parts, e.g., a file name and its content
+func (c *Cache) Key(parts ...[]byte) string {
+	return Digest(append([][]byte{[]byte(c.digest)}, parts...)...)
+}
+
+// Has is true when there is an entry for key, it does not count as a hit
+func (c *Cache) Has(key string) bool {
+	_, err := os.Stat(c.filename(key))
+	return err == nil
+}
+
+// Load decodes the entry for key into value and returns whether there was a valid one
+func (c *Cache) Load(key string, value interface{}) bool {
+	content, err := ioutil.ReadFile(c.filename(key))
+	if err == nil {
+		err = json.Unmarshal(content, value)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err != nil {
+		c.misses++
+		return false
+	}
+
+	c.hits++
+	return true
+}
+
+// Save stores value as the entry for key, the entry is renamed into place so concurrent readers never see half of it
+func (c *Cache) Save(key string, value interface{}) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(c.Dirname, 0755)
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(c.Dirname, "tmp-")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmpFile.Write(content)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), c.filename(key))
+}
+
+// Stats returns the number of hits and misses of Load
+func (c *Cache) Stats() (int, int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.hits, c.misses
+}
+
+// Clear removes every entry
+func (c *Cache) Clear() error {
+	return os.RemoveAll(c.Dirname)
+}
+
+func (c *Cache) filename(key string) string {
+	return filepath.Join(c.Dirname, key+".json")
+}
@@ -0,0 +1,268 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// the decisions of the classification of a string
+const (
+	EXTRACT_DECISION = "extract"
+	SKIP_DECISION    = "skip"
+)
+
+// the formats of the --explain report
+const (
+	TEXT_EXPLAIN_FORMAT = "text"
+	JSON_EXPLAIN_FORMAT = "json"
+)
+
+// a detector enabled by the detectors rule skips the strings it scores at least this high
+const DETECTOR_MIN_SCORE = 0.75
+
+// Detector recognizes a kind of string that is not user facing, Score is how sure it is that s is one, from 0 to 1
+type Detector struct {
+	Name  string
+	Score func(s string) float64
+}
+
+// Detection is the score of a detector that recognized a string
+type Detection struct {
+	Detector string  `json:"detector"`
+	Score    float64 `json:"score"`
+}
+
+// Classification is the decision taken for a string literal, Reason is the rule, directive or detector behind it
+type Classification struct {
+	Filename   string      `json:"filename"`
+	Line       int         `json:"line"`
+	Column     int         `json:"column"`
+	Value      string      `json:"value"`
+	Context    string      `json:"context,omitempty"`
+	Decision   string      `json:"decision"`
+	Reason     string      `json:"reason"`
+	Detections []Detection `json:"detections,omitempty"`
+}
+
+// the built-in detectors, in the order their detections are reported when they score the same
+var DETECTORS = []Detector{
+	{"identifier", scoreIdentifier},
+	{"url", scoreURL},
+	{"path", scorePath},
+	{"mime", scoreMime},
+	{"format", scoreFormat},
+	{"sql", scoreSQL},
+	{"json", scoreJSON},
+	{"regexp", scoreRegexp},
+	{"tag", scoreStructTag},
+}
+
+var (
+	camelCaseRegexp    = regexp.MustCompile(`^[a-z]+[a-z0-9]*([A-Z][a-z0-9]*)+$|^([A-Z][a-z0-9]+){2,}$`)
+	snakeCaseRegexp    = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(_[A-Za-z0-9]+)+$`)
+	dottedNameRegexp   = regexp.MustCompile(`^[A-Za-z_]\w*(\.[A-Za-z_]\w*)+$`)
+	lowerWordRegexp    = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+	urlRegexp          = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://\S+$`)
+	wwwRegexp          = regexp.MustCompile(`^(www\.|mailto:)\S+$`)
+	absPathRegexp      = regexp.MustCompile(`^(/|\./|\.\./|~/|[A-Za-z]:\\)[^\s]*$`)
+	relPathRegexp      = regexp.MustCompile(`^[\w.-]+([/\\][\w.-]+)+$`)
+	fileNameRegexp     = regexp.MustCompile(`^[\w-]+\.[a-z][a-z0-9]{0,4}$`)
+	mimeRegexp         = regexp.MustCompile(`^(application|audio|font|image|message|model|multipart|text|video)/[\w.+-]+(\s*;.*)?$`)
+	formatVerbRegexp   = regexp.MustCompile(`%[-+# 0]*(\d+|\*)?(\.(\d+|\*))?[vTtbcdoOqxXUeEfFgGsp%]`)
+	sqlRegexp          = regexp.MustCompile(`(?i)^\s*(SELECT|INSERT|UPDATE|DELETE|CREATE|DROP|ALTER|WITH)\s.*\s(FROM|INTO|SET|TABLE|WHERE|VALUES|AS)\s`)
+	upperSQLRegexp     = regexp.MustCompile(`^\s*(SELECT|INSERT|UPDATE|DELETE|CREATE|DROP|ALTER|WITH)\s.*\s(FROM|INTO|SET|TABLE|WHERE|VALUES|AS)\s`)
+	kebabCaseRegexp    = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)+$`)
+	regexpSyntaxRegexp = regexp.MustCompile(`^\^|\$$|\\[dDwWsSbB.]|\[[^\]]+\][*+?]?|\(\?[:!=P<i]|\.[*+]|[^\\][*+]\?|\{\d+(,\d*)?\}`)
+	structTagRegexp    = regexp.MustCompile(`^(\w+:"[^"]*"\s*)+$`)
+)
+
+// Detect returns the detections of the built-in detectors that recognize s, the highest score first
+func Detect(s string) []Detection {
+	var detections []Detection
+	for _, detector := range DETECTORS {
+		if score := detector.Score(s); score > 0 {
+			detections = append(detections, Detection{Detector: detector.Name, Score: score})
+		}
+	}
+
+	sort.SliceStable(detections, func(i, j int) bool {
+		return detections[i].Score > detections[j].Score
+	})
+
+	return detections
+}
+
+// IsDetector is true when name is the name of a built-in detector
+func IsDetector(name string) bool {
+	for _, detector := range DETECTORS {
+		if detector.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// identifiers, e.g., userName, user_name or config.userName, a single lowercase word may be a word of a message
+func scoreIdentifier(s string) float64 {
+	switch {
+	case camelCaseRegexp.MatchString(s), snakeCaseRegexp.MatchString(s), dottedNameRegexp.MatchString(s) && !fileNameRegexp.MatchString(s):
+		return 0.9
+	case lowerWordRegexp.MatchString(s):
+		return 0.5
+	}
+
+	return 0
+}
+
+func scoreURL(s string) float64 {
+	switch {
+	case urlRegexp.MatchString(s):
+		return 1
+	case wwwRegexp.MatchString(s):
+		return 0.9
+	}
+
+	return 0
+}
+
+// file paths, e.g., /etc/hosts, ./bin or config/app.yml, a file name may also be a word of a message
+func scorePath(s string) float64 {
+	switch {
+	case absPathRegexp.MatchString(s) && len(s) > 1:
+		return 0.9
+	case relPathRegexp.MatchString(s):
+		return 0.75
+	case fileNameRegexp.MatchString(s):
+		return 0.5
+	}
+
+	return 0
+}
+
+func scoreMime(s string) float64 {
+	if mimeRegexp.MatchString(s) {
+		return 1
+	}
+
+	return 0
+}
+
+// strings made of format verbs, spaces and punctuation, e.g., "%s: %d\n", or of digits and punctuation, e.g., "--"
+func scoreFormat(s string) float64 {
+	rest := formatVerbRegexp.ReplaceAllString(s, "")
+	if strings.IndexFunc(rest, unicode.IsLetter) != -1 {
+		return 0
+	}
+
+	if rest != s {
+		return 1
+	}
+
+	return 0.75
+}
+
+func scoreSQL(s string) float64 {
+	switch {
+	case upperSQLRegexp.MatchString(s):
+		return 1
+	case sqlRegexp.MatchString(s):
+		return 0.5
+	}
+
+	return 0
+}
+
+// JSON documents, e.g., {"name": "app"}, and the kebab-case keys of JSON or YAML documents, e.g., api-version
+func scoreJSON(s string) float64 {
+	trimmed := strings.TrimSpace(s)
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		return 1
+	}
+
+	if kebabCaseRegexp.MatchString(s) {
+		return 0.75
+	}
+
+	return 0
+}
+
+// regular expressions, the more regexp syntax s has the surer it is, s must compile
+func scoreRegexp(s string) float64 {
+	count := len(regexpSyntaxRegexp.FindAllString(s, -1))
+	if count == 0 {
+		return 0
+	}
+
+	if _, err := regexp.Compile(s); err != nil {
+		return 0
+	}
+
+	if count > 1 {
+		return 0.9
+	}
+
+	return 0.5
+}
+
+func scoreStructTag(s string) float64 {
+	if structTagRegexp.MatchString(s) {
+		return 1
+	}
+
+	return 0
+}
+
+// SaveClassifications writes the --explain report of classifications in format, text or json
+func SaveClassifications(w io.Writer, format string, classifications []Classification) error {
+	switch format {
+	case JSON_EXPLAIN_FORMAT:
+		if classifications == nil {
+			classifications = []Classification{}
+		}
+
+		jsonData, err := json.MarshalIndent(classifications, "", "   ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(w, string(jsonData))
+		return err
+	case TEXT_EXPLAIN_FORMAT:
+		for _, classification := range classifications {
+			_, err := fmt.Fprintln(w, classification.String())
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("i18n4go: unknown explain format %q, expected %s or %s", format, TEXT_EXPLAIN_FORMAT, JSON_EXPLAIN_FORMAT)
+}
+
+// String formats a classification as one line of the text report, e.g.,
+// app.go:12:14: skip "https://example.com" by detector url [url 1.00]
+func (c Classification) String() string {
+	value := fmt.Sprintf("%q", c.Value)
+	if c.Context != "" {
+		value += fmt.Sprintf(" in context %q", c.Context)
+	}
+
+	line := fmt.Sprintf("%s:%d:%d: %s %s by %s", c.Filename, c.Line, c.Column, c.Decision, value, c.Reason)
+	if len(c.Detections) > 0 {
+		var detections []string
+		for _, detection := range c.Detections {
+			detections = append(detections, fmt.Sprintf("%s %.2f", detection.Detector, detection.Score))
+		}
+		line += " [" + strings.Join(detections, ", ") + "]"
+	}
+
+	return line
+}
@@ -10,6 +10,7 @@ type Options struct {
 	DryRunFlag  bool
 	PoFlag      bool
 	MetaFlag    bool
+	SinksFlag   bool
 
 	SourceLanguageFlag        string
 	LanguagesFlag             string
@@ -22,11 +23,23 @@ type Options struct {
 
 	ExcludedFilenameFlag  string
 	SubstringFilenameFlag string
+	RulesFilenameFlag     string
 	FilenameFlag          string
 	DirnameFlag           string
 
 	RecurseFlag bool
 
+	TagsFlag string
+
+	JobsFlag int
+
+	NoCacheFlag    bool
+	ClearCacheFlag bool
+	CacheDirFlag   string
+
+	ExplainFlag       bool
+	ExplainFormatFlag string
+
 	IgnoreRegexpFlag string
 
 	LanguageFilesFlag string
@@ -37,22 +50,54 @@ type Options struct {
 	RootPathFlag string
 
 	InitCodeSnippetFilenameFlag string
+	EmbedDirnameFlag            string
+
+	DiffFlag  bool
+	CheckFlag bool
+	PatchFlag string
+
+	QualifierFlag         string
+	TranslatorPackageFlag string
+	BackendFlag           string
+
+	MergeConcatenationsFlag bool
+
+	IdStrategyFlag string
 
-	QualifierFlag string
+	ArgNamesFilenameFlag string
 }
 
 type I18nStringInfo struct {
 	ID          string `json:"id"`
+	Context     string `json:"context,omitempty"`
 	Translation string `json:"translation"`
 	Modified    bool   `json:"modified"`
+	Description string `json:"description,omitempty"`
 }
 
 type StringInfo struct {
 	Filename string `json:"filename"`
 	Value    string `json:"value"`
+	ID       string `json:"id,omitempty"`
+	Offset   int    `json:"offset"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Sink     string `json:"sink,omitempty"`
+	Context  string `json:"context,omitempty"`
+
+	Description string `json:"description,omitempty"`
+
+	Occurrences []Occurrence `json:"occurrences,omitempty"`
+	Count       int          `json:"count,omitempty"`
+}
+
+// Occurrence is one place in the source where an extracted string is used
+type Occurrence struct {
+	Filename string `json:"filename"`
 	Offset   int    `json:"offset"`
 	Line     int    `json:"line"`
 	Column   int    `json:"column"`
+	Func     string `json:"func,omitempty"`
 }
 
 type ExcludedStrings struct {
@@ -61,6 +106,7 @@ type ExcludedStrings struct {
 	ExcludedRegexps     []string `json:"excludedRegexps"`
 	ExcludedFileRegexps []string `json:"excludedFileRegexps"`
 	EnforcedFuncs       []string `json:"enforcedFuncs"`
+	Sinks               []string `json:"sinks"`
 }
 
 type PrinterInterface interface {
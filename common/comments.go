@@ -0,0 +1,64 @@
+package common
+
+import (
+	"strings"
+
+	"go/ast"
+	"go/token"
+)
+
+// comments starting with one of these prefixes are notes for the translators
+var TRANSLATOR_COMMENT_PREFIXES = []string{"TRANSLATORS:", "i18n:"}
+
+// TranslatorComment returns the note of a comment text, without its prefix, and whether it is one
+func TranslatorComment(text string) (string, bool) {
+	text = strings.TrimSpace(text)
+	for _, prefix := range TRANSLATOR_COMMENT_PREFIXES {
+		if strings.HasPrefix(text, prefix) {
+			lines := strings.Split(strings.TrimSpace(text[len(prefix):]), "\n")
+			for i := range lines {
+				lines[i] = strings.TrimSpace(lines[i])
+			}
+			return strings.Join(lines, " "), true
+		}
+	}
+
+	return "", false
+}
+
+// TranslatorComments maps the lines of a file to the translator comments that describe the strings on them,
+// a comment describes the strings on its own line and, unless it follows some code, on the line below it
+func TranslatorComments(fset *token.FileSet, astFile *ast.File, src []byte) map[int]string {
+	descriptions := make(map[int]string)
+	for _, commentGroup := range astFile.Comments {
+		description, ok := TranslatorComment(commentGroup.Text())
+		if !ok || description == "" {
+			continue
+		}
+
+		start := fset.Position(commentGroup.Pos())
+		end := fset.Position(commentGroup.End())
+		lineStart := start.Offset - (start.Column - 1)
+		trailing := start.Offset <= len(src) && lineStart >= 0 && strings.TrimSpace(string(src[lineStart:start.Offset])) != ""
+
+		AddTranslatorComment(descriptions, start.Line, end.Line, !trailing, description)
+	}
+
+	return descriptions
+}
+
+// AddTranslatorComment records a comment that spans the lines startLine to endLine
+func AddTranslatorComment(descriptions map[int]string, startLine, endLine int, nextLine bool, description string) {
+	lines := []int{startLine}
+	if nextLine {
+		lines = append(lines, endLine+1)
+	}
+
+	for _, line := range lines {
+		if descriptions[line] == "" {
+			descriptions[line] = description
+		} else if descriptions[line] != description {
+			descriptions[line] += "\n" + description
+		}
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
+	"sort"
 
 	"io/ioutil"
 	"strconv"
@@ -17,11 +19,10 @@ import (
 )
 
 const (
-	TEMPLATED_STRING_REGEXP    = `\{\{\.[[:alnum:][:punct:][:print:]]+?\}\}`
-	INTERPOLATED_STRING_REGEXP = `%(?:[#v]|[%EGUTXbcdefgopqstvx])`
+	TEMPLATED_STRING_REGEXP = `\{\{\.[[:alnum:][:punct:][:print:]]+?\}\}`
 )
 
-var templatedStringRegexp, interpolatedStringRegexp *regexp.Regexp
+var templatedStringRegexp *regexp.Regexp
 
 func ParseStringList(stringList string, delimiter string) []string {
 	stringArray := strings.Split(stringList, delimiter)
@@ -124,10 +125,8 @@ func SaveStrings(printer PrinterInterface, options Options, stringInfos map[stri
 	}
 
 	i18nStringInfos := make([]I18nStringInfo, len(stringInfos))
-	i := 0
-	for _, stringInfo := range stringInfos {
-		i18nStringInfos[i] = I18nStringInfo{ID: stringInfo.Value, Translation: stringInfo.Value}
-		i++
+	for i, stringInfo := range StringInfoMapValues2Array(stringInfos) {
+		i18nStringInfos[i] = I18nStringInfo{ID: stringInfo.MessageID(), Context: stringInfo.Context, Translation: stringInfo.Value, Description: stringInfo.Description}
 	}
 
 	jsonData, err := json.MarshalIndent(i18nStringInfos, "", "   ")
@@ -176,12 +175,17 @@ func SaveStringsInPo(printer PrinterInterface, options Options, stringInfos map[
 			return err
 		}
 
-		for _, stringInfo := range stringInfos {
-			file.Write([]byte("# filename: " + strings.Split(fileName, ".en.po")[0] +
-				", offset: " + strconv.Itoa(stringInfo.Offset) +
-				", line: " + strconv.Itoa(stringInfo.Line) +
-				", column: " + strconv.Itoa(stringInfo.Column) + "\n"))
-			file.Write([]byte("msgid " + strconv.Quote(stringInfo.Value) + "\n"))
+		for _, stringInfo := range StringInfoMapValues2Array(stringInfos) {
+			occurrences := stringInfo.Occurrences
+			if len(occurrences) == 0 {
+				occurrences = []Occurrence{{Filename: strings.Split(fileName, ".en.po")[0], Line: stringInfo.Line}}
+			}
+			writePoDescription(file, stringInfo.Description)
+			for _, occurrence := range occurrences {
+				file.Write([]byte("#: " + occurrence.Filename + ":" + strconv.Itoa(occurrence.Line) + "\n"))
+			}
+			writePoContext(file, stringInfo.Context)
+			file.Write([]byte("msgid " + strconv.Quote(stringInfo.MessageID()) + "\n"))
 			file.Write([]byte("msgstr " + strconv.Quote(stringInfo.Value) + "\n"))
 			file.Write([]byte("\n"))
 		}
@@ -201,6 +205,8 @@ func SaveI18nStringsInPo(printer PrinterInterface, options Options, i18nStrings
 		}
 
 		for _, stringInfo := range i18nStrings {
+			writePoDescription(file, stringInfo.Description)
+			writePoContext(file, stringInfo.Context)
 			file.Write([]byte("msgid " + strconv.Quote(stringInfo.ID) + "\n"))
 			file.Write([]byte("msgstr " + strconv.Quote(stringInfo.Translation) + "\n"))
 			file.Write([]byte("\n"))
@@ -209,13 +215,32 @@ func SaveI18nStringsInPo(printer PrinterInterface, options Options, i18nStrings
 	return nil
 }
 
+// writePoDescription writes a description as PO extracted comments, one per line
+func writePoDescription(writer io.Writer, description string) {
+	if description == "" {
+		return
+	}
+
+	for _, line := range strings.Split(description, "\n") {
+		writer.Write([]byte("#. " + line + "\n"))
+	}
+}
+
+// writePoContext writes the msgctxt of a message with a context
+func writePoContext(writer io.Writer, context string) {
+	if context == "" {
+		return
+	}
+
+	writer.Write([]byte("msgctxt " + strconv.Quote(context) + "\n"))
+}
+
 func SaveI18nStringInfos(printer PrinterInterface, options Options, i18nStringInfos []I18nStringInfo, fileName string) error {
-	jsonData, err := json.MarshalIndent(i18nStringInfos, "", "   ")
+	jsonData, err := MarshalI18nStringInfos(i18nStringInfos)
 	if err != nil {
 		printer.Println(err)
 		return err
 	}
-	jsonData = UnescapeHTML(jsonData)
 
 	if !options.DryRunFlag && len(i18nStringInfos) != 0 {
 		err := ioutil.WriteFile(fileName, jsonData, 0644)
@@ -228,6 +253,16 @@ func SaveI18nStringInfos(printer PrinterInterface, options Options, i18nStringIn
 	return nil
 }
 
+// MarshalI18nStringInfos is the JSON content SaveI18nStringInfos writes
+func MarshalI18nStringInfos(i18nStringInfos []I18nStringInfo) ([]byte, error) {
+	jsonData, err := json.MarshalIndent(i18nStringInfos, "", "   ")
+	if err != nil {
+		return nil, err
+	}
+
+	return UnescapeHTML(jsonData), nil
+}
+
 func LoadI18nStringInfos(fileName string) ([]I18nStringInfo, error) {
 	_, err := os.Stat(fileName)
 	if os.IsNotExist(err) {
@@ -239,8 +274,13 @@ func LoadI18nStringInfos(fileName string) ([]I18nStringInfo, error) {
 		return nil, err
 	}
 
+	return ParseI18nStringInfos(content)
+}
+
+// ParseI18nStringInfos parses the JSON content of an i18n strings file
+func ParseI18nStringInfos(content []byte) ([]I18nStringInfo, error) {
 	var i18nStringInfos []I18nStringInfo
-	err = json.Unmarshal(content, &i18nStringInfos)
+	err := json.Unmarshal(content, &i18nStringInfos)
 	if err != nil {
 		return nil, err
 	}
@@ -248,13 +288,16 @@ func LoadI18nStringInfos(fileName string) ([]I18nStringInfo, error) {
 	return i18nStringInfos, nil
 }
 
+// CreateI18nStringInfoMap maps the strings by MessageKey, the same ID can be used once per context
 func CreateI18nStringInfoMap(i18nStringInfos []I18nStringInfo) (map[string]I18nStringInfo, error) {
 	inputMap := make(map[string]I18nStringInfo, len(i18nStringInfos))
 
 	for _, i18nStringInfo := range i18nStringInfos {
 
-		if _, ok := inputMap[i18nStringInfo.ID]; !ok {
-			inputMap[i18nStringInfo.ID] = i18nStringInfo
+		if _, ok := inputMap[i18nStringInfo.Key()]; !ok {
+			inputMap[i18nStringInfo.Key()] = i18nStringInfo
+		} else if i18nStringInfo.Context != "" {
+			return nil, errors.New("Duplicated key found: " + i18nStringInfo.ID + " in context " + i18nStringInfo.Context)
 		} else {
 			return nil, errors.New("Duplicated key found: " + i18nStringInfo.ID)
 		}
@@ -302,35 +345,42 @@ func IsTemplatedString(aString string) bool {
 	return re.Match([]byte(aString))
 }
 
+// IsInterpolatedString is true when aString is a printf format string with a verb formatting an arg
 func IsInterpolatedString(aString string) bool {
-	re, err := getInterpolatedStringRegexp()
-	if err != nil {
-		fmt.Printf("i18n4go: Error compiling interpolated string Regexp: %s\n", err.Error())
-		return false
-	}
-
-	return re.Match([]byte(aString))
+	return FormatArgCount(ParseFormat(aString)) > 0
 }
 
+// ConvertToTemplatedString converts the printf format string aString into a templated string, see ConvertFormatToTemplate
 func ConvertToTemplatedString(aString string) string {
 	if !IsInterpolatedString(aString) {
 		return aString
 	}
 
-	re, err := getInterpolatedStringRegexp()
-	if err != nil {
-		fmt.Printf("i18n4go: Error compiling interpolated string Regexp: %s\n", err.Error())
-		return ""
-	}
+	templatedString, _ := ConvertFormatToTemplate(aString)
+	return templatedString
+}
 
-	matches := re.FindAllStringSubmatch(aString, -1)
-	templatedString := aString
-	for i, match := range matches {
-		argName := "{{.Arg" + strconv.Itoa(i) + "}}"
-		templatedString = strings.Replace(templatedString, match[0], argName, 1)
+// StringInfoMapValues2Array returns the strings in the order they appear in the source files
+func StringInfoMapValues2Array(stringInfosMap map[string]StringInfo) []StringInfo {
+	var stringInfos []StringInfo
+	for _, stringInfo := range stringInfosMap {
+		stringInfos = append(stringInfos, stringInfo)
 	}
 
-	return templatedString
+	sort.Slice(stringInfos, func(i, j int) bool {
+		if stringInfos[i].Filename != stringInfos[j].Filename {
+			return stringInfos[i].Filename < stringInfos[j].Filename
+		}
+		if stringInfos[i].Offset != stringInfos[j].Offset {
+			return stringInfos[i].Offset < stringInfos[j].Offset
+		}
+		if stringInfos[i].Value != stringInfos[j].Value {
+			return stringInfos[i].Value < stringInfos[j].Value
+		}
+		return stringInfos[i].Context < stringInfos[j].Context
+	})
+
+	return stringInfos
 }
 
 func I18nStringInfoMapValues2Array(i18nStringInfosMap map[string]I18nStringInfo) []I18nStringInfo {
@@ -352,12 +402,3 @@ func getTemplatedStringRegexp() (*regexp.Regexp, error) {
 
 	return templatedStringRegexp, err
 }
-
-func getInterpolatedStringRegexp() (*regexp.Regexp, error) {
-	var err error
-	if interpolatedStringRegexp == nil {
-		interpolatedStringRegexp, err = regexp.Compile(INTERPOLATED_STRING_REGEXP)
-	}
-
-	return interpolatedStringRegexp, err
-}
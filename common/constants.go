@@ -0,0 +1,163 @@
+package common
+
+import (
+	"sort"
+	"strconv"
+
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+)
+
+// ConstFolder computes the value of constant string expressions, e.g., "Failed to " + "connect", including the
+// identifiers of string constants, with the type information of the package when it was type checked and with
+// the declarations of the file otherwise
+type ConstFolder struct {
+	info *types.Info
+}
+
+// FoldedString is a run of constant operands of a concatenation and the string they add up to
+type FoldedString struct {
+	Value    string
+	Operands []ast.Expr
+	Lits     []*ast.BasicLit
+}
+
+func NewConstFolder(info *types.Info) *ConstFolder {
+	return &ConstFolder{info: info}
+}
+
+// Fold returns the value of a constant string expression and whether expr is one
+func (cf *ConstFolder) Fold(expr ast.Expr) (string, bool) {
+	return cf.fold(expr, make(map[*ast.Object]bool))
+}
+
+func (cf *ConstFolder) fold(expr ast.Expr, visiting map[*ast.Object]bool) (string, bool) {
+	if cf != nil && cf.info != nil {
+		if typeAndValue, ok := cf.info.Types[expr]; ok && typeAndValue.Value != nil {
+			if typeAndValue.Value.Kind() != constant.String {
+				return "", false
+			}
+			return constant.StringVal(typeAndValue.Value), true
+		}
+	}
+
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		if x.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(x.Value)
+		return value, err == nil
+	case *ast.ParenExpr:
+		return cf.fold(x.X, visiting)
+	case *ast.BinaryExpr:
+		if x.Op != token.ADD {
+			return "", false
+		}
+		left, ok := cf.fold(x.X, visiting)
+		if !ok {
+			return "", false
+		}
+		right, ok := cf.fold(x.Y, visiting)
+		if !ok {
+			return "", false
+		}
+		return left + right, true
+	case *ast.Ident:
+		return cf.foldIdent(x, visiting)
+	}
+
+	return "", false
+}
+
+// foldIdent resolves a constant declared in the same file, an implicitly repeated value, e.g., with iota, is not resolved
+func (cf *ConstFolder) foldIdent(ident *ast.Ident, visiting map[*ast.Object]bool) (string, bool) {
+	obj := ident.Obj
+	if obj == nil || obj.Kind != ast.Con || visiting[obj] {
+		return "", false
+	}
+
+	valueSpec, ok := obj.Decl.(*ast.ValueSpec)
+	if !ok {
+		return "", false
+	}
+
+	for i, name := range valueSpec.Names {
+		if name.Name == ident.Name && i < len(valueSpec.Values) {
+			visiting[obj] = true
+			defer delete(visiting, obj)
+			return cf.fold(valueSpec.Values[i], visiting)
+		}
+	}
+
+	return "", false
+}
+
+// Concatenation returns the operands of a chain of +, e.g., a, b, c and d for a + (b + c) + d,
+// and the binary expressions joining them sorted by position
+func Concatenation(binaryExpr *ast.BinaryExpr) ([]ast.Expr, []*ast.BinaryExpr) {
+	var operands []ast.Expr
+	var joins []*ast.BinaryExpr
+
+	var flatten func(expr ast.Expr)
+	flatten = func(expr ast.Expr) {
+		inner := expr
+		for {
+			parenExpr, ok := inner.(*ast.ParenExpr)
+			if !ok {
+				break
+			}
+			inner = parenExpr.X
+		}
+
+		if x, ok := inner.(*ast.BinaryExpr); ok && x.Op == token.ADD {
+			joins = append(joins, x)
+			flatten(x.X)
+			flatten(x.Y)
+			return
+		}
+		operands = append(operands, expr)
+	}
+	flatten(binaryExpr)
+
+	sort.Slice(joins, func(i, j int) bool { return joins[i].OpPos < joins[j].OpPos })
+
+	return operands, joins
+}
+
+// FoldConcatenation returns the runs of two or more adjacent constant operands of a concatenation
+// with at least one string literal among them
+func (cf *ConstFolder) FoldConcatenation(binaryExpr *ast.BinaryExpr) []FoldedString {
+	operands, _ := Concatenation(binaryExpr)
+
+	var foldedStrings []FoldedString
+	var run FoldedString
+	endRun := func() {
+		if len(run.Operands) > 1 && len(run.Lits) > 0 {
+			foldedStrings = append(foldedStrings, run)
+		}
+		run = FoldedString{}
+	}
+
+	for _, operand := range operands {
+		value, ok := cf.Fold(operand)
+		if !ok {
+			endRun()
+			continue
+		}
+
+		run.Value += value
+		run.Operands = append(run.Operands, operand)
+		ast.Inspect(operand, func(n ast.Node) bool {
+			if basicLit, ok := n.(*ast.BasicLit); ok {
+				run.Lits = append(run.Lits, basicLit)
+			}
+			return true
+		})
+	}
+	endRun()
+
+	return foldedStrings
+}
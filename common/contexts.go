@@ -0,0 +1,80 @@
+package common
+
+import (
+	"strings"
+
+	"go/ast"
+)
+
+// the func of the generated init code that translates a message in a context, e.g., TC("menu", "Open")
+const CONTEXT_FUNC = "TC"
+
+// separates the context from the ID in the key of a message, as in gettext MO files
+const CONTEXT_SEPARATOR = "\x04"
+
+// separates the context from the ID of a message in the readable IDs of the runtimes without contexts, e.g., menu.Open
+const READABLE_CONTEXT_SEPARATOR = "."
+
+// MessageKey identifies a message by its context and ID, it is the ID when there is no context
+// so the keys of the messages without context do not change
+func MessageKey(context, id string) string {
+	if context == "" {
+		return id
+	}
+
+	return context + CONTEXT_SEPARATOR + id
+}
+
+// SplitMessageKey returns the context and the ID of a message key
+func SplitMessageKey(key string) (string, string) {
+	pieces := strings.SplitN(key, CONTEXT_SEPARATOR, 2)
+	if len(pieces) == 1 {
+		return "", key
+	}
+
+	return pieces[0], pieces[1]
+}
+
+// QuoteMessageKey quotes the ID of a message key followed by its context, e.g., "Open" in context "menu"
+func QuoteMessageKey(key string) string {
+	context, id := SplitMessageKey(key)
+	if context == "" {
+		return `"` + id + `"`
+	}
+
+	return `"` + id + `" in context "` + context + `"`
+}
+
+// DescribeMessageKey is the ID of a message key followed by its context when it has one, e.g., Open in context menu
+func DescribeMessageKey(key string) string {
+	context, id := SplitMessageKey(key)
+	if context == "" {
+		return id
+	}
+
+	return id + " in context " + context
+}
+
+func (info StringInfo) Key() string {
+	return MessageKey(info.Context, info.Value)
+}
+
+func (info I18nStringInfo) Key() string {
+	return MessageKey(info.Context, info.ID)
+}
+
+// IsContextCall is true for TC(context, message) and pkg.TC(context, message)
+func IsContextCall(call *ast.CallExpr) bool {
+	if len(call.Args) < 2 {
+		return false
+	}
+
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name == CONTEXT_FUNC
+	case *ast.SelectorExpr:
+		return fun.Sel.Name == CONTEXT_FUNC
+	}
+
+	return false
+}
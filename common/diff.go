@@ -0,0 +1,239 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// the lines of unchanged context around the changes of a hunk
+const DIFF_CONTEXT_LINES = 3
+
+// FileChange is the content a file would have after a rewrite, Created is true when the file does not exist yet.
+// FileName is the name of the file in diffs, Path is where it is written
+type FileChange struct {
+	FileName string
+	Path     string
+	Mode     os.FileMode
+	Created  bool
+
+	Old []byte
+	New []byte
+}
+
+// Changed is false when the file would keep its content
+func (c FileChange) Changed() bool {
+	return c.Created || string(c.Old) != string(c.New)
+}
+
+// Diff is the unified diff of the change, e.g., "--- a/app.go\n+++ b/app.go\n@@ -1,3 +1,3 @@\n..."
+func (c FileChange) Diff() string {
+	if !c.Changed() {
+		return ""
+	}
+
+	oldName := "a/" + c.FileName
+	if c.Created {
+		oldName = "/dev/null"
+	}
+
+	return UnifiedDiff(oldName, "b/"+c.FileName, c.Old, c.New)
+}
+
+// Patch is the diff of the change with the headers git apply expects
+func (c FileChange) Patch() string {
+	if !c.Changed() {
+		return ""
+	}
+
+	header := fmt.Sprintf("diff --git a/%s b/%s\n", c.FileName, c.FileName)
+	if c.Created {
+		mode := "100644"
+		if c.Mode&0111 != 0 {
+			mode = "100755"
+		}
+		header += fmt.Sprintf("new file mode %s\n", mode)
+	}
+
+	return header + c.Diff()
+}
+
+// diffOp is an edit of a diff, a line kept, deleted from the old lines or inserted from the new lines
+type diffOp struct {
+	kind     byte
+	oldIndex int
+	newIndex int
+}
+
+// UnifiedDiff returns the unified diff of the lines of from and to, labelled fromName and toName, or "" when they are the same
+func UnifiedDiff(fromName, toName string, from, to []byte) string {
+	oldLines, newLines := splitLines(string(from)), splitLines(string(to))
+	ops := diffLines(oldLines, newLines)
+
+	var hunks [][]diffOp
+	for start := 0; start < len(ops); {
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		first := start - DIFF_CONTEXT_LINES
+		if first < 0 {
+			first = 0
+		}
+
+		// a hunk goes on while the next change is close enough for their contexts to touch
+		end, kept := start, 0
+		for end < len(ops) && kept <= 2*DIFF_CONTEXT_LINES {
+			if ops[end].kind == ' ' {
+				kept++
+			} else {
+				kept = 0
+			}
+			end++
+		}
+		last := end - kept + DIFF_CONTEXT_LINES
+		if last > len(ops) {
+			last = len(ops)
+		}
+
+		hunks = append(hunks, ops[first:last])
+		start = last
+	}
+
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var diff strings.Builder
+	fmt.Fprintf(&diff, "--- %s\n+++ %s\n", fromName, toName)
+	for _, hunk := range hunks {
+		oldStart, oldCount, newStart, newCount := hunk[0].oldIndex+1, 0, hunk[0].newIndex+1, 0
+		for _, op := range hunk {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+
+		fmt.Fprintf(&diff, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+		for _, op := range hunk {
+			line := ""
+			switch op.kind {
+			case '-':
+				line = oldLines[op.oldIndex]
+			default:
+				line = newLines[op.newIndex]
+			}
+
+			diff.WriteByte(op.kind)
+			diff.WriteString(line)
+			if !strings.HasSuffix(line, "\n") {
+				diff.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+
+	return diff.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits s after each newline, the last line has no newline when s does not end with one
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines returns the shortest edit script turning oldLines into newLines, using the greedy algorithm of Myers
+func diffLines(oldLines, newLines []string) []diffOp {
+	n, m := len(oldLines), len(newLines)
+	max := n + m
+	offset := max + 1
+
+	v := make([]int, 2*max+3)
+	var trace [][]int
+
+	found := false
+	for d := 0; d <= max && !found; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+
+			for x < n && y < m && oldLines[x] == newLines[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	// the script is rebuilt backwards from the snapshots of v taken before each step
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, diffOp{kind: ' ', oldIndex: x, newIndex: y})
+		}
+
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{kind: '+', oldIndex: x, newIndex: prevY})
+			} else {
+				ops = append(ops, diffOp{kind: '-', oldIndex: prevX, newIndex: y})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
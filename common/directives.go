@@ -0,0 +1,244 @@
+package common
+
+import (
+	"strings"
+
+	"go/ast"
+	"go/token"
+)
+
+// comments like //i18n4go:ignore are directives, the text after the directive name is free form, e.g., a reason
+const DIRECTIVE_PREFIX = "i18n4go:"
+
+const (
+	IGNORE_DIRECTIVE           = "ignore"
+	IGNORE_NEXT_LINE_DIRECTIVE = "ignore-next-line"
+	IGNORE_START_DIRECTIVE     = "ignore-start"
+	IGNORE_END_DIRECTIVE       = "ignore-end"
+	IGNORE_FILE_DIRECTIVE      = "ignore-file"
+	TRANSLATE_DIRECTIVE        = "translate"
+	CONTEXT_DIRECTIVE          = "context"
+)
+
+type lineRange struct {
+	start, end int
+}
+
+// Directives are the i18n4go directives found in the comments of a file:
+//   - //i18n4go:ignore ignores the strings on its line, /*i18n4go:ignore*/ right after a string only ignores that string
+//   - //i18n4go:ignore-next-line ignores the strings on the line below it
+//   - //i18n4go:ignore-start and //i18n4go:ignore-end ignore the strings of the lines between them
+//   - //i18n4go:ignore-file ignores the whole file
+//   - //i18n4go:translate forces the strings on its line, or the string before it, to be translated
+//   - //i18n4go:context menu sets the message context of the strings on its line, or of the string before it, to menu
+type Directives struct {
+	IgnoreFile bool
+
+	file            *token.File
+	ignoredLines    map[int]bool
+	ignoredRanges   []lineRange
+	ignoredLits     map[token.Pos]bool
+	translatedLines map[int]bool
+	translatedLits  map[token.Pos]bool
+	contextLines    map[int]string
+	contextLits     map[token.Pos]string
+}
+
+// Directive returns the name of the directive in a comment, e.g., ignore-next-line, and whether the comment is one
+func Directive(comment string) (string, bool) {
+	name, _, ok := parseDirective(comment)
+	return name, ok
+}
+
+// parseDirective returns the name of the directive in a comment and the text after it, e.g., the context of a context directive
+func parseDirective(comment string) (string, string, bool) {
+	text := comment
+	if strings.HasPrefix(text, "//") {
+		text = text[2:]
+	} else if strings.HasPrefix(text, "/*") {
+		text = strings.TrimSuffix(text[2:], "*/")
+	}
+
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, DIRECTIVE_PREFIX) {
+		return "", "", false
+	}
+
+	fields := strings.Fields(text[len(DIRECTIVE_PREFIX):])
+	if len(fields) == 0 {
+		return "", "", false
+	}
+
+	return fields[0], strings.Join(fields[1:], " "), true
+}
+
+// ParseDirectives finds the directives in the comments of astFile, which must be parsed with parser.ParseComments
+func ParseDirectives(fset *token.FileSet, astFile *ast.File) *Directives {
+	directives := &Directives{
+		file:            fset.File(astFile.Pos()),
+		ignoredLines:    make(map[int]bool),
+		ignoredLits:     make(map[token.Pos]bool),
+		translatedLines: make(map[int]bool),
+		translatedLits:  make(map[token.Pos]bool),
+		contextLines:    make(map[int]string),
+		contextLits:     make(map[token.Pos]string),
+	}
+
+	var inlineComments []*ast.Comment
+	ignoreStart := 0
+	for _, commentGroup := range astFile.Comments {
+		for _, comment := range commentGroup.List {
+			name, argument, ok := parseDirective(comment.Text)
+			if !ok {
+				continue
+			}
+
+			line := fset.Position(comment.Pos()).Line
+			switch name {
+			case IGNORE_DIRECTIVE, TRANSLATE_DIRECTIVE, CONTEXT_DIRECTIVE:
+				if strings.HasPrefix(comment.Text, "/*") {
+					inlineComments = append(inlineComments, comment)
+				} else if name == IGNORE_DIRECTIVE {
+					directives.ignoredLines[line] = true
+				} else if name == TRANSLATE_DIRECTIVE {
+					directives.translatedLines[line] = true
+				} else {
+					directives.contextLines[line] = argument
+				}
+			case IGNORE_NEXT_LINE_DIRECTIVE:
+				directives.ignoredLines[fset.Position(comment.End()).Line+1] = true
+			case IGNORE_START_DIRECTIVE:
+				if ignoreStart == 0 {
+					ignoreStart = line
+				}
+			case IGNORE_END_DIRECTIVE:
+				if ignoreStart != 0 {
+					directives.ignoredRanges = append(directives.ignoredRanges, lineRange{start: ignoreStart, end: line})
+					ignoreStart = 0
+				}
+			case IGNORE_FILE_DIRECTIVE:
+				directives.IgnoreFile = true
+			}
+		}
+	}
+
+	// a block without its end runs to the end of the file
+	if ignoreStart != 0 {
+		directives.ignoredRanges = append(directives.ignoredRanges, lineRange{start: ignoreStart, end: int(^uint(0) >> 1)})
+	}
+
+	directives.addInlineComments(fset, astFile, inlineComments)
+
+	return directives
+}
+
+// addInlineComments applies a /*i18n4go:...*/ comment to the string that ends closest before it on the same line,
+// or to the whole line when there is none
+func (d *Directives) addInlineComments(fset *token.FileSet, astFile *ast.File, inlineComments []*ast.Comment) {
+	if len(inlineComments) == 0 {
+		return
+	}
+
+	litsByLine := make(map[int][]*ast.BasicLit)
+	ast.Inspect(astFile, func(n ast.Node) bool {
+		if basicLit, ok := n.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+			line := fset.Position(basicLit.End()).Line
+			litsByLine[line] = append(litsByLine[line], basicLit)
+		}
+		return true
+	})
+
+	for _, comment := range inlineComments {
+		name, argument, _ := parseDirective(comment.Text)
+		line := fset.Position(comment.Pos()).Line
+
+		var target *ast.BasicLit
+		for _, basicLit := range litsByLine[line] {
+			if basicLit.End() <= comment.Pos() && (target == nil || basicLit.End() > target.End()) {
+				target = basicLit
+			}
+		}
+
+		switch {
+		case target != nil && name == IGNORE_DIRECTIVE:
+			d.ignoredLits[target.Pos()] = true
+		case target != nil && name == TRANSLATE_DIRECTIVE:
+			d.translatedLits[target.Pos()] = true
+		case target != nil:
+			d.contextLits[target.Pos()] = argument
+		case name == IGNORE_DIRECTIVE:
+			d.ignoredLines[line] = true
+		case name == TRANSLATE_DIRECTIVE:
+			d.translatedLines[line] = true
+		default:
+			d.contextLines[line] = argument
+		}
+	}
+}
+
+// Ignores is true when a directive excludes the string at node from translation
+func (d *Directives) Ignores(node ast.Node) bool {
+	if d == nil {
+		return false
+	}
+	if d.IgnoreFile {
+		return true
+	}
+
+	line, ok := d.line(node.Pos())
+	if !ok {
+		return false
+	}
+	if d.ignoredLits[node.Pos()] || d.ignoredLines[line] {
+		return true
+	}
+
+	for _, ignoredRange := range d.ignoredRanges {
+		if line >= ignoredRange.start && line <= ignoredRange.end {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Translates is true when a directive forces the string at node to be translated
+func (d *Directives) Translates(node ast.Node) bool {
+	if d == nil {
+		return false
+	}
+
+	line, ok := d.line(node.Pos())
+	if !ok {
+		return false
+	}
+
+	return d.translatedLits[node.Pos()] || d.translatedLines[line]
+}
+
+// Context returns the message context a directive sets for the string at node, "" when there is none
+func (d *Directives) Context(node ast.Node) string {
+	if d == nil {
+		return ""
+	}
+
+	if context, ok := d.contextLits[node.Pos()]; ok {
+		return context
+	}
+
+	line, ok := d.line(node.Pos())
+	if !ok {
+		return ""
+	}
+
+	return d.contextLines[line]
+}
+
+// line is the line of pos, nodes created or moved while rewriting a file have no line
+func (d *Directives) line(pos token.Pos) (int, bool) {
+	if d.file == nil || !pos.IsValid() || int(pos) < d.file.Base() || int(pos) > d.file.Base()+d.file.Size() {
+		return 0, false
+	}
+
+	return d.file.Line(pos), true
+}
@@ -0,0 +1,103 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TextEdit replaces the bytes of a source from Offset up to End with Text
+type TextEdit struct {
+	Offset int
+	End    int
+	Text   string
+}
+
+// ApplyTextEdits returns src with edits applied, the edits must not overlap
+func ApplyTextEdits(src []byte, edits []TextEdit) ([]byte, error) {
+	sorted := append([]TextEdit(nil), edits...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Offset < sorted[j].Offset
+	})
+
+	var buffer bytes.Buffer
+	last := 0
+	for _, edit := range sorted {
+		if edit.Offset < last || edit.End < edit.Offset || edit.End > len(src) {
+			return nil, fmt.Errorf("i18n4go: invalid edit of bytes %d to %d", edit.Offset, edit.End)
+		}
+
+		buffer.Write(src[last:edit.Offset])
+		buffer.WriteString(edit.Text)
+		last = edit.End
+	}
+	buffer.Write(src[last:])
+
+	return buffer.Bytes(), nil
+}
+
+// EditedLines returns the lines, counted from 0, of the source the edits produce that hold the text of an edit
+func EditedLines(src []byte, edits []TextEdit) map[int]bool {
+	sorted := append([]TextEdit(nil), edits...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Offset < sorted[j].Offset
+	})
+
+	lines := make(map[int]bool)
+	line, last := 0, 0
+	for _, edit := range sorted {
+		line += bytes.Count(src[last:edit.Offset], []byte("\n"))
+		lastLine := line + strings.Count(edit.Text, "\n")
+		for ; line <= lastLine; line++ {
+			lines[line] = true
+		}
+		line = lastLine
+		last = edit.End
+	}
+
+	return lines
+}
+
+// ChangeLines returns src with the changes of changed, e.g., its gofmt-ed source, that touch the given lines of src, counted
+// from 0, the other lines of src are kept as is
+func ChangeLines(src, changed []byte, lines map[int]bool) []byte {
+	oldLines, newLines := splitLines(string(src)), splitLines(string(changed))
+	ops := diffLines(oldLines, newLines)
+
+	var buffer bytes.Buffer
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			buffer.WriteString(oldLines[ops[start].oldIndex])
+			start++
+			continue
+		}
+
+		// a run of deleted and inserted lines is one change, it touches the lines it replaces, a change only inserting or only
+		// deleting lines, e.g., a blank line, also touches the lines around it
+		end, touched, replaces := start, false, false
+		for end < len(ops) && ops[end].kind != ' ' {
+			touched = touched || (ops[end].kind == '-' && lines[ops[end].oldIndex])
+			replaces = replaces || ops[end].kind != ops[start].kind
+			end++
+		}
+		if !replaces {
+			before, after := ops[start].oldIndex-1, ops[end-1].oldIndex
+			if ops[start].kind == '-' {
+				after++
+			}
+			touched = touched || lines[before] || lines[after]
+		}
+
+		for _, op := range ops[start:end] {
+			if touched && op.kind == '+' {
+				buffer.WriteString(newLines[op.newIndex])
+			} else if !touched && op.kind == '-' {
+				buffer.WriteString(oldLines[op.oldIndex])
+			}
+		}
+		start = end
+	}
+
+	return buffer.Bytes()
+}
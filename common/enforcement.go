@@ -0,0 +1,252 @@
+package common
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+var (
+	enforcedNameRegexp    = regexp.MustCompile(`^[\w*?\[\]]+$`)
+	majorVersionRegexp    = regexp.MustCompile(`^v[0-9]+$`)
+	gopkgInVersionRegexp  = regexp.MustCompile(`\.v[0-9]+$`)
+	enforcedFieldsRegexp  = regexp.MustCompile(`^(.+)\{([^{}]*)\}$`)
+	enforcedPackageRegexp = regexp.MustCompile(`^[\w./-]+$`)
+)
+
+// EnforcedCall is an enforced func of the rules, the strings passed to it are extracted even when an exclusion rule matches them.
+// A plain name such as "Say" matches the calls of any func or method with that name and enforces its first string argument,
+// a qualified name such as "flag.String:3" or "github.com/org/ui.UI.Say" only matches that func or method and enforces
+// the arguments at the listed positions, starting at 1, or every argument when there is none.
+// Methods are only matched when packages are loaded with type information, i.e., with --sinks
+type EnforcedCall struct {
+	Sink
+
+	Positions []int
+}
+
+// EnforcedField enforces the strings of some fields of the composite literals of a type, e.g., "cli.Command{Usage,Description}"
+type EnforcedField struct {
+	Pattern string
+
+	PkgPath string
+	Type    string
+	Fields  []string
+}
+
+// ParseEnforcedCall parses an enforced func such as "Say", "flag.String:3", "fmt.Sprintf:1" or "github.com/org/ui.UI.Say:1,2"
+func ParseEnforcedCall(pattern string) (EnforcedCall, error) {
+	invalid := fmt.Errorf("i18n4go: invalid enforced func: %s, expected Func, package.Func or package.Type.Method optionally followed by :positions", pattern)
+
+	name := pattern
+	var positions []int
+	if colon := strings.LastIndex(pattern, ":"); colon != -1 {
+		name = pattern[:colon]
+		for _, position := range strings.Split(pattern[colon+1:], ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(position))
+			if err != nil || n < 1 {
+				return EnforcedCall{}, invalid
+			}
+			positions = append(positions, n)
+		}
+	}
+
+	if enforcedNameRegexp.MatchString(name) {
+		return EnforcedCall{Sink: Sink{Pattern: pattern, Name: name}, Positions: positions}, nil
+	}
+
+	sink, err := ParseSink(name)
+	if err != nil || !enforcedPackageRegexp.MatchString(sink.PkgPath) || !enforcedNameRegexp.MatchString(sink.Name) ||
+		(sink.Recv != "" && !enforcedNameRegexp.MatchString(sink.Recv)) {
+		return EnforcedCall{}, invalid
+	}
+	sink.Pattern = pattern
+
+	return EnforcedCall{Sink: sink, Positions: positions}, nil
+}
+
+// ParseEnforcedField parses enforced fields such as "cli.Command{Usage,Description}" or "github.com/spf13/cobra.Command{Short,Long}"
+func ParseEnforcedField(pattern string) (EnforcedField, error) {
+	invalid := fmt.Errorf("i18n4go: invalid enforced field: %s, expected package.Type{Field,...}", pattern)
+
+	submatches := enforcedFieldsRegexp.FindStringSubmatch(pattern)
+	if submatches == nil {
+		return EnforcedField{}, invalid
+	}
+
+	sink, err := ParseSink(submatches[1])
+	if err != nil || sink.Recv != "" || !enforcedPackageRegexp.MatchString(sink.PkgPath) || !enforcedNameRegexp.MatchString(sink.Name) {
+		return EnforcedField{}, invalid
+	}
+
+	enforcedField := EnforcedField{Pattern: pattern, PkgPath: sink.PkgPath, Type: sink.Name}
+	for _, field := range strings.Split(submatches[2], ",") {
+		field = strings.TrimSpace(field)
+		if !token.IsIdentifier(field) {
+			return EnforcedField{}, invalid
+		}
+		enforcedField.Fields = append(enforcedField.Fields, field)
+	}
+
+	return enforcedField, nil
+}
+
+// Qualified is false for the enforced funcs matched by name only
+func (ec EnforcedCall) Qualified() bool {
+	return ec.PkgPath != ""
+}
+
+// Enforces is true when the argument at index, starting at 0, is enforced
+func (ec EnforcedCall) Enforces(index int) bool {
+	if len(ec.Positions) == 0 {
+		return true
+	}
+
+	for _, position := range ec.Positions {
+		if position == index+1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchCall is true when call calls the func or method of ec, the types of pkg are used when it is not nil
+func (ec EnforcedCall) MatchCall(call *ast.CallExpr, imports Imports, pkg *TypedPackage) bool {
+	fun, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	if !ec.Qualified() {
+		ok, _ := path.Match(ec.Name, fun.Sel.Name)
+		return ok
+	}
+
+	if pkg != nil {
+		if fn, ok := pkg.Info.Uses[fun.Sel].(*types.Func); ok && fn.Pkg() != nil {
+			sink := ec.Sink
+			if matchesPackageName(sink.PkgPath, fn.Pkg()) {
+				sink.PkgPath = fn.Pkg().Path()
+			}
+			return sink.matches(fn, pkg.Types)
+		}
+	}
+
+	if ec.Recv != "" {
+		return false
+	}
+
+	if ok, _ := path.Match(ec.Name, fun.Sel.Name); !ok {
+		return false
+	}
+
+	return imports.matchesPackage(fun.X, ec.PkgPath)
+}
+
+// Enforces is true when field is one of the enforced fields
+func (f EnforcedField) Enforces(field string) bool {
+	for _, enforcedField := range f.Fields {
+		if enforcedField == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchType is true when the composite literal lit is of the type of f, typeExpr is the type of lit when it is elided,
+// the types of pkg are used when it is not nil
+func (f EnforcedField) MatchType(lit *ast.CompositeLit, typeExpr ast.Expr, imports Imports, pkg *TypedPackage) bool {
+	if pkg != nil {
+		if litType := pkg.Info.TypeOf(lit); litType != nil {
+			if pointer, ok := litType.(*types.Pointer); ok {
+				litType = pointer.Elem()
+			}
+
+			named, ok := litType.(*types.Named)
+			if !ok || named.Obj().Pkg() == nil {
+				return false
+			}
+
+			ok, _ = path.Match(f.Type, named.Obj().Name())
+			return ok && (named.Obj().Pkg().Path() == f.PkgPath || matchesPackageName(f.PkgPath, named.Obj().Pkg()))
+		}
+	}
+
+	if star, ok := typeExpr.(*ast.StarExpr); ok {
+		typeExpr = star.X
+	}
+
+	selector, ok := typeExpr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	if ok, _ := path.Match(f.Type, selector.Sel.Name); !ok {
+		return false
+	}
+
+	return imports.matchesPackage(selector.X, f.PkgPath)
+}
+
+// matchesPackageName is true when pkgPath is the name of pkg rather than its import path, e.g., cli for github.com/urfave/cli
+func matchesPackageName(pkgPath string, pkg *types.Package) bool {
+	return !strings.Contains(pkgPath, "/") && pkg.Name() == pkgPath
+}
+
+// Imports maps the names the packages imported by a file are known by to their import paths
+type Imports map[string]string
+
+// FileImports returns the imports of a file, the blank and dot imports are left out
+func FileImports(astFile *ast.File) Imports {
+	imports := make(Imports)
+	for _, importSpec := range astFile.Imports {
+		importPath, err := strconv.Unquote(importSpec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		name := ImportName(importPath)
+		if importSpec.Name != nil {
+			name = importSpec.Name.Name
+		}
+		if name != "_" && name != "." {
+			imports[name] = importPath
+		}
+	}
+
+	return imports
+}
+
+// ImportName guesses the name of the package of an import path, e.g., cli for github.com/urfave/cli/v2 or gopkg.in/urfave/cli.v1
+func ImportName(importPath string) string {
+	pieces := strings.Split(importPath, "/")
+	name := pieces[len(pieces)-1]
+	if majorVersionRegexp.MatchString(name) && len(pieces) > 1 {
+		name = pieces[len(pieces)-2]
+	}
+
+	return gopkgInVersionRegexp.ReplaceAllString(name, "")
+}
+
+// matchesPackage is true when x is the name of an imported package whose import path or name is pkgPath
+func (imports Imports) matchesPackage(x ast.Expr, pkgPath string) bool {
+	ident, ok := x.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	importPath, ok := imports[ident.Name]
+	if !ok {
+		return false
+	}
+
+	return importPath == pkgPath || (!strings.Contains(pkgPath, "/") && ImportName(importPath) == pkgPath)
+}
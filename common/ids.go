@@ -0,0 +1,233 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// the strategies that give its ID to a message
+const (
+	// the ID is the source text, e.g., "Invalid password"
+	SOURCE_ID_STRATEGY = "source"
+	// the ID is a short hash of the context and the source text, e.g., "5f1d0e7a3c"
+	HASH_ID_STRATEGY = "hash"
+	// the ID is built from the package, the function and the first words of the source text, e.g., "login.checkInvalidPassword"
+	SEMANTIC_ID_STRATEGY = "semantic"
+)
+
+var ID_STRATEGIES = []string{SOURCE_ID_STRATEGY, HASH_ID_STRATEGY, SEMANTIC_ID_STRATEGY}
+
+// the number of hex digits of a hash ID
+const HASH_ID_LENGTH = 10
+
+// the number of words of the source text kept in a semantic ID
+const SEMANTIC_ID_WORDS = 4
+
+var (
+	hashIDRegexp     = regexp.MustCompile(fmt.Sprintf("^[0-9a-f]{%d}$", HASH_ID_LENGTH))
+	semanticIDRegexp = regexp.MustCompile(`^[A-Za-z_]\w*\.[A-Za-z]\w*$`)
+)
+
+// IsIDStrategy is true when strategy is one of the ID_STRATEGIES
+func IsIDStrategy(strategy string) bool {
+	for _, idStrategy := range ID_STRATEGIES {
+		if strategy == idStrategy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckIDStrategy returns an error for an unknown ID strategy
+func CheckIDStrategy(strategy string) error {
+	if !IsIDStrategy(strategy) {
+		return fmt.Errorf("i18n4go: unknown ID strategy %q, expected one of: %s", strategy, strings.Join(ID_STRATEGIES, ", "))
+	}
+
+	return nil
+}
+
+// MatchesIDStrategy is true when id looks like the IDs of strategy, every ID matches the source strategy
+func MatchesIDStrategy(strategy, id string) bool {
+	switch strategy {
+	case HASH_ID_STRATEGY:
+		return hashIDRegexp.MatchString(id)
+	case SEMANTIC_ID_STRATEGY:
+		return semanticIDRegexp.MatchString(id)
+	}
+
+	return true
+}
+
+// HashID is the hash ID of a message, the same text in different contexts has different IDs
+func HashID(context, value string) string {
+	sum := sha256.Sum256([]byte(MessageKey(context, value)))
+	return hex.EncodeToString(sum[:])[:HASH_ID_LENGTH]
+}
+
+// SemanticID is the semantic ID of a message used in funcName of package pkgName, funcName is "" outside of functions
+// and Type.Method for methods, the format verbs and template args of the message are left out
+func SemanticID(pkgName, funcName, value string) string {
+	var words []string
+	for _, piece := range strings.Split(funcName, ".") {
+		words = append(words, slugWords(piece)...)
+	}
+
+	value = formatVerbRegexp.ReplaceAllString(value, " ")
+	if templatedRegexp, err := getTemplatedStringRegexp(); err == nil {
+		value = templatedRegexp.ReplaceAllString(value, " ")
+	}
+
+	valueWords := slugWords(value)
+	if len(valueWords) > SEMANTIC_ID_WORDS {
+		valueWords = valueWords[:SEMANTIC_ID_WORDS]
+	}
+	words = append(words, valueWords...)
+	if len(valueWords) == 0 {
+		words = append(words, "message")
+	}
+
+	var slug strings.Builder
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if i > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		slug.WriteString(word)
+	}
+
+	if pkgName == "" {
+		pkgName = "main"
+	}
+
+	id := slug.String()
+	if unicode.IsDigit(rune(id[0])) {
+		id = "n" + id
+	}
+
+	return pkgName + "." + id
+}
+
+// slugWords splits s into its words of ASCII letters and digits, camelCase words are split too
+func slugWords(s string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+
+	for i, r := range s {
+		isLetterOrDigit := r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+		if !isLetterOrDigit {
+			flush()
+			continue
+		}
+
+		if unicode.IsUpper(r) && i > 0 && len(word) > 0 && unicode.IsLower(word[len(word)-1]) {
+			flush()
+		}
+		word = append(word, r)
+	}
+	flush()
+
+	return words
+}
+
+// IDGenerator gives their IDs to the messages of a package following a strategy, a semantic ID already given to another message
+// gets the start of the hash of the message appended so IDs are unique
+type IDGenerator struct {
+	strategy string
+	pkgName  string
+
+	keys map[string]string
+}
+
+func NewIDGenerator(strategy, pkgName string) *IDGenerator {
+	return &IDGenerator{
+		strategy: strategy,
+		pkgName:  pkgName,
+		keys:     make(map[string]string),
+	}
+}
+
+// ID returns the ID of the message value in context used in funcName
+func (g *IDGenerator) ID(funcName, context, value string) string {
+	var id string
+	switch g.strategy {
+	case HASH_ID_STRATEGY:
+		return HashID(context, value)
+	case SEMANTIC_ID_STRATEGY:
+		id = SemanticID(g.pkgName, funcName, value)
+	default:
+		return value
+	}
+
+	key := MessageKey(context, value)
+	if existing, ok := g.keys[id]; ok && existing != key {
+		id += "_" + HashID(context, value)[:4]
+	}
+	g.keys[id] = key
+
+	return id
+}
+
+// Reserve records the ID a message already has, no other message of the package is given it
+func (g *IDGenerator) Reserve(context, value, id string) {
+	g.keys[id] = MessageKey(context, value)
+}
+
+// AssignIDs sets the IDs of the extracted strings of a file, a string whose key is in existingIDs keeps that ID so that
+// fixing its source text does not change it, the other strings are visited in order of their keys so the same strings
+// always get the same IDs, the source strategy leaves the ID empty
+func (g *IDGenerator) AssignIDs(stringInfos map[string]StringInfo, existingIDs map[string]string) {
+	if g.strategy == "" || g.strategy == SOURCE_ID_STRATEGY {
+		return
+	}
+
+	keys := make([]string, 0, len(stringInfos))
+	for key := range stringInfos {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		stringInfo := stringInfos[key]
+		if id, ok := existingIDs[key]; ok {
+			g.Reserve(stringInfo.Context, stringInfo.Value, id)
+			stringInfo.ID = id
+			stringInfos[key] = stringInfo
+		}
+	}
+
+	for _, key := range keys {
+		stringInfo := stringInfos[key]
+		if _, ok := existingIDs[key]; ok {
+			continue
+		}
+
+		funcName := ""
+		if len(stringInfo.Occurrences) > 0 {
+			funcName = stringInfo.Occurrences[0].Func
+		}
+		stringInfo.ID = g.ID(funcName, stringInfo.Context, stringInfo.Value)
+		stringInfos[key] = stringInfo
+	}
+}
+
+// MessageID is the ID of an extracted string, its source text unless an ID strategy gave it another ID
+func (info StringInfo) MessageID() string {
+	if info.ID != "" {
+		return info.ID
+	}
+
+	return info.Value
+}
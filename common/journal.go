@@ -0,0 +1,80 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// the undo journal of the last rewrite, saved in its output dir
+const REWRITE_JOURNAL_FILENAME = ".i18n4go_rewrite_journal.json"
+
+// RewriteJournal records the content the files of a rewrite had before it, so that it can be undone
+type RewriteJournal struct {
+	Files []JournalFile `json:"files"`
+}
+
+// JournalFile is a file of a rewrite, Old is its content before the rewrite, unless it was Created by it,
+// and NewDigest the SHA-256 of the content the rewrite gave it
+type JournalFile struct {
+	Path      string      `json:"path"`
+	Mode      os.FileMode `json:"mode"`
+	Created   bool        `json:"created,omitempty"`
+	Old       []byte      `json:"old,omitempty"`
+	NewDigest string      `json:"newDigest"`
+}
+
+// NewRewriteJournal records the changes of a rewrite, their paths are made absolute
+func NewRewriteJournal(changes []FileChange) (*RewriteJournal, error) {
+	journal := &RewriteJournal{}
+	for _, change := range changes {
+		path, err := filepath.Abs(change.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		journal.Files = append(journal.Files, JournalFile{
+			Path:      path,
+			Mode:      change.Mode,
+			Created:   change.Created,
+			Old:       change.Old,
+			NewDigest: ContentDigest(change.New),
+		})
+	}
+
+	return journal, nil
+}
+
+// ContentDigest is the hex SHA-256 of content
+func ContentDigest(content []byte) string {
+	digest := sha256.Sum256(content)
+	return hex.EncodeToString(digest[:])
+}
+
+func SaveRewriteJournal(journal *RewriteJournal, fileName string) error {
+	jsonData, err := json.MarshalIndent(journal, "", "   ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, jsonData, 0644)
+}
+
+func LoadRewriteJournal(fileName string) (*RewriteJournal, error) {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("i18n4go: could not read the rewrite journal %s: %w", fileName, err)
+	}
+
+	var journal RewriteJournal
+	err = json.Unmarshal(content, &journal)
+	if err != nil {
+		return nil, fmt.Errorf("i18n4go: could not parse the rewrite journal %s: %w", fileName, err)
+	}
+
+	return &journal, nil
+}
@@ -0,0 +1,450 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+)
+
+type ListedModule struct {
+	Path string
+	Dir  string
+	Main bool
+}
+
+type ListedPackageError struct {
+	Err string
+}
+
+// ListedPackage is the subset of `go list -json` that i18n4go needs to find the sources of a package
+type ListedPackage struct {
+	ImportPath   string
+	Name         string
+	Dir          string
+	GoFiles      []string
+	CgoFiles     []string
+	TestGoFiles  []string
+	XTestGoFiles []string
+	Module       *ListedModule
+	Error        *ListedPackageError
+}
+
+// SourceFiles returns the absolute paths of the files selected by the build tags, sorted
+func (lp *ListedPackage) SourceFiles(withTests bool) []string {
+	fileNames := append([]string{}, lp.GoFiles...)
+	fileNames = append(fileNames, lp.CgoFiles...)
+	if withTests {
+		fileNames = append(fileNames, lp.TestGoFiles...)
+		fileNames = append(fileNames, lp.XTestGoFiles...)
+	}
+
+	for i, fileName := range fileNames {
+		fileNames[i] = filepath.Join(lp.Dir, fileName)
+	}
+	sort.Strings(fileNames)
+
+	return fileNames
+}
+
+// ListPackages uses the go command so that go.mod, go.work, replace directives, build tags
+// and vendor directories are resolved the same way `go build` resolves them
+func ListPackages(dirName string, tags string, patterns ...string) ([]*ListedPackage, error) {
+	args := []string{"list", "-e", "-json"}
+	if tags != "" {
+		args = append(args, "-tags", tags)
+	}
+	args = append(args, patterns...)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dirName
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("i18n4go: go list failed in %s: %s", dirName, strings.TrimSpace(stderr.String()))
+	}
+
+	var listedPackages []*ListedPackage
+	decoder := json.NewDecoder(&stdout)
+	for {
+		var listedPackage ListedPackage
+		err = decoder.Decode(&listedPackage)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		listedPackages = append(listedPackages, &listedPackage)
+	}
+
+	return listedPackages, nil
+}
+
+// FindPackage returns the package in dirName using the go command and falls back to go/build
+// for directories the go command cannot resolve, e.g., outside of any module
+func FindPackage(dirName string, tags string) (*ListedPackage, error) {
+	listedPackages, err := ListPackages(dirName, tags, ".")
+	if err == nil && len(listedPackages) == 1 && listedPackages[0].ImportPath != "" {
+		if listedPackages[0].ImportPath != "." {
+			return listedPackages[0], nil
+		}
+
+		// the go command lists a directory of a module without Go files, e.g., the module root, as "."
+		if module, err := findModule(dirName); err == nil {
+			listedPackage := listedPackages[0]
+			listedPackage.ImportPath = moduleImportPath(module, listedPackage.Dir)
+			listedPackage.Module = module
+			return listedPackage, nil
+		}
+	}
+
+	pkg, err := build.ImportDir(dirName, build.FindOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	absDirName, err := filepath.Abs(dirName)
+	if err != nil {
+		return nil, err
+	}
+
+	listedPackage := &ListedPackage{ImportPath: pkg.ImportPath, Dir: absDirName}
+	pkg, err = build.ImportDir(dirName, 0)
+	if err == nil {
+		listedPackage.Name = pkg.Name
+		listedPackage.GoFiles = pkg.GoFiles
+		listedPackage.CgoFiles = pkg.CgoFiles
+		listedPackage.TestGoFiles = pkg.TestGoFiles
+		listedPackage.XTestGoFiles = pkg.XTestGoFiles
+	} else {
+		listedPackage.Error = &ListedPackageError{Err: err.Error()}
+	}
+
+	return listedPackage, nil
+}
+
+// findModule returns the module of the workspace of dirName that dirName belongs to
+func findModule(dirName string) (*ListedModule, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", "list", "-m", "-json")
+	cmd.Dir = dirName
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("i18n4go: go list -m failed in %s: %s", dirName, strings.TrimSpace(stderr.String()))
+	}
+
+	absDirName, err := filepath.Abs(dirName)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *ListedModule
+	decoder := json.NewDecoder(&stdout)
+	for {
+		var module ListedModule
+		err = decoder.Decode(&module)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if moduleImportPath(&module, absDirName) != "" && (found == nil || len(module.Dir) > len(found.Dir)) {
+			found = &module
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("i18n4go: no module found for dir: %s", dirName)
+	}
+	return found, nil
+}
+
+// moduleImportPath is the import path of the package in dirName of module, empty when dirName is not in module
+func moduleImportPath(module *ListedModule, dirName string) string {
+	if module.Dir == "" {
+		return ""
+	}
+
+	relativeDirName, err := filepath.Rel(module.Dir, dirName)
+	if err != nil || relativeDirName == ".." || strings.HasPrefix(relativeDirName, ".."+string(filepath.Separator)) {
+		return ""
+	}
+
+	if relativeDirName == "." {
+		return module.Path
+	}
+	return module.Path + "/" + filepath.ToSlash(relativeDirName)
+}
+
+// RelativeImportPath is the import path of pkg relative to the import path of rootPkg, it is false with the import path
+// of pkg when pkg is neither rootPkg nor one of the packages below it
+func RelativeImportPath(rootPkg, pkg *ListedPackage) (string, bool) {
+	if pkg.ImportPath == rootPkg.ImportPath {
+		return "", true
+	}
+
+	importPath := strings.TrimPrefix(pkg.ImportPath, rootPkg.ImportPath+"/")
+	if importPath == pkg.ImportPath {
+		return pkg.ImportPath, false
+	}
+
+	return importPath, true
+}
+
+type TypedPackage struct {
+	Name  string
+	Dir   string
+	Files map[string]*ast.File
+	Types *types.Package
+	Info  *types.Info
+
+	TypeErrors []error
+}
+
+type PackageLoader struct {
+	Fset *token.FileSet
+	Tags string
+
+	// the build context of the loader, a copy of build.Default with the build tags of the loader
+	context  *build.Context
+	importer types.Importer
+	packages map[string]*TypedPackage
+}
+
+func NewPackageLoader(fset *token.FileSet, tags string) *PackageLoader {
+	context := build.Default
+	context.BuildTags = ParseStringList(tags, ",")
+
+	return &PackageLoader{
+		Fset:     fset,
+		Tags:     tags,
+		context:  &context,
+		importer: newSourceImporter(&context, fset),
+		packages: make(map[string]*TypedPackage),
+	}
+}
+
+// LoadDir parses and type checks the non test package found in dirName,
+// packages are cached by directory so every file of a package shares the same type information
+func (pl *PackageLoader) LoadDir(dirName string) (*TypedPackage, error) {
+	absDirName, err := filepath.Abs(dirName)
+	if err != nil {
+		return nil, err
+	}
+
+	if typedPackage, ok := pl.packages[absDirName]; ok {
+		return typedPackage, nil
+	}
+
+	typedPackage, err := pl.load(absDirName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pl.packages[absDirName] = typedPackage
+	return typedPackage, nil
+}
+
+// LoadDirOverlay parses and type checks the package found in dirName like LoadDir, with the content of the files of
+// overlay, by file name, in place of their content on disk, the files of overlay missing from dirName are added to the
+// package. The packages loaded with an overlay are not cached
+func (pl *PackageLoader) LoadDirOverlay(dirName string, overlay map[string][]byte) (*TypedPackage, error) {
+	absDirName, err := filepath.Abs(dirName)
+	if err != nil {
+		return nil, err
+	}
+
+	return pl.load(absDirName, overlay)
+}
+
+func (pl *PackageLoader) load(absDirName string, overlay map[string][]byte) (*TypedPackage, error) {
+	var fileNames map[string]bool
+	listedPackage, err := FindPackage(absDirName, pl.Tags)
+	if err == nil && listedPackage.Error == nil {
+		fileNames = make(map[string]bool)
+		for _, fileName := range listedPackage.SourceFiles(false) {
+			fileNames[filepath.Base(fileName)] = true
+		}
+	}
+
+	filter := func(fileName string) bool {
+		if fileNames != nil {
+			return fileNames[fileName]
+		}
+
+		if strings.HasSuffix(fileName, "_test.go") {
+			return false
+		}
+		match, err := pl.context.MatchFile(absDirName, fileName)
+		return err == nil && match
+	}
+
+	fileInfos, err := ioutil.ReadDir(absDirName)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string][]byte)
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() && strings.HasSuffix(fileInfo.Name(), ".go") && filter(fileInfo.Name()) {
+			sources[fileInfo.Name()] = nil
+		}
+	}
+	for fileName, src := range overlay {
+		if _, ok := sources[fileName]; ok || !fileExists(filepath.Join(absDirName, fileName)) {
+			sources[fileName] = src
+		}
+	}
+
+	files := make(map[string]map[string]*ast.File)
+	for fileName, src := range sources {
+		var source interface{}
+		if src != nil {
+			source = src
+		}
+
+		filePath := filepath.Join(absDirName, fileName)
+		astFile, err := parser.ParseFile(pl.Fset, filePath, source, parser.ParseComments|parser.AllErrors)
+		if err != nil {
+			return nil, err
+		}
+
+		if files[astFile.Name.Name] == nil {
+			files[astFile.Name.Name] = make(map[string]*ast.File)
+		}
+		files[astFile.Name.Name][filePath] = astFile
+	}
+
+	packageName := ""
+	for name, packageFiles := range files {
+		if packageName == "" || len(packageFiles) > len(files[packageName]) {
+			packageName = name
+		}
+	}
+	if packageName == "" {
+		return nil, fmt.Errorf("i18n4go: no Go package found in dir: %s", absDirName)
+	}
+
+	return pl.check(absDirName, packageName, files[packageName])
+}
+
+func fileExists(fileName string) bool {
+	_, err := os.Stat(fileName)
+	return err == nil
+}
+
+func (pl *PackageLoader) check(dirName, packageName string, files map[string]*ast.File) (*TypedPackage, error) {
+	typedPackage := &TypedPackage{
+		Name:  packageName,
+		Dir:   dirName,
+		Files: files,
+		Info: &types.Info{
+			Types:      make(map[ast.Expr]types.TypeAndValue),
+			Defs:       make(map[*ast.Ident]types.Object),
+			Uses:       make(map[*ast.Ident]types.Object),
+			Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		},
+	}
+
+	var fileNames []string
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	var astFiles []*ast.File
+	for _, fileName := range fileNames {
+		astFiles = append(astFiles, files[fileName])
+	}
+
+	config := types.Config{
+		Importer: pl.importer,
+		Error: func(err error) {
+			typedPackage.TypeErrors = append(typedPackage.TypeErrors, err)
+		},
+	}
+
+	// errors are collected above, a partially checked package is still useful
+	typedPackage.Types, _ = config.Check(packageName, pl.Fset, astFiles, typedPackage.Info)
+
+	return typedPackage, nil
+}
+
+// sourceImporter imports the packages from their sources like the "source" importer of go/importer, which is bound to
+// build.Default, with the build context it is given
+type sourceImporter struct {
+	context  *build.Context
+	fset     *token.FileSet
+	packages map[string]*types.Package
+}
+
+func newSourceImporter(context *build.Context, fset *token.FileSet) *sourceImporter {
+	return &sourceImporter{context: context, fset: fset, packages: make(map[string]*types.Package)}
+}
+
+func (si *sourceImporter) Import(path string) (*types.Package, error) {
+	return si.ImportFrom(path, ".", 0)
+}
+
+// ImportFrom type checks the package path imported from srcDir, the packages are cached by directory, their errors are
+// ignored but for import cycles, a partially checked package is still useful
+func (si *sourceImporter) ImportFrom(path, srcDir string, mode types.ImportMode) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+
+	buildPackage, err := si.context.Import(path, srcDir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if pkg, ok := si.packages[buildPackage.Dir]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("i18n4go: import cycle through package %s", buildPackage.ImportPath)
+		}
+		return pkg, nil
+	}
+	si.packages[buildPackage.Dir] = nil
+
+	var astFiles []*ast.File
+	for _, fileName := range append(buildPackage.GoFiles, buildPackage.CgoFiles...) {
+		astFile, err := parser.ParseFile(si.fset, filepath.Join(buildPackage.Dir, fileName), nil, 0)
+		if err != nil {
+			delete(si.packages, buildPackage.Dir)
+			return nil, err
+		}
+		astFiles = append(astFiles, astFile)
+	}
+
+	config := types.Config{
+		Importer:         si,
+		IgnoreFuncBodies: true,
+		FakeImportC:      true,
+		Error:            func(err error) {},
+	}
+	pkg, _ := config.Check(buildPackage.ImportPath, si.fset, astFiles, nil)
+	si.packages[buildPackage.Dir] = pkg
+
+	return pkg, nil
+}
@@ -0,0 +1,251 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// the verbs whose arg a template prints the same way, a verb with flags, width or precision never does
+const PLAIN_FORMAT_VERBS = "vsd"
+
+// the verbs of package fmt, a percent sign followed by another rune is no verb
+const FORMAT_VERBS = "vTtbcdoOqxXUeEfFgGspw%"
+
+// FormatVerb is a verb of a printf format string, e.g., "%-10s", "%[2]*.2f" or "%%"
+type FormatVerb struct {
+	// the bytes of the verb in the format string
+	Offset int
+	End    int
+
+	Flags string
+	// the digits of the width or "*", "" without width
+	Width string
+	// the precision with its dot, e.g., ".2", "." or ".*", "" without precision
+	Precision string
+	Verb      rune
+
+	// the index of the arg formatted by the verb, -1 for %%, and of the args of a "*" width and precision, -1 without
+	ArgIndex          int
+	WidthArgIndex     int
+	PrecisionArgIndex int
+}
+
+// Literal is true for %%, it prints a percent sign and formats no arg
+func (v FormatVerb) Literal() bool {
+	return v.Verb == '%'
+}
+
+// Plain is true when a template prints the arg of the verb as the verb does
+func (v FormatVerb) Plain() bool {
+	return v.Flags == "" && v.Width == "" && v.Precision == "" && strings.ContainsRune(PLAIN_FORMAT_VERBS, v.Verb)
+}
+
+// Format is the verb without its explicit arg indexes, e.g., "%*.2f" for "%[3]*.[2]f", it formats the args of
+// ArgIndexes in their order
+func (v FormatVerb) Format() string {
+	return "%" + v.Flags + v.Width + v.Precision + string(v.Verb)
+}
+
+// Indexed is the verb with the explicit index of each arg it formats, e.g., "%[1]*[2]d" for "%*d", two verbs
+// formatting the same args the same way are indexed alike
+func (v FormatVerb) Indexed() string {
+	if v.Literal() {
+		return "%%"
+	}
+
+	indexed := "%" + v.Flags
+	if v.WidthArgIndex != -1 {
+		indexed += "[" + strconv.Itoa(v.WidthArgIndex+1) + "]*"
+	} else {
+		indexed += v.Width
+	}
+	if v.PrecisionArgIndex != -1 {
+		indexed += ".[" + strconv.Itoa(v.PrecisionArgIndex+1) + "]*"
+	} else {
+		indexed += v.Precision
+	}
+
+	return indexed + "[" + strconv.Itoa(v.ArgIndex+1) + "]" + string(v.Verb)
+}
+
+// ArgIndexes are the indexes of the args the verb formats, the args of a "*" width and precision first
+func (v FormatVerb) ArgIndexes() []int {
+	var argIndexes []int
+	for _, argIndex := range []int{v.WidthArgIndex, v.PrecisionArgIndex, v.ArgIndex} {
+		if argIndex != -1 {
+			argIndexes = append(argIndexes, argIndex)
+		}
+	}
+
+	return argIndexes
+}
+
+// ParseFormat returns the verbs of the printf format string format, the args are numbered like fmt does, an explicit
+// index, e.g., "%[2]d", sets the arg of the verb and of the verbs that follow, a "*" width or precision takes an arg.
+// A percent sign followed by a rune that is no verb of fmt, or by a space and a word, e.g., "50% full", is text
+func ParseFormat(format string) []FormatVerb {
+	var verbs []FormatVerb
+	argNum := 0
+	for i := 0; i < len(format); {
+		if format[i] != '%' {
+			i++
+			continue
+		}
+
+		verb := FormatVerb{Offset: i, ArgIndex: -1, WidthArgIndex: -1, PrecisionArgIndex: -1}
+		textArgNum := argNum
+		i++
+
+		start := i
+		for i < len(format) && strings.IndexByte("#0+- ", format[i]) != -1 {
+			i++
+		}
+		verb.Flags = format[start:i]
+
+		i, argNum = parseArgIndex(format, i, argNum)
+		if i < len(format) && format[i] == '*' {
+			verb.Width, verb.WidthArgIndex = "*", argNum
+			argNum++
+			i++
+		} else {
+			start = i
+			for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+				i++
+			}
+			verb.Width = format[start:i]
+		}
+
+		if i < len(format) && format[i] == '.' {
+			i++
+			i, argNum = parseArgIndex(format, i, argNum)
+			if i < len(format) && format[i] == '*' {
+				verb.Precision, verb.PrecisionArgIndex = ".*", argNum
+				argNum++
+				i++
+			} else {
+				start = i
+				for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+					i++
+				}
+				verb.Precision = "." + format[start:i]
+			}
+		}
+
+		i, argNum = parseArgIndex(format, i, argNum)
+		if i == len(format) {
+			// a percent sign ending the format has no verb
+			break
+		}
+
+		r, size := utf8.DecodeRuneInString(format[i:])
+		next, _ := utf8.DecodeRuneInString(format[i+size:])
+		if !strings.ContainsRune(FORMAT_VERBS, r) || strings.Contains(verb.Flags, " ") && unicode.IsLetter(next) {
+			i, argNum = verb.Offset+1, textArgNum
+			continue
+		}
+
+		verb.Verb = r
+		i += size
+		verb.End = i
+
+		if !verb.Literal() {
+			verb.ArgIndex = argNum
+			argNum++
+		}
+		verbs = append(verbs, verb)
+	}
+
+	return verbs
+}
+
+// parseArgIndex parses the explicit arg index at i of format, e.g., "[2]", it returns the position after it and the
+// index of the next arg, unchanged without explicit index
+func parseArgIndex(format string, i, argNum int) (int, int) {
+	if i >= len(format) || format[i] != '[' {
+		return i, argNum
+	}
+
+	end := strings.IndexByte(format[i:], ']')
+	if end == -1 {
+		return i, argNum
+	}
+
+	index, err := strconv.Atoi(format[i+1 : i+end])
+	if err != nil || index < 1 {
+		return i, argNum
+	}
+
+	return i + end + 1, index - 1
+}
+
+// FormatArgCount is the number of args the verbs of a format string take
+func FormatArgCount(verbs []FormatVerb) int {
+	count := 0
+	for _, verb := range verbs {
+		for _, argIndex := range verb.ArgIndexes() {
+			if argIndex+1 > count {
+				count = argIndex + 1
+			}
+		}
+	}
+
+	return count
+}
+
+// TemplateArg is an arg of the templated string converted from a printf format string
+type TemplateArg struct {
+	Name string
+	// the format keeping the formatting of the verb, e.g., "%5d", empty when the template prints the arg as is
+	Format string
+	// the indexes of the args of the format string that the value of the arg is made of
+	ArgIndexes []int
+}
+
+// ConvertFormatToTemplate converts the printf format string format into a templated string, each verb becomes an arg
+// named after the index of the arg it formats, e.g., "{{.Arg1}}" for "%[2]s", an arg formatted several ways gets a name
+// for each. A verb with flags, width or precision keeps its formatting in the Format of its arg. The %% are kept since
+// the templated string is still the format of the call it is passed to
+func ConvertFormatToTemplate(format string) (string, []TemplateArg) {
+	var templated strings.Builder
+	var templateArgs []TemplateArg
+	names := make(map[string]string)
+	taken := make(map[string]bool)
+
+	last := 0
+	for _, verb := range ParseFormat(format) {
+		if verb.Literal() {
+			continue
+		}
+
+		templateArg := TemplateArg{ArgIndexes: verb.ArgIndexes()}
+		if !verb.Plain() {
+			templateArg.Format = verb.Format()
+		}
+
+		key := templateArg.Format
+		for _, argIndex := range templateArg.ArgIndexes {
+			key += "," + strconv.Itoa(argIndex)
+		}
+
+		name, ok := names[key]
+		if !ok {
+			name = "Arg" + strconv.Itoa(verb.ArgIndex)
+			for i := 1; taken[name]; i++ {
+				name = "Arg" + strconv.Itoa(verb.ArgIndex) + "_" + strconv.Itoa(i)
+			}
+			names[key], taken[name] = name, true
+
+			templateArg.Name = name
+			templateArgs = append(templateArgs, templateArg)
+		}
+
+		templated.WriteString(format[last:verb.Offset])
+		templated.WriteString("{{." + name + "}}")
+		last = verb.End
+	}
+	templated.WriteString(format[last:])
+
+	return templated.String(), templateArgs
+}
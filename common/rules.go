@@ -0,0 +1,619 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// the rules files found in the directories of the extracted files, at most one per directory
+var RULES_FILENAMES = []string{".i18n4go.yml", ".i18n4go.yaml", ".i18n4go.json"}
+
+// RulesFile is the content of a rules file, YAML or JSON, it holds the exclusions of excluded.json, the capturing groups
+// of capturing_group.json, the enforced funcs and fields, the sinks, the detectors that exclude strings and the selectors of the
+// asset files, the files listed in extends are applied before it
+type RulesFile struct {
+	Extends []string `json:"extends,omitempty" yaml:"extends"`
+	Root    bool     `json:"root,omitempty" yaml:"root"`
+
+	ExcludedStrings        []string `json:"excludedStrings,omitempty" yaml:"excludedStrings"`
+	ExcludedLines          []string `json:"excludedLines,omitempty" yaml:"excludedLines"`
+	ExcludedRegexps        []string `json:"excludedRegexps,omitempty" yaml:"excludedRegexps"`
+	ExcludedFileRegexps    []string `json:"excludedFileRegexps,omitempty" yaml:"excludedFileRegexps"`
+	EnforcedFuncs          []string `json:"enforcedFuncs,omitempty" yaml:"enforcedFuncs"`
+	EnforcedFields         []string `json:"enforcedFields,omitempty" yaml:"enforcedFields"`
+	Sinks                  []string `json:"sinks,omitempty" yaml:"sinks"`
+	CaptureGroupSubstrings []string `json:"captureGroupSubstrings,omitempty" yaml:"captureGroupSubstrings"`
+	Detectors              []string `json:"detectors,omitempty" yaml:"detectors"`
+
+	Assets []AssetRule `json:"assets,omitempty" yaml:"assets"`
+}
+
+// Rules are the validated rules that apply to the files of a directory
+type Rules struct {
+	ExcludedStrings        map[string]bool
+	ExcludedLines          []string
+	ExcludedRegexps        []*regexp.Regexp
+	ExcludedFileRegexps    []*regexp.Regexp
+	EnforcedFuncs          []string
+	EnforcedCalls          []EnforcedCall
+	EnforcedFields         []EnforcedField
+	Sinks                  []string
+	CaptureGroupSubstrings []*regexp.Regexp
+	// the built-in detectors that exclude the strings they recognize
+	Detectors []string
+	// the selectors of the JSON, YAML, text and Markdown files that are extracted, the last one matching a file applies
+	Assets []AssetSelector
+
+	// the rules files the rules come from, in the order they are applied
+	Filenames []string
+	// changes whenever a rule changes
+	Digest string
+
+	spec RulesFile
+}
+
+// RuleError is an invalid rule, Line and Column are 0 when the position of the rule is not known
+type RuleError struct {
+	Filename string
+	Line     int
+	Column   int
+	Message  string
+}
+
+// RuleErrors are all the invalid rules found while loading rules files
+type RuleErrors []*RuleError
+
+// RulesLoader loads rules files once and resolves the rules of directories, it is safe for concurrent use
+type RulesLoader struct {
+	mutex sync.Mutex
+	base  RulesFile
+	// the base rules files, in the order they are applied
+	baseFilenames []string
+	files         map[string]*RulesFile
+	dirs          map[string]*Rules
+
+	// the rules the legacy files do not know, they are ignored
+	Warnings RuleErrors
+}
+
+func NewRulesLoader() *RulesLoader {
+	return &RulesLoader{
+		files: make(map[string]*RulesFile),
+		dirs:  make(map[string]*Rules),
+	}
+}
+
+func (e *RuleError) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", e.Filename, e.Line, e.Column, e.Message)
+	case e.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", e.Filename, e.Line, e.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", e.Filename, e.Message)
+}
+
+func (e RuleErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, ruleError := range e {
+		messages[i] = ruleError.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// AddBase applies a rules file, e.g., excluded.json, to every directory before the rules files found in the directories,
+// it must be called before Rules
+func (rl *RulesLoader) AddBase(filename string) error {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	return rl.addBase(filename, true)
+}
+
+// AddLegacyBase applies a legacy rules file, i.e., excluded.json or capturing_group.json, like AddBase, the keys it does not
+// know are Warnings rather than errors since the legacy files were never validated, their other rules are still validated
+func (rl *RulesLoader) AddLegacyBase(filename string) error {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	return rl.addBase(filename, false)
+}
+
+func (rl *RulesLoader) addBase(filename string, strict bool) error {
+	rulesFile, err := rl.loadFile(filename, nil, strict)
+	if err != nil {
+		return err
+	}
+
+	rl.base.add(rulesFile)
+	rl.baseFilenames = append(rl.baseFilenames, filename)
+	return nil
+}
+
+// Rules returns the rules of the files in dirName: the base rules followed by the rules files of dirName and of its parents,
+// outermost first, up to the first one with root: true
+func (rl *RulesLoader) Rules(dirName string) (*Rules, error) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	return rl.rules(dirName)
+}
+
+func (rl *RulesLoader) rules(dirName string) (*Rules, error) {
+	absDirName, err := filepath.Abs(dirName)
+	if err != nil {
+		return nil, err
+	}
+	if rules, ok := rl.dirs[absDirName]; ok {
+		return rules, nil
+	}
+
+	rulesFilename, err := findRulesFile(absDirName)
+	if err != nil {
+		return nil, err
+	}
+
+	var rulesFile *RulesFile
+	if rulesFilename != "" {
+		rulesFile, err = rl.loadFile(rulesFilename, nil, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var rules *Rules
+	parentDirName := filepath.Dir(absDirName)
+	if parentDirName == absDirName || (rulesFile != nil && rulesFile.Root) {
+		rules = newRules(rl.base, rl.baseFilenames)
+	} else {
+		rules, err = rl.rules(parentDirName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rulesFile != nil {
+		spec := RulesFile{}
+		spec.add(&rules.spec)
+		spec.add(rulesFile)
+		rules = newRules(spec, append(append([]string{}, rules.Filenames...), rulesFilename))
+	}
+
+	rl.dirs[absDirName] = rules
+	return rules, nil
+}
+
+// findRulesFile returns the rules file of a directory or "" when it has none
+func findRulesFile(dirName string) (string, error) {
+	var found []string
+	for _, name := range RULES_FILENAMES {
+		filename := filepath.Join(dirName, name)
+		if fileInfo, err := os.Stat(filename); err == nil && !fileInfo.IsDir() {
+			found = append(found, filename)
+		}
+	}
+
+	if len(found) > 1 {
+		return "", RuleErrors{{Filename: found[1], Message: fmt.Sprintf("only one rules file is allowed per directory, found %s too", filepath.Base(found[0]))}}
+	}
+	if len(found) == 0 {
+		return "", nil
+	}
+
+	return found[0], nil
+}
+
+// loadFile reads, validates and resolves the extends of a rules file, including lists the files that extend it, the
+// unknown keys of a file that is not strict are warnings, those of the files it extends are errors
+func (rl *RulesLoader) loadFile(filename string, including []string, strict bool) (*RulesFile, error) {
+	absFilename, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	if rulesFile, ok := rl.files[absFilename]; ok {
+		return rulesFile, nil
+	}
+
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	rulesFile, errs := parseRulesFile(filename, content)
+	if rulesFile == nil {
+		return nil, errs
+	}
+	if !strict {
+		rl.Warnings = append(rl.Warnings, errs...)
+		errs = nil
+	}
+	errs = append(errs, validateRulesFile(filename, content, rulesFile)...)
+
+	resolved := &RulesFile{Root: rulesFile.Root}
+	including = append(including, absFilename)
+	for _, extended := range rulesFile.Extends {
+		extendedFilename := extended
+		if !filepath.IsAbs(extendedFilename) {
+			extendedFilename = filepath.Join(filepath.Dir(filename), extended)
+		}
+
+		line, column := locate(content, "extends", extended)
+		absExtendedFilename, _ := filepath.Abs(extendedFilename)
+		if containsString(including, absExtendedFilename) {
+			errs = append(errs, &RuleError{filename, line, column, fmt.Sprintf("extends: %s is part of a cycle", extended)})
+			continue
+		}
+		if _, err := os.Stat(extendedFilename); err != nil {
+			errs = append(errs, &RuleError{filename, line, column, fmt.Sprintf("extends: could not find %s", extended)})
+			continue
+		}
+
+		extendedFile, err := rl.loadFile(extendedFilename, including, true)
+		if err != nil {
+			errs = appendRuleErrors(errs, filename, err)
+			continue
+		}
+		resolved.add(extendedFile)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	resolved.add(rulesFile)
+	rl.files[absFilename] = resolved
+	return resolved, nil
+}
+
+// add appends the rules of other, extends and root are not inherited
+func (rf *RulesFile) add(other *RulesFile) {
+	rf.ExcludedStrings = append(rf.ExcludedStrings, other.ExcludedStrings...)
+	rf.ExcludedLines = append(rf.ExcludedLines, other.ExcludedLines...)
+	rf.ExcludedRegexps = append(rf.ExcludedRegexps, other.ExcludedRegexps...)
+	rf.ExcludedFileRegexps = append(rf.ExcludedFileRegexps, other.ExcludedFileRegexps...)
+	rf.EnforcedFuncs = append(rf.EnforcedFuncs, other.EnforcedFuncs...)
+	rf.EnforcedFields = append(rf.EnforcedFields, other.EnforcedFields...)
+	rf.Sinks = append(rf.Sinks, other.Sinks...)
+	rf.CaptureGroupSubstrings = append(rf.CaptureGroupSubstrings, other.CaptureGroupSubstrings...)
+	rf.Detectors = append(rf.Detectors, other.Detectors...)
+	rf.Assets = append(rf.Assets, other.Assets...)
+}
+
+// newRules compiles validated rules
+func newRules(spec RulesFile, filenames []string) *Rules {
+	rules := &Rules{
+		ExcludedStrings:        make(map[string]bool),
+		ExcludedLines:          spec.ExcludedLines,
+		ExcludedRegexps:        mustCompileRegexps(spec.ExcludedRegexps),
+		ExcludedFileRegexps:    mustCompileRegexps(spec.ExcludedFileRegexps),
+		EnforcedFuncs:          spec.EnforcedFuncs,
+		Sinks:                  spec.Sinks,
+		CaptureGroupSubstrings: mustCompileRegexps(spec.CaptureGroupSubstrings),
+		Detectors:              spec.Detectors,
+		Filenames:              filenames,
+		spec:                   spec,
+	}
+
+	for _, excludedString := range spec.ExcludedStrings {
+		rules.ExcludedStrings[excludedString] = true
+	}
+
+	for _, enforcedFunc := range spec.EnforcedFuncs {
+		enforcedCall, _ := ParseEnforcedCall(enforcedFunc)
+		rules.EnforcedCalls = append(rules.EnforcedCalls, enforcedCall)
+	}
+
+	for _, enforcedFieldString := range spec.EnforcedFields {
+		enforcedField, _ := ParseEnforcedField(enforcedFieldString)
+		rules.EnforcedFields = append(rules.EnforcedFields, enforcedField)
+	}
+
+	for _, assetRule := range spec.Assets {
+		rules.Assets = append(rules.Assets, NewAssetSelector(assetRule))
+	}
+
+	content, _ := json.Marshal(spec)
+	rules.Digest = Digest(content)
+
+	return rules
+}
+
+func mustCompileRegexps(regexpStrings []string) []*regexp.Regexp {
+	var compiledRegexps []*regexp.Regexp
+	for _, regexpString := range regexpStrings {
+		compiledRegexps = append(compiledRegexps, regexp.MustCompile(regexpString))
+	}
+
+	return compiledRegexps
+}
+
+// Detection returns the detection of the first enabled detector that recognizes s for sure enough, nil when there is none
+func (r *Rules) Detection(s string) *Detection {
+	for _, detection := range Detect(s) {
+		if detection.Score < DETECTOR_MIN_SCORE {
+			break
+		}
+
+		for _, detector := range r.Detectors {
+			if detection.Detector == detector {
+				return &detection
+			}
+		}
+	}
+
+	return nil
+}
+
+// Asset returns the selector of the last assets rule whose files match fileName, nil when there is none
+func (r *Rules) Asset(fileName string) *AssetSelector {
+	for i := len(r.Assets) - 1; i >= 0; i-- {
+		if MatchesFilesPattern(r.Assets[i].Rule.Files, fileName) {
+			return &r.Assets[i]
+		}
+	}
+
+	return nil
+}
+
+// ExcludesFile is true when one of the excludedFileRegexps matches fileName
+func (r *Rules) ExcludesFile(fileName string) bool {
+	for _, compiledRegexp := range r.ExcludedFileRegexps {
+		if compiledRegexp.MatchString(fileName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseRulesFile decodes a .json file as JSON and any other file as YAML, keys it does not know are errors
+// but the file is still returned so that its other rules are validated too
+func parseRulesFile(filename string, content []byte) (*RulesFile, RuleErrors) {
+	var keys []string
+	rulesFile := &RulesFile{}
+
+	if strings.HasSuffix(filename, ".json") {
+		var values map[string]json.RawMessage
+		err := json.Unmarshal(content, &values)
+		if err == nil {
+			err = json.Unmarshal(content, rulesFile)
+		}
+		if err != nil {
+			return nil, RuleErrors{jsonRuleError(filename, content, err)}
+		}
+
+		for key := range values {
+			keys = append(keys, key)
+		}
+	} else {
+		var values yaml.MapSlice
+		err := yaml.Unmarshal(content, &values)
+		if err == nil {
+			err = yaml.Unmarshal(content, rulesFile)
+		}
+		if err != nil {
+			return nil, yamlRuleErrors(filename, err)
+		}
+
+		for _, item := range values {
+			keys = append(keys, fmt.Sprint(item.Key))
+		}
+	}
+
+	knownKeys := rulesFileKeys()
+	sort.Strings(keys)
+	var errs RuleErrors
+	for _, key := range keys {
+		if !knownKeys[key] {
+			line, column := locate(content, key, "")
+			errs = append(errs, &RuleError{filename, line, column, fmt.Sprintf("unknown rule: %s", key)})
+		}
+	}
+
+	return rulesFile, errs
+}
+
+func rulesFileKeys() map[string]bool {
+	keys := make(map[string]bool)
+	rulesFileType := reflect.TypeOf(RulesFile{})
+	for i := 0; i < rulesFileType.NumField(); i++ {
+		keys[rulesFileType.Field(i).Tag.Get("yaml")] = true
+	}
+
+	return keys
+}
+
+func jsonRuleError(filename string, content []byte, err error) *RuleError {
+	switch x := err.(type) {
+	case *json.SyntaxError:
+		line, column := offsetPosition(content, int(x.Offset))
+		return &RuleError{filename, line, column, x.Error()}
+	case *json.UnmarshalTypeError:
+		line, column := offsetPosition(content, int(x.Offset))
+		return &RuleError{filename, line, column, fmt.Sprintf("%s: expected %s, found %s", x.Field, x.Type, x.Value)}
+	}
+
+	return &RuleError{Filename: filename, Message: err.Error()}
+}
+
+var yamlLineRegexp = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+func yamlRuleErrors(filename string, err error) RuleErrors {
+	messages := []string{err.Error()}
+	if typeError, ok := err.(*yaml.TypeError); ok {
+		messages = typeError.Errors
+	}
+
+	var errs RuleErrors
+	for _, message := range messages {
+		ruleError := &RuleError{Filename: filename, Message: message}
+		if submatches := yamlLineRegexp.FindStringSubmatch(message); submatches != nil {
+			ruleError.Line, _ = strconv.Atoi(submatches[1])
+			ruleError.Message = submatches[2]
+		}
+		errs = append(errs, ruleError)
+	}
+
+	return errs
+}
+
+// validateRulesFile checks every rule of a file, not just the first invalid one
+func validateRulesFile(filename string, content []byte, rulesFile *RulesFile) RuleErrors {
+	var errs RuleErrors
+	invalid := func(key, value, format string, a ...interface{}) {
+		line, column := locate(content, key, value)
+		errs = append(errs, &RuleError{filename, line, column, key + ": " + fmt.Sprintf(format, a...)})
+	}
+
+	for _, excludedLine := range rulesFile.ExcludedLines {
+		if excludedLine == "" {
+			invalid("excludedLines", excludedLine, "an empty line would exclude every line")
+		}
+	}
+
+	regexpLists := []struct {
+		key     string
+		regexps []string
+	}{
+		{"excludedRegexps", rulesFile.ExcludedRegexps},
+		{"excludedFileRegexps", rulesFile.ExcludedFileRegexps},
+		{"captureGroupSubstrings", rulesFile.CaptureGroupSubstrings},
+	}
+	for _, regexpList := range regexpLists {
+		for _, regexpString := range regexpList.regexps {
+			compiledRegexp, err := regexp.Compile(regexpString)
+			if err != nil {
+				invalid(regexpList.key, regexpString, "invalid regexp %q: %s", regexpString, strings.TrimPrefix(err.Error(), "error parsing regexp: "))
+			} else if regexpList.key == "captureGroupSubstrings" && compiledRegexp.NumSubexp() == 0 {
+				invalid(regexpList.key, regexpString, "regexp %q has no capturing group", regexpString)
+			}
+		}
+	}
+
+	for _, enforcedFunc := range rulesFile.EnforcedFuncs {
+		if _, err := ParseEnforcedCall(enforcedFunc); err != nil {
+			invalid("enforcedFuncs", enforcedFunc, "%q is not a function name, expected Func, package.Func or package.Type.Method optionally followed by :positions", enforcedFunc)
+		}
+	}
+
+	for _, enforcedField := range rulesFile.EnforcedFields {
+		if _, err := ParseEnforcedField(enforcedField); err != nil {
+			invalid("enforcedFields", enforcedField, "invalid enforced field %q, expected package.Type{Field,...}", enforcedField)
+		}
+	}
+
+	for _, sink := range rulesFile.Sinks {
+		if _, err := ParseSink(sink); err != nil {
+			invalid("sinks", sink, "invalid sink %q, expected package.Func or package.Type.Method", sink)
+		}
+	}
+
+	for _, detector := range rulesFile.Detectors {
+		if !IsDetector(detector) {
+			invalid("detectors", detector, "unknown detector %q", detector)
+		}
+	}
+
+	for _, assetRule := range rulesFile.Assets {
+		if assetRule.Files == "" {
+			invalid("assets", "", "an asset needs files, a file name pattern such as help/*.md")
+		} else if _, err := path.Match(assetRule.Files, ""); err != nil {
+			invalid("assets", assetRule.Files, "invalid files pattern %q", assetRule.Files)
+		}
+
+		if assetRule.Mode != "" && assetRule.Mode != FILE_ASSET_MODE && assetRule.Mode != PARAGRAPH_ASSET_MODE {
+			invalid("assets", assetRule.Mode, "unknown asset mode %q, expected %s or %s", assetRule.Mode, FILE_ASSET_MODE, PARAGRAPH_ASSET_MODE)
+		}
+		if assetRule.Mode != "" && len(assetRule.Paths) > 0 {
+			invalid("assets", assetRule.Files, "an asset has either paths, for JSON and YAML files, or a mode, for text files")
+		}
+
+		for _, assetPath := range assetRule.Paths {
+			if _, err := ParseAssetPath(assetPath); err != nil {
+				invalid("assets", assetPath, "invalid path %q: %s", assetPath, err)
+			}
+		}
+	}
+
+	return errs
+}
+
+// appendRuleErrors adds the errors of an extended file, an error already in errs is not added twice
+func appendRuleErrors(errs RuleErrors, filename string, err error) RuleErrors {
+	ruleErrors, ok := err.(RuleErrors)
+	if !ok {
+		return append(errs, &RuleError{Filename: filename, Message: err.Error()})
+	}
+
+	for _, ruleError := range ruleErrors {
+		duplicate := false
+		for _, existing := range errs {
+			duplicate = duplicate || existing.Error() == ruleError.Error()
+		}
+		if !duplicate {
+			errs = append(errs, ruleError)
+		}
+	}
+
+	return errs
+}
+
+// locate returns the position of value in the list of key, or of key when value is "" or cannot be found,
+// the content is searched as text since neither decoder keeps the positions of values
+func locate(content []byte, key string, value string) (int, int) {
+	keyRegexp := regexp.MustCompile(`["']?` + regexp.QuoteMeta(key) + `["']?\s*:`)
+	keyLoc := keyRegexp.FindIndex(content)
+	if keyLoc == nil {
+		return 0, 0
+	}
+
+	if value != "" {
+		var quoted bytes.Buffer
+		encoder := json.NewEncoder(&quoted)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(value)
+
+		for _, candidate := range [][]byte{bytes.TrimSpace(quoted.Bytes()), []byte(value)} {
+			if i := bytes.Index(content[keyLoc[1]:], candidate); i >= 0 {
+				return offsetPosition(content, keyLoc[1]+i)
+			}
+		}
+	}
+
+	return offsetPosition(content, keyLoc[0])
+}
+
+// offsetPosition returns the line and column of a byte offset, both start at 1
+func offsetPosition(content []byte, offset int) (int, int) {
+	if offset > len(content) {
+		offset = len(content)
+	}
+
+	before := content[:offset]
+	return bytes.Count(before, []byte("\n")) + 1, offset - bytes.LastIndexByte(before, '\n')
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
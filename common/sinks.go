@@ -0,0 +1,329 @@
+package common
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+var DEFAULT_SINKS = []string{
+	"fmt.Print*",
+	"fmt.Fprint*",
+	"fmt.Errorf",
+	"errors.New",
+	"io.Writer.Write",
+}
+
+// functions whose result carries the text of their string arguments
+var PASS_THROUGH_FUNCS = []string{
+	"fmt.Sprint*",
+}
+
+type Sink struct {
+	Pattern string
+
+	PkgPath string
+	Recv    string
+	Name    string
+}
+
+// ParseSink parses a sink such as "fmt.Print*", "io.Writer.Write" or "github.com/org/ui.Say",
+// the function and receiver names can use path.Match patterns
+func ParseSink(pattern string) (Sink, error) {
+	lastSlash := strings.LastIndex(pattern, "/")
+	pieces := strings.Split(pattern[lastSlash+1:], ".")
+	prefix := pattern[:lastSlash+1]
+
+	switch len(pieces) {
+	case 2:
+		return Sink{Pattern: pattern, PkgPath: prefix + pieces[0], Name: pieces[1]}, nil
+	case 3:
+		return Sink{Pattern: pattern, PkgPath: prefix + pieces[0], Recv: pieces[1], Name: pieces[2]}, nil
+	}
+
+	return Sink{}, fmt.Errorf("i18n4go: invalid sink: %s, expected package.Func or package.Type.Method", pattern)
+}
+
+func ParseSinks(patterns []string) ([]Sink, error) {
+	var sinks []Sink
+	for _, pattern := range patterns {
+		sink, err := ParseSink(pattern)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return sinks, nil
+}
+
+func (s Sink) matches(fn *types.Func, pkg *types.Package) bool {
+	if fn.Pkg() == nil {
+		return false
+	}
+
+	if ok, _ := path.Match(s.Name, fn.Name()); !ok {
+		return false
+	}
+
+	recv := fn.Type().(*types.Signature).Recv()
+	if s.Recv == "" {
+		return recv == nil && fn.Pkg().Path() == s.PkgPath
+	}
+
+	if recv == nil {
+		return false
+	}
+
+	recvType := recv.Type()
+	if pointer, ok := recvType.(*types.Pointer); ok {
+		recvType = pointer.Elem()
+	}
+
+	if named, ok := recvType.(*types.Named); ok && named.Obj().Pkg() != nil {
+		if ok, _ := path.Match(s.Recv, named.Obj().Name()); ok && named.Obj().Pkg().Path() == s.PkgPath {
+			return true
+		}
+	}
+
+	iface := lookupInterface(pkg, s.PkgPath, s.Recv)
+	if iface == nil {
+		return false
+	}
+
+	return types.Implements(recvType, iface) || types.Implements(types.NewPointer(recvType), iface)
+}
+
+func lookupInterface(pkg *types.Package, pkgPath, name string) *types.Interface {
+	if pkg == nil {
+		return nil
+	}
+
+	visited := make(map[*types.Package]bool)
+	queue := []*types.Package{pkg}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		if current.Path() == pkgPath {
+			if typeName, ok := current.Scope().Lookup(name).(*types.TypeName); ok {
+				if iface, ok := typeName.Type().Underlying().(*types.Interface); ok {
+					return iface
+				}
+			}
+			return nil
+		}
+
+		queue = append(queue, current.Imports()...)
+	}
+
+	return nil
+}
+
+// SinkFinder follows string expressions of a type checked package to the call that uses them
+type SinkFinder struct {
+	pkg   *TypedPackage
+	sinks []Sink
+
+	passThroughs []Sink
+	parents      map[ast.Node]ast.Node
+	uses         map[types.Object][]*ast.Ident
+}
+
+func NewSinkFinder(pkg *TypedPackage, sinks []Sink) *SinkFinder {
+	passThroughs, _ := ParseSinks(PASS_THROUGH_FUNCS)
+
+	sf := &SinkFinder{
+		pkg:          pkg,
+		sinks:        sinks,
+		passThroughs: passThroughs,
+		parents:      make(map[ast.Node]ast.Node),
+		uses:         make(map[types.Object][]*ast.Ident),
+	}
+
+	for _, astFile := range pkg.Files {
+		var stack []ast.Node
+		ast.Inspect(astFile, func(node ast.Node) bool {
+			if node == nil {
+				stack = stack[:len(stack)-1]
+				return true
+			}
+
+			if len(stack) > 0 {
+				sf.parents[node] = stack[len(stack)-1]
+			}
+			stack = append(stack, node)
+			return true
+		})
+	}
+
+	for ident, obj := range pkg.Info.Uses {
+		sf.uses[obj] = append(sf.uses[obj], ident)
+	}
+	for obj := range sf.uses {
+		idents := sf.uses[obj]
+		sort.Slice(idents, func(i, j int) bool { return idents[i].Pos() < idents[j].Pos() })
+	}
+
+	return sf
+}
+
+// FindSink returns the pattern of the sink the expression flows into, or "" if it never reaches one
+func (sf *SinkFinder) FindSink(expr ast.Expr) string {
+	return sf.follow(expr, make(map[ast.Node]bool))
+}
+
+func (sf *SinkFinder) follow(node ast.Node, visited map[ast.Node]bool) string {
+	if visited[node] {
+		return ""
+	}
+	visited[node] = true
+
+	switch parent := sf.parents[node].(type) {
+	case *ast.ParenExpr:
+		return sf.follow(parent, visited)
+	case *ast.BinaryExpr:
+		if parent.Op == token.ADD {
+			return sf.follow(parent, visited)
+		}
+	case *ast.CallExpr:
+		if parent.Fun == node {
+			return ""
+		}
+
+		if sf.isConversion(parent) || sf.matchCall(parent, sf.passThroughs) != "" {
+			return sf.follow(parent, visited)
+		}
+
+		return sf.matchCall(parent, sf.sinks)
+	case *ast.AssignStmt:
+		if len(parent.Lhs) != len(parent.Rhs) {
+			return ""
+		}
+
+		for i, rhs := range parent.Rhs {
+			if rhs == node {
+				return sf.followVariable(parent.Lhs[i], visited)
+			}
+		}
+	case *ast.ValueSpec:
+		for i, value := range parent.Values {
+			if value == node && i < len(parent.Names) {
+				return sf.followVariable(parent.Names[i], visited)
+			}
+		}
+	case *ast.ReturnStmt:
+		return sf.followReturn(parent, visited)
+	}
+
+	return ""
+}
+
+func (sf *SinkFinder) followVariable(expr ast.Expr, visited map[ast.Node]bool) string {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+
+	obj := sf.pkg.Info.Defs[ident]
+	if obj == nil {
+		obj = sf.pkg.Info.Uses[ident]
+	}
+
+	switch x := obj.(type) {
+	case *types.Const:
+	case *types.Var:
+		if x.IsField() {
+			return ""
+		}
+	default:
+		return ""
+	}
+
+	for _, use := range sf.uses[obj] {
+		if sink := sf.follow(use, visited); sink != "" {
+			return sink
+		}
+	}
+
+	return ""
+}
+
+func (sf *SinkFinder) followReturn(returnStmt *ast.ReturnStmt, visited map[ast.Node]bool) string {
+	var node ast.Node = returnStmt
+	for node != nil {
+		switch x := node.(type) {
+		case *ast.FuncLit:
+			return ""
+		case *ast.FuncDecl:
+			obj := sf.pkg.Info.Defs[x.Name]
+			if obj == nil {
+				return ""
+			}
+
+			for _, use := range sf.uses[obj] {
+				var callee ast.Node = use
+				if selector, ok := sf.parents[use].(*ast.SelectorExpr); ok && selector.Sel == use {
+					callee = selector
+				}
+
+				if call, ok := sf.parents[callee].(*ast.CallExpr); ok && call.Fun == callee {
+					if sink := sf.follow(call, visited); sink != "" {
+						return sink
+					}
+				}
+			}
+
+			return ""
+		}
+		node = sf.parents[node]
+	}
+
+	return ""
+}
+
+func (sf *SinkFinder) isConversion(call *ast.CallExpr) bool {
+	typeAndValue, ok := sf.pkg.Info.Types[call.Fun]
+	return ok && typeAndValue.IsType()
+}
+
+func (sf *SinkFinder) matchCall(call *ast.CallExpr, sinks []Sink) string {
+	fn := sf.callee(call)
+	if fn == nil {
+		return ""
+	}
+
+	for _, sink := range sinks {
+		if sink.matches(fn, sf.pkg.Types) {
+			return sink.Pattern
+		}
+	}
+
+	return ""
+}
+
+func (sf *SinkFinder) callee(call *ast.CallExpr) *types.Func {
+	var obj types.Object
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		obj = sf.pkg.Info.Uses[fun]
+	case *ast.SelectorExpr:
+		if selection, ok := sf.pkg.Info.Selections[fun]; ok {
+			obj = selection.Obj()
+		} else {
+			obj = sf.pkg.Info.Uses[fun.Sel]
+		}
+	}
+
+	fn, _ := obj.(*types.Func)
+	return fn
+}
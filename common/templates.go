@@ -0,0 +1,196 @@
+package common
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"text/template/parse"
+)
+
+var TEMPLATE_EXTENSIONS = []string{".tmpl", ".tpl", ".gotmpl", ".html"}
+
+// the funcs whose string argument is extracted from templates, e.g., {{T "Hello"}} or {{"Hello" | T}}
+var TEMPLATE_FUNCS = []string{"T"}
+
+var htmlTagRegexp = regexp.MustCompile(`(?s)<!--.*?-->|<[^>]*>`)
+
+type TemplateString struct {
+	Value  string
+	Offset int
+}
+
+func IsTemplateFile(fileName string) bool {
+	extension := filepath.Ext(fileName)
+	for _, templateExtension := range TEMPLATE_EXTENSIONS {
+		if extension == templateExtension {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsHTMLTemplate is true for templates whose text is HTML markup
+func IsHTMLTemplate(fileName string) bool {
+	return filepath.Ext(fileName) == ".html" || strings.HasSuffix(fileName, ".html.tmpl")
+}
+
+// ParseTemplate parses text with the text/template parser, html/template uses the same syntax,
+// the funcs are not checked since they are only known when the template is executed
+func ParseTemplate(name, text string) (map[string]*parse.Tree, error) {
+	trees := make(map[string]*parse.Tree)
+
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck | parse.ParseComments
+	_, err := tree.Parse(text, "", "", trees)
+	if err != nil {
+		return nil, err
+	}
+
+	return trees, nil
+}
+
+// TemplateStrings returns the text and the arguments of funcNames in the templates sorted by their offset in the template,
+// with html the markup is removed and each piece of text between tags is a string
+func TemplateStrings(trees map[string]*parse.Tree, html bool, funcNames []string) []TemplateString {
+	tw := templateWalker{html: html, funcNames: make(map[string]bool)}
+	for _, funcName := range funcNames {
+		tw.funcNames[funcName] = true
+	}
+
+	for _, tree := range trees {
+		if tree.Root != nil {
+			tw.walk(tree.Root)
+		}
+	}
+
+	sort.Slice(tw.found, func(i, j int) bool {
+		return tw.found[i].Offset < tw.found[j].Offset
+	})
+
+	return tw.found
+}
+
+// TemplateComments returns the text of the {{/* ... */}} comments in the templates sorted by their offset in the template
+func TemplateComments(trees map[string]*parse.Tree) []TemplateString {
+	tw := templateWalker{}
+	for _, tree := range trees {
+		if tree.Root != nil {
+			tw.walk(tree.Root)
+		}
+	}
+
+	sort.Slice(tw.comments, func(i, j int) bool {
+		return tw.comments[i].Offset < tw.comments[j].Offset
+	})
+
+	return tw.comments
+}
+
+// TemplateLineColumn converts an offset in text to a line and column, both starting at 1
+func TemplateLineColumn(text string, offset int) (int, int) {
+	line := 1 + strings.Count(text[:offset], "\n")
+	column := offset - strings.LastIndex(text[:offset], "\n")
+	return line, column
+}
+
+type templateWalker struct {
+	html      bool
+	funcNames map[string]bool
+
+	found    []TemplateString
+	comments []TemplateString
+}
+
+func (tw *templateWalker) walk(node parse.Node) {
+	switch x := node.(type) {
+	case *parse.ListNode:
+		if x == nil {
+			return
+		}
+		for _, child := range x.Nodes {
+			tw.walk(child)
+		}
+	case *parse.TextNode:
+		tw.addText(string(x.Text), int(x.Pos))
+	case *parse.CommentNode:
+		text := strings.TrimSuffix(strings.TrimPrefix(x.Text, "/*"), "*/")
+		tw.comments = append(tw.comments, TemplateString{Value: text, Offset: int(x.Pos)})
+	case *parse.ActionNode:
+		tw.walkPipe(x.Pipe)
+	case *parse.IfNode:
+		tw.walkBranch(&x.BranchNode)
+	case *parse.RangeNode:
+		tw.walkBranch(&x.BranchNode)
+	case *parse.WithNode:
+		tw.walkBranch(&x.BranchNode)
+	case *parse.TemplateNode:
+		tw.walkPipe(x.Pipe)
+	}
+}
+
+func (tw *templateWalker) walkBranch(branch *parse.BranchNode) {
+	tw.walkPipe(branch.Pipe)
+	tw.walk(branch.List)
+	tw.walk(branch.ElseList)
+}
+
+func (tw *templateWalker) walkPipe(pipe *parse.PipeNode) {
+	if pipe == nil {
+		return
+	}
+
+	for i, cmd := range pipe.Cmds {
+		for j, arg := range cmd.Args {
+			switch x := arg.(type) {
+			case *parse.IdentifierNode:
+				if !tw.funcNames[x.Ident] {
+					continue
+				}
+
+				if j+1 < len(cmd.Args) {
+					// {{T "Hello"}}
+					if stringNode, ok := cmd.Args[j+1].(*parse.StringNode); ok {
+						tw.found = append(tw.found, TemplateString{Value: stringNode.Text, Offset: int(stringNode.Pos)})
+					}
+				} else if j == 0 && i > 0 && len(pipe.Cmds[i-1].Args) == 1 {
+					// {{"Hello" | T}}
+					if stringNode, ok := pipe.Cmds[i-1].Args[0].(*parse.StringNode); ok {
+						tw.found = append(tw.found, TemplateString{Value: stringNode.Text, Offset: int(stringNode.Pos)})
+					}
+				}
+			case *parse.PipeNode:
+				tw.walkPipe(x)
+			}
+		}
+	}
+}
+
+func (tw *templateWalker) addText(text string, offset int) {
+	if !tw.html {
+		tw.addTrimmedText(text, offset)
+		return
+	}
+
+	start := 0
+	for _, tag := range htmlTagRegexp.FindAllStringIndex(text, -1) {
+		tw.addTrimmedText(text[start:tag[0]], offset+start)
+		start = tag[1]
+	}
+	tw.addTrimmedText(text[start:], offset+start)
+}
+
+func (tw *templateWalker) addTrimmedText(text string, offset int) {
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	offset += len(text) - len(trimmed)
+	trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+
+	if strings.IndexFunc(trimmed, unicode.IsLetter) == -1 {
+		return
+	}
+
+	tw.found = append(tw.found, TemplateString{Value: trimmed, Offset: offset})
+}
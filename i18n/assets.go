@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pivotal-cf-experimental/jibber_jabber"
+)
+
+// AssetVariants returns the names of the variants of an asset file for a locale, the most specific first and the asset
+// itself last, e.g., help.fr_FR.md, help.fr.md and help.md for fr_FR or fr-FR
+func AssetVariants(fileName, locale string) []string {
+	extension := filepath.Ext(fileName)
+	base := strings.TrimSuffix(fileName, extension)
+
+	var variants []string
+	locale = strings.Replace(locale, "-", "_", -1)
+	if locale != "" {
+		variants = append(variants, base+"."+locale+extension)
+		if language := strings.SplitN(locale, "_", 2)[0]; language != locale {
+			variants = append(variants, base+"."+language+extension)
+		}
+	}
+
+	return append(variants, fileName)
+}
+
+// LoadAssetForLocale reads the first variant of an asset file for a locale that exists, e.g., the translated
+// help.fr.md of help.md
+func LoadAssetForLocale(fileName, locale string) ([]byte, error) {
+	return loadAssetVariant(ioutil.ReadFile, fileName, locale)
+}
+
+// LoadAssetForLocaleFS reads the first variant of an asset file of fsys for a locale that exists, e.g., of the asset
+// files a package embeds with go:embed
+func LoadAssetForLocaleFS(fsys fs.FS, fileName, locale string) ([]byte, error) {
+	return loadAssetVariant(func(variant string) ([]byte, error) {
+		return fs.ReadFile(fsys, filepath.ToSlash(variant))
+	}, fileName, locale)
+}
+
+func loadAssetVariant(readFile func(string) ([]byte, error), fileName, locale string) ([]byte, error) {
+	var err error
+	for _, variant := range AssetVariants(fileName, locale) {
+		var content []byte
+		content, err = readFile(variant)
+		if err == nil {
+			return content, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+// LoadAsset reads the variant of an asset file for the locale of the user
+func LoadAsset(fileName string) ([]byte, error) {
+	locale, err := jibber_jabber.DetectIETF()
+	if err != nil {
+		locale = DEFAULT_LOCALE
+	}
+
+	return LoadAssetForLocale(fileName, locale)
+}
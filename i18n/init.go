@@ -1,10 +1,13 @@
 package i18n
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -16,8 +19,17 @@ import (
 const (
 	DEFAULT_LOCALE   = "en_US"
 	DEFAULT_LANGUAGE = "en"
+
+	// separates the context from the ID of a message translated in a context
+	CONTEXT_SEPARATOR = "\x04"
+
+	// separates the context from the ID of a message in the readable IDs of the runtimes without contexts
+	READABLE_CONTEXT_SEPARATOR = "."
 )
 
+// TranslateContextFunc translates a message in a context, e.g., TC("menu", "Open")
+type TranslateContextFunc func(context string, translationID string, args ...interface{}) string
+
 var SUPPORTED_LOCALES = map[string]string{
 	"de": "de_DE",
 	"en": "en_US",
@@ -32,14 +44,32 @@ var SUPPORTED_LOCALES = map[string]string{
 }
 var RESOUCES_PATH = filepath.Join("cf", "i18n", "resources")
 
+// Asset reads the go-bindata assets Init loads the translations from, it is set to the Asset func go-bindata generates
+var Asset func(name string) ([]byte, error)
+
+// readAssetFunc reads the translations of a locale, assetKey is <i18nDirname>/<language>/<packageName>/<locale>.all.json
+type readAssetFunc func(assetKey string) ([]byte, error)
+
 func GetResourcesPath() string {
 	return RESOUCES_PATH
 }
 
 func Init(packageName string, i18nDirname string) go_i18n.TranslateFunc {
-	userLocale, err := initWithUserLocale(packageName, i18nDirname)
+	return initWithAssets(readBindataAsset, packageName, i18nDirname)
+}
+
+// InitFS loads the translations from the i18nDirname directory of fsys, e.g., the locale directory a package embeds
+// with go:embed, instead of the go-bindata assets
+func InitFS(fsys fs.FS, packageName string, i18nDirname string) go_i18n.TranslateFunc {
+	return initWithAssets(func(assetKey string) ([]byte, error) {
+		return fs.ReadFile(fsys, filepath.ToSlash(assetKey))
+	}, packageName, i18nDirname)
+}
+
+func initWithAssets(readAsset readAssetFunc, packageName string, i18nDirname string) go_i18n.TranslateFunc {
+	userLocale, err := initWithUserLocale(readAsset, packageName, i18nDirname)
 	if err != nil {
-		userLocale = mustLoadDefaultLocale(packageName, i18nDirname)
+		userLocale = mustLoadDefaultLocale(readAsset, packageName, i18nDirname)
 	}
 
 	T, err := go_i18n.Tfunc(userLocale, DEFAULT_LOCALE)
@@ -50,7 +80,116 @@ func Init(packageName string, i18nDirname string) go_i18n.TranslateFunc {
 	return T
 }
 
-func initWithUserLocale(packageName, i18nDirname string) (string, error) {
+// UserLocale is the locale of the user, e.g., fr_FR, or DEFAULT_LOCALE when it cannot be detected
+func UserLocale() string {
+	userLocale, err := jibber_jabber.DetectIETF()
+	if err != nil {
+		return DEFAULT_LOCALE
+	}
+
+	return strings.Replace(userLocale, "-", "_", 1)
+}
+
+// Translations reads the translations of every locale of a package from the i18nDirname directory of fsys, for the
+// translation runtimes other than go-i18n v1. They are returned by locale, e.g., fr_FR, and by message ID, the ID of a
+// message translated in a context is prefixed with its context and CONTEXT_SEPARATOR
+func Translations(fsys fs.FS, packageName string, i18nDirname string) (map[string]map[string]string, error) {
+	pattern := path.Join(filepath.ToSlash(i18nDirname), "*", filepath.ToSlash(packageName), "*.all.json")
+	fileNames, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fileNames) == 0 {
+		return nil, errors.New(fmt.Sprintf("Could not find i18n assets: %v", pattern))
+	}
+
+	translations := make(map[string]map[string]string)
+	for _, fileName := range fileNames {
+		byteArray, err := fs.ReadFile(fsys, fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		var messages []struct {
+			ID          string          `json:"id"`
+			Context     string          `json:"context"`
+			Translation json.RawMessage `json:"translation"`
+		}
+		err = json.Unmarshal(byteArray, &messages)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("Could not load i18n asset: %v, %v", fileName, err))
+		}
+
+		locale := strings.TrimSuffix(path.Base(fileName), ".all.json")
+		if translations[locale] == nil {
+			translations[locale] = make(map[string]string)
+		}
+		for _, message := range messages {
+			id := message.ID
+			if message.Context != "" {
+				id = message.Context + CONTEXT_SEPARATOR + id
+			}
+			translation, err := messageTranslation(message.Translation)
+			if err != nil {
+				return nil, errors.New(fmt.Sprintf("Could not load the translation of %q of i18n asset: %v, %v", message.ID, fileName, err))
+			}
+			translations[locale][id] = translation
+		}
+	}
+
+	return translations, nil
+}
+
+// messageTranslation is the translation of a message of a go-i18n v1 file, the translation of a plural message is its
+// other form, e.g., {"one": "{{.Count}} app", "other": "{{.Count}} apps"}
+func messageTranslation(rawTranslation json.RawMessage) (string, error) {
+	if len(rawTranslation) == 0 {
+		return "", nil
+	}
+
+	var translation string
+	if err := json.Unmarshal(rawTranslation, &translation); err == nil {
+		return translation, nil
+	}
+
+	var pluralTranslation map[string]string
+	if err := json.Unmarshal(rawTranslation, &pluralTranslation); err != nil {
+		return "", errors.New("the translation is neither a string nor plural forms")
+	}
+
+	translation, ok := pluralTranslation["other"]
+	if !ok {
+		return "", errors.New("the plural translation has no other form")
+	}
+
+	return translation, nil
+}
+
+// ReadableMessageID is the ID of a message of Translations for the runtimes without contexts, the ID of a message in a
+// context is prefixed with its context and a dot, e.g., menu.Open
+func ReadableMessageID(id string) string {
+	return strings.Replace(id, CONTEXT_SEPARATOR, READABLE_CONTEXT_SEPARATOR, 1)
+}
+
+// ContextTfunc translates the messages of a context with T, falling back to the
+// translation of the message without context when the context has none
+func ContextTfunc(T go_i18n.TranslateFunc) TranslateContextFunc {
+	return func(context string, translationID string, args ...interface{}) string {
+		if context == "" {
+			return T(translationID, args...)
+		}
+
+		contextID := context + CONTEXT_SEPARATOR + translationID
+		if translation := T(contextID, args...); translation != contextID {
+			return translation
+		}
+
+		return T(translationID, args...)
+	}
+}
+
+func initWithUserLocale(readAsset readAssetFunc, packageName, i18nDirname string) (string, error) {
 	userLocale, err := jibber_jabber.DetectIETF()
 	if err != nil {
 		userLocale = DEFAULT_LOCALE
@@ -62,7 +201,7 @@ func initWithUserLocale(packageName, i18nDirname string) (string, error) {
 	}
 
 	userLocale = strings.Replace(userLocale, "-", "_", 1)
-	err = loadFromAsset(packageName, i18nDirname, userLocale, language)
+	err = loadFromAsset(readAsset, packageName, i18nDirname, userLocale, language)
 	if err != nil {
 		locale := SUPPORTED_LOCALES[language]
 		if locale == "" {
@@ -70,16 +209,16 @@ func initWithUserLocale(packageName, i18nDirname string) (string, error) {
 		} else {
 			userLocale = locale
 		}
-		err = loadFromAsset(packageName, i18nDirname, userLocale, language)
+		err = loadFromAsset(readAsset, packageName, i18nDirname, userLocale, language)
 	}
 
 	return userLocale, err
 }
 
-func mustLoadDefaultLocale(packageName, i18nDirname string) string {
+func mustLoadDefaultLocale(readAsset readAssetFunc, packageName, i18nDirname string) string {
 	userLocale := DEFAULT_LOCALE
 
-	err := loadFromAsset(packageName, i18nDirname, DEFAULT_LOCALE, DEFAULT_LANGUAGE)
+	err := loadFromAsset(readAsset, packageName, i18nDirname, DEFAULT_LOCALE, DEFAULT_LANGUAGE)
 	if err != nil {
 		panic("Could not load en_US language files. God save the queen. " + err.Error())
 	}
@@ -87,11 +226,19 @@ func mustLoadDefaultLocale(packageName, i18nDirname string) string {
 	return userLocale
 }
 
-func loadFromAsset(packageName, assetPath, locale, language string) error {
+func readBindataAsset(assetKey string) ([]byte, error) {
+	if Asset == nil {
+		return nil, errors.New("No go-bindata Asset func to load i18n asset: " + assetKey + ", set i18n.Asset or use i18n.InitFS")
+	}
+
+	return Asset(assetKey)
+}
+
+func loadFromAsset(readAsset readAssetFunc, packageName, assetPath, locale, language string) error {
 	assetName := locale + ".all.json"
 	assetKey := filepath.Join(assetPath, language, packageName, assetName)
 
-	byteArray, err := resources.Asset(assetKey)
+	byteArray, err := readAsset(assetKey)
 	if err != nil {
 		return err
 	}
@@ -108,6 +255,11 @@ func loadFromAsset(packageName, assetPath, locale, language string) error {
 		os.RemoveAll(tmpDir)
 	}()
 
+	byteArray, err = keyContextTranslations(byteArray)
+	if err != nil {
+		return err
+	}
+
 	fileName, err := saveLanguageFileToDisk(tmpDir, assetName, byteArray)
 	if err != nil {
 		return err
@@ -120,6 +272,33 @@ func loadFromAsset(packageName, assetPath, locale, language string) error {
 	return nil
 }
 
+// keyContextTranslations prefixes the IDs of the translations that have a context
+// with their context so the same ID can be translated differently in each context
+func keyContextTranslations(byteArray []byte) ([]byte, error) {
+	var translations []map[string]interface{}
+	err := json.Unmarshal(byteArray, &translations)
+	if err != nil {
+		return nil, err
+	}
+
+	hasContext := false
+	for _, translation := range translations {
+		context, _ := translation["context"].(string)
+		id, _ := translation["id"].(string)
+		if context != "" {
+			translation["id"] = context + CONTEXT_SEPARATOR + id
+			delete(translation, "context")
+			hasContext = true
+		}
+	}
+
+	if !hasContext {
+		return byteArray, nil
+	}
+
+	return json.Marshal(translations)
+}
+
 func saveLanguageFileToDisk(tmpDir, assetName string, byteArray []byte) (fileName string, err error) {
 	fileName = filepath.Join(tmpDir, assetName)
 	file, err := os.Create(fileName)
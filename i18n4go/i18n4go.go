@@ -13,7 +13,7 @@ import (
 	"github.com/Liam-Williams/i18n4go/common"
 )
 
-const VERSION = "v0.2.7"
+const VERSION = common.VERSION
 
 var options common.Options
 
@@ -42,13 +42,17 @@ func main() {
 		checkupCmd()
 	case "fixup":
 		fixupCmd()
+	case "rename-args":
+		renameArgsCmd()
+	case "rewrite-undo":
+		rewriteUndoCmd()
 	default:
 		usage()
 	}
 }
 
 func extractStringsCmd() {
-	if options.HelpFlag || (options.FilenameFlag == "" && options.DirnameFlag == "") {
+	if options.HelpFlag || (options.FilenameFlag == "" && options.DirnameFlag == "" && !options.ClearCacheFlag) {
 		usage()
 		return
 	}
@@ -208,8 +212,48 @@ func fixupCmd() {
 	fixup.Println("Total time:", duration)
 }
 
+func renameArgsCmd() {
+	if options.HelpFlag {
+		usage()
+		return
+	}
+
+	renameArgs := cmds.NewRenameArgs(options)
+
+	startTime := time.Now()
+
+	err := renameArgs.Run()
+	if err != nil {
+		renameArgs.Println("i18n4go: Could not rename args, err:", err)
+		os.Exit(1)
+	}
+
+	duration := time.Now().Sub(startTime)
+	renameArgs.Println("Total time:", duration)
+}
+
+func rewriteUndoCmd() {
+	if options.HelpFlag {
+		usage()
+		return
+	}
+
+	rewriteUndo := cmds.NewRewriteUndo(options)
+
+	startTime := time.Now()
+
+	err := rewriteUndo.Run()
+	if err != nil {
+		rewriteUndo.Println("i18n4go: Could not undo the rewrite, err:", err)
+		os.Exit(1)
+	}
+
+	duration := time.Now().Sub(startTime)
+	rewriteUndo.Println("Total time:", duration)
+}
+
 func init() {
-	flag.StringVar(&options.CommandFlag, "c", "", "the command, one of: extract-strings, create-translations, rewrite-package, verify-strings, merge-strings, checkup, fixup")
+	flag.StringVar(&options.CommandFlag, "c", "", "the command, one of: extract-strings, create-translations, rewrite-package, rewrite-undo, verify-strings, merge-strings, checkup, fixup, rename-args")
 
 	flag.BoolVar(&options.HelpFlag, "h", false, "prints the usage")
 	flag.BoolVar(&options.LongHelpFlag, "-help", false, "prints the usage")
@@ -224,15 +268,19 @@ func init() {
 
 	flag.BoolVar(&options.MetaFlag, "meta", false, "[optional] create a *.extracted.json file with metadata such as: filename, directory, and positions of the strings in source file")
 	flag.BoolVar(&options.DryRunFlag, "dry-run", false, "prevents any output files from being created")
+	flag.BoolVar(&options.SinksFlag, "sinks", false, "[optional] load packages with type information and only extract strings that flow into a sink, e.g., fmt.Print*, fmt.Errorf, errors.New, io.Writer.Write or the sinks in the excluded JSON file")
 
 	flag.StringVar(&options.ExcludedFilenameFlag, "e", "excluded.json", "[optional] the excluded JSON file name, all strings there will be excluded")
 
 	flag.StringVar(&options.SubstringFilenameFlag, "s", "capturing_group.json", "[optional] the substring capturing JSON file name, all strings there will only have their first capturing group saved as a translation")
 
+	flag.StringVar(&options.RulesFilenameFlag, "rules", "", "[optional] a YAML or JSON rules file with exclusions, enforced funcs, sinks and substring captures, applied before the .i18n4go.yml files of the extracted directories")
+
 	flag.StringVar(&options.OutputDirFlag, "o", "", "output directory where the translation files will be placed")
 
 	flag.BoolVar(&options.OutputFlatFlag, "output-flat", true, "generated files are created in the specified output directory")
 	flag.BoolVar(&options.OutputMatchPackageFlag, "output-match-package", false, "generated files are created in directory to match the package name")
+	flag.BoolVar(&options.OutputMatchImportFlag, "output-match-import", false, "generated files are created in directory to match the package import path")
 
 	flag.StringVar(&options.FilenameFlag, "f", "", "the file name for which strings are extracted")
 
@@ -240,6 +288,17 @@ func init() {
 
 	flag.BoolVar(&options.RecurseFlag, "r", false, "recursively extract strings from all files in the same directory as filename or dirName")
 
+	flag.IntVar(&options.JobsFlag, "j", 0, "[optional] the maximum number of files processed concurrently, defaults to the number of CPUs")
+
+	flag.BoolVar(&options.NoCacheFlag, "no-cache", false, "[optional] extract every file again instead of reusing the results cached for unchanged files")
+	flag.BoolVar(&options.ClearCacheFlag, "clear-cache", false, "[optional] remove the cached extraction results before extracting, without -f or -d the cache is only cleared")
+	flag.StringVar(&options.CacheDirFlag, "cache-dir", "", "[optional] the directory of the extraction cache, defaults to i18n4go/extract-strings in the user cache directory")
+
+	flag.BoolVar(&options.ExplainFlag, "explain", false, "[optional] print every string literal with its extract or skip decision and the rule, directive or detector behind it")
+	flag.StringVar(&options.ExplainFormatFlag, "explain-format", common.TEXT_EXPLAIN_FORMAT, "[optional] the format of the --explain report, text or json")
+
+	flag.StringVar(&options.TagsFlag, "tags", "", "[optional] a comma separated list of build tags used when loading packages, e.g., \"linux,integration\"")
+
 	flag.StringVar(&options.IgnoreRegexpFlag, "ignore-regexp", ".*test.*", "a perl-style regular expression for files to ignore, e.g., \".*test.*\"")
 
 	flag.StringVar(&options.LanguageFilesFlag, "language-files", "", `[optional] a comma separated list of target files for different languages to compare,  e.g., \"en, en_US, fr_FR, es\"	                                                                  if not specified then the languages flag is used to find target files in same directory as source`)
@@ -249,30 +308,47 @@ func init() {
 	flag.StringVar(&options.RootPathFlag, "root-path", "", "the root path to the Go source files whose packages are being rewritten, defaults to working directory, if not specified")
 
 	flag.StringVar(&options.InitCodeSnippetFilenameFlag, "init-code-snippet-filename", "", "[optional] the path to a file containing the template snippet for the code that is used for go-i18n initialization")
+	flag.StringVar(&options.BackendFlag, "backend", "", "[optional] the translation runtime the rewritten strings call: go-i18n (default) for T(...), go-i18n-v2 for Localizer.MustLocalize(...) or x-text for Printer.Sprintf(...), go-i18n-v2 and x-text require --embed-dirname")
+	flag.StringVar(&options.EmbedDirnameFlag, "embed-dirname", "", "[optional] the locale directory of each rewritten package, relative to it, that its i18n_init.go embeds with go:embed instead of loading the translations with go-bindata")
+	flag.BoolVar(&options.MergeConcatenationsFlag, "merge-concatenations", false, "[optional] wrap each concatenation mixing strings and other expressions with one templated message whose args are the expressions, instead of wrapping each string")
+
+	flag.BoolVar(&options.DiffFlag, "diff", false, "[optional] print a unified diff of the changes of rewrite-package instead of writing them")
+	flag.BoolVar(&options.CheckFlag, "check", false, "[optional] exit with an error when rewrite-package would change a file, without writing it")
+	flag.StringVar(&options.PatchFlag, "patch", "", "[optional] write the changes of rewrite-package to a patch file that git apply accepts instead of writing them")
+
+	flag.StringVar(&options.IdStrategyFlag, "id-strategy", common.SOURCE_ID_STRATEGY, "[optional] how the IDs of the messages are made: source (the source text), hash (a hash of the source text) or semantic (package.funcWords)")
 
 	flag.StringVar(&options.QualifierFlag, "q", "", "[optional] the qualifier string that is used when using the T(...) function, default to nothing but could be set to `i18n` so that all calls would be: i18n.T(...)")
+	flag.StringVar(&options.TranslatorPackageFlag, "translator-package", "", "[optional] the import path of the central package declaring T(...) and TC(...), rewrite-package then calls them qualified, imports the package and generates no i18n_init.go")
+
+	flag.StringVar(&options.ArgNamesFilenameFlag, "arg-names", "", "[optional] a JSON file mapping the strings to the new names of their args, e.g., {\"Deleting {{.Arg0}}\": {\"Arg0\": \"AppName\"}}, used by rename-args before the names of the values of the args")
 
 	flag.Parse()
 }
 
 func usage() {
 	usageString := `
-usage: i18n4go -c extract-strings [-vpe] [--dry-run] [--output-flat|--output-match-package|-o <outputDir>] -f <fileName>
-   or: i18n4go -c extract-strings [-vpe] [--dry-run] [--output-flat|--output-match-package|-o <outputDir>] -d <dirName> [-r] [--ignore-regexp <fileNameRegexp>]
+usage: i18n4go -c extract-strings [-vpe] [--rules <rulesFile>] [--dry-run] [--sinks] [--explain [--explain-format text|json]] [--id-strategy source|hash|semantic] [--tags <tags>] [-j <jobs>] [--no-cache|--clear-cache] [--cache-dir <dirName>] [--output-flat|--output-match-package|--output-match-import|-o <outputDir>] -f <fileName>
+   or: i18n4go -c extract-strings [-vpe] [--rules <rulesFile>] [--dry-run] [--sinks] [--explain [--explain-format text|json]] [--id-strategy source|hash|semantic] [--tags <tags>] [-j <jobs>] [--no-cache|--clear-cache] [--cache-dir <dirName>] [--output-flat|--output-match-package|--output-match-import|-o <outputDir>] -d <dirName> [-r] [--ignore-regexp <fileNameRegexp>]
+   or: i18n4go -c extract-strings --clear-cache [--cache-dir <dirName>]
 
-usage: i18n4go -c rewrite-package [-v] [-r] -d <dirName> [--i18n-strings-filename <fileName> | --i18n-strings-dirname <dirName>] [--init-code-snippet-filename <fileName>]
-   or: i18n4go -c rewrite-package [-v] [-r] -f <fileName> --i18n-strings-filename <fileName> [--init-code-snippet-filename <fileName>]
+usage: i18n4go -c rewrite-package [-v] [-r] [--id-strategy source|hash|semantic] [--tags <tags>] [--diff] [--check] [--patch <patchFile>] [-q <qualifier>] [--translator-package <importPath>] [--backend go-i18n|go-i18n-v2|x-text] [--merge-concatenations] -d <dirName> [--i18n-strings-filename <fileName> | --i18n-strings-dirname <dirName>] [--init-code-snippet-filename <fileName>] [--embed-dirname <dirName>]
+   or: i18n4go -c rewrite-package [-v] [-r] [--id-strategy source|hash|semantic] [--tags <tags>] [--diff] [--check] [--patch <patchFile>] [-q <qualifier>] [--translator-package <importPath>] [--backend go-i18n|go-i18n-v2|x-text] [--merge-concatenations] -f <fileName> --i18n-strings-filename <fileName> [--init-code-snippet-filename <fileName>] [--embed-dirname <dirName>]
 
 usage: i18n4go -c create-translations [-v] [--google-translate-api-key <api key>] [--source-language <language>] -f <fileName> --languages <lang1,lang2,...> -o <outputDir>
 
 usage: i18n4go -c merge-strings [-v] [-r] [--source-language <language>] -d <dirName>
 
-usage: i18n4go -c verify-strings [-v] [--source-language <language>] -f <sourceFileName> --language-files <language files>
-   or: i18n4go -c verify-strings [-v] [--source-language <language>] -f <sourceFileName> --languages <lang1,lang2,...>
+usage: i18n4go -c verify-strings [-v] [--source-language <language>] [--id-strategy source|hash|semantic] -f <sourceFileName> --language-files <language files>
+   or: i18n4go -c verify-strings [-v] [--source-language <language>] [--id-strategy source|hash|semantic] -f <sourceFileName> --languages <lang1,lang2,...>
 
 usage: i18n4go -c show-missing-strings [-v] -d <dirName> --i18n-strings-filename <language file>
 
-usage: i18n4go -c checkup
+usage: i18n4go -c checkup [-q <qualifier>] [--id-strategy source|hash|semantic]
+
+usage: i18n4go -c rename-args [-v] [--dry-run] [-q <qualifier>] [-d <dirName>] [--arg-names <argNamesFile>]
+
+usage: i18n4go -c rewrite-undo [-v] [-d <outputDir>]
 
   -h | --help                prints the usage
   -v                         verbose
@@ -284,20 +360,36 @@ usage: i18n4go -c checkup
   --po                       to generate standard .po files for translation
   -e                         [optional] the JSON file with strings to be excluded, defaults to excluded.json if present
 	-s												 [optional] the JSON file with regexp that specify a capturing group to be extracted instead of the full string matching the regexp
+  --rules                    [optional] a YAML or JSON rules file, see Rules Files, applied before the .i18n4go.yml, .i18n4go.yaml or .i18n4go.json
+                             files of the extracted directories and of their parents
   --meta                     [optional] create a *.extracted.json file with metadata such as: filename, directory, and positions of the strings in source file
   --dry-run                  [optional] prevents any output files from being created
+  --sinks                    [optional] type check the packages and only extract strings that flow into a sink, e.g., fmt.Print*, fmt.Errorf, errors.New, io.Writer.Write
+                             and the "sinks" listed in the excluded JSON file, the matched sink is saved in the *.extracted.json file
+  --explain                  [optional] print every string literal with its extract or skip decision and the rule, directive or detector behind it
+  --explain-format           [optional] the format of the --explain report, text (default) or json
+  --id-strategy              [optional] how the IDs of the messages are made, see Message IDs: source (default), hash or semantic
 
 
   --output-flat              generated files are created in the specified output directory (default)
   --output-match-package     generated files are created in directory to match the package name
+  --output-match-import      generated files are created in directory to match the package import path, e.g., the module path followed by the package directory
   -o                         the output directory where the translation files will be placed
 
-  -f                         the go file name to extract strings
+  -f                         the go, template or asset file name to extract strings, templates are .tmpl, .tpl, .gotmpl and .html files
+                             and assets are .json, .yml, .yaml, .md, .markdown and .txt files, see Asset Files
 
-  -d                         the directory containing the go files to extract strings
+  -d                         the directory containing the go, template and asset files to extract strings
 
   -r                         [optional] recursesively extract strings from all subdirectories
   --ignore-regexp            [optional] a perl-style regular expression for files to ignore, e.g., ".*test.*"
+  --tags                     [optional] a comma separated list of build tags used when loading packages, packages are loaded with the go command
+                             so that go.mod, go.work, replace directives and vendor directories are honoured
+  -j                         [optional] the maximum number of files extracted concurrently, defaults to the number of CPUs
+  --no-cache                 [optional] extract every file again instead of reusing the cached results of unchanged files
+  --clear-cache              [optional] remove the cached results before extracting, without -f or -d the cache is only cleared
+  --cache-dir                [optional] the directory of the extraction cache, defaults to i18n4go/extract-strings in the user cache
+                             directory, e.g., ~/.cache on Linux
 
   REWRITE-PACKAGE:
 
@@ -307,9 +399,25 @@ usage: i18n4go -c checkup
 
   --i18n-strings-filename    a JSON file with the strings that should be i18n enabled, typically the output of -extract-strings command
   --i18n-strings-dirname     a directory with the extracted JSON files, using -output-match-package with -extract-strings this directory should match the input files package name
-  --root-path                the root path to the Go source files whose packages are being rewritten, defaults to working directory, if not specified
+  --root-path                the root path to the Go source files whose packages are being rewritten, defaults to the module root or working directory, if not specified
+  --tags                     [optional] a comma separated list of build tags used when loading packages
+  --id-strategy              [optional] T() is called with the IDs of the i18n strings file, or with new IDs saved to <outputDir>/<source-language>.all.json
+                             when there is none: source (default), hash or semantic
+  --diff                     [optional] print a unified diff of every change instead of writing the files
+  --check                    [optional] exit with an error when a file would change, without writing the files
+  --patch                    [optional] write the changes to a patch file that git apply accepts instead of writing the files
+  -q                         [optional] the qualifier of the T(...) calls, e.g., i18n for i18n.T(...), the files calling T(...) must import a package with that name
+                             unless --translator-package is given
+  --translator-package       [optional] the import path of the central package declaring T(...) and TC(...), the calls are qualified with -q or with
+                             the name of the package, the package is imported where needed and no i18n_init.go is generated
+  --backend                  [optional] the translation runtime the rewritten strings call: go-i18n (default) for T(...), go-i18n-v2 for
+                             Localizer.MustLocalize(&i18n.LocalizeConfig{...}) or x-text for Printer.Sprintf(...) of golang.org/x/text/message
+  --merge-concatenations    [optional] wrap each concatenation mixing strings and other expressions, e.g., "Deleted " + name, with one templated
+                             message whose args are the expressions, and replace the strings it merges with it in the i18n strings files
 
   --init-code-snippet-filename [optional] the path to a file containing the template snippet for the code that is used for go-i18n initialization"
+  --embed-dirname              [optional] the locale directory of each rewritten package, relative to it, that its i18n_init.go embeds with go:embed
+                               instead of loading the translations with go-bindata's Asset()
   -o                           [optional] output diretory for rewritten file. If not specified, the original file will be overwritten
 
   MERGE STRINGS:
@@ -337,6 +445,7 @@ usage: i18n4go -c checkup
   -c verify-strings          the verify strings command
 
   --source-language          [optional] the source language of the source translation file (default to 'en')
+  --id-strategy              [optional] the templated strings are checked against the source text rather than the ID with hash or semantic IDs
 
   -f                         the source translation file
 
@@ -355,10 +464,27 @@ usage: i18n4go -c checkup
 
   -c checkup                 the checkup command which ensures that the strings in code match strings in resource files and vice versa
   -q                         the qualifier to use when calling the T(...), defaults to empty but can be used to set to something like i18n for example, such that, i18n.T(...) is used for T(...) function
+  --id-strategy              [optional] with hash or semantic also reports the IDs that do not follow the strategy
 
   FIXUP:
 
   -c fixup                   the fixup command which interactively lets users add, update, or remove translations keys from code and resource files.
+
+  RENAME-ARGS:
+
+  -c rename-args             the rename args command which renames the positional args of the templated strings, e.g., {{.Arg0}}, after the values
+                             the T(...) calls give them, e.g., {{.OrgName}} for org.Name, in the code and in every <language>.all.json file
+  -d                         [optional] the directory containing the go and language files, defaults to the working directory
+  -q                         [optional] the qualifier of the T(...) calls, e.g., i18n for i18n.T(...)
+  --arg-names                [optional] a JSON file mapping the strings to the new names of their args, e.g., {"Deleting {{.Arg0}}": {"Arg0": "AppName"}},
+                             the args it does not name are named after their values
+  --dry-run                  [optional] print the renamed strings without writing the files
+
+  REWRITE-UNDO:
+
+  -c rewrite-undo            the rewrite undo command which restores the files of the last rewrite-package run, from the undo journal
+                             it saved to its output dir, unless a file changed since
+  -d                         [optional] the output dir of the rewrite, defaults to the working directory
 `
 	fmt.Println(fmt.Sprintf("%s\nVersion %s", usageString, VERSION))
 }
@@ -73,6 +73,24 @@ var _ = Describe("checkup", func() {
 		})
 	})
 
+	Context("When the strings missing from the translations are ignored by directives", func() {
+		BeforeEach(func() {
+			fixturesPath = filepath.Join("..", "..", "test_fixtures", "checkup", "directives")
+			err = os.Chdir(fixturesPath)
+			Ω(err).ToNot(HaveOccurred(), "Could not change to fixtures directory")
+
+			session = Runi18n("-c", "checkup", "-v")
+		})
+
+		It("returns 0", func() {
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("prints a reassuring message", func() {
+			Ω(session).Should(Say("OK"))
+		})
+	})
+
 	Context("When there are problems", func() {
 		BeforeEach(func() {
 			fixturesPath = filepath.Join("..", "..", "test_fixtures", "checkup", "notsogood")
@@ -100,4 +118,27 @@ var _ = Describe("checkup", func() {
 			Ω(session.ExitCode()).Should(Equal(1))
 		})
 	})
+
+	Context("When the IDs are hashes", func() {
+		BeforeEach(func() {
+			fixturesPath = filepath.Join("..", "..", "test_fixtures", "checkup", "hash_ids")
+			err = os.Chdir(fixturesPath)
+			Ω(err).ToNot(HaveOccurred(), "Could not change to fixtures directory")
+
+			session = Runi18n("-c", "checkup", "-v", "--id-strategy", "hash")
+		})
+
+		It("shows the IDs that are not hashes and returns 1", func() {
+			output := string(session.Out.Contents())
+
+			Ω(output).Should(ContainSubstring("\"Welcome\" in the code is not a hash ID"))
+			Ω(output).Should(ContainSubstring("\"Welcome\" in en_US is not a hash ID"))
+
+			// the source text of the ID changed after it was hashed, the ID is kept
+			Ω(output).ShouldNot(ContainSubstring("\"4b94bb1971\""))
+			Ω(output).ShouldNot(ContainSubstring("\"ee62cca1f6\""))
+
+			Ω(session.ExitCode()).Should(Equal(1))
+		})
+	})
 })
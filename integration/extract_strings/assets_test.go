@@ -0,0 +1,86 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings from asset files", func() {
+	var (
+		outputPath        string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		_, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "extract_strings", "assets")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	Context("When the assets rule selects JSON, YAML, text and Markdown files", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c", "extract-strings", "-v", "--meta", "--no-cache", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("extracts the strings of the YAML paths", func() {
+			CompareExpectedToGeneratedExtendedJson(
+				filepath.Join(expectedFilesPath, "config.yml.extracted.json"),
+				filepath.Join(outputPath, "config.yml.extracted.json"),
+			)
+		})
+
+		It("extracts the strings of the JSONPaths", func() {
+			CompareExpectedToGeneratedExtendedJson(
+				filepath.Join(expectedFilesPath, "settings.json.extracted.json"),
+				filepath.Join(outputPath, "settings.json.extracted.json"),
+			)
+		})
+
+		It("extracts a text file whole in file mode", func() {
+			CompareExpectedToGeneratedExtendedJson(
+				filepath.Join(expectedFilesPath, "banner.txt.extracted.json"),
+				filepath.Join(outputPath, "banner.txt.extracted.json"),
+			)
+		})
+
+		It("extracts the paragraphs and headings of a Markdown file but not its code blocks", func() {
+			CompareExpectedToGeneratedExtendedJson(
+				filepath.Join(expectedFilesPath, "help.md.extracted.json"),
+				filepath.Join(outputPath, "help.md.extracted.json"),
+			)
+		})
+	})
+
+	Context("When an asset file is extracted with -f", func() {
+		It("writes its strings with their lines to the PO file", func() {
+			session := Runi18n("-c", "extract-strings", "-v", "--po", "--no-cache", "-f", filepath.Join(inputFilesPath, "help.md"), "-o", outputPath)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			expected, err := ioutil.ReadFile(filepath.Join(expectedFilesPath, "help.md.en.po"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			generated, err := ioutil.ReadFile(filepath.Join(outputPath, "help.md.en.po"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(string(generated)).Should(Equal(string(expected)))
+		})
+	})
+})
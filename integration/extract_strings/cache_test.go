@@ -0,0 +1,128 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("extract-strings cache", func() {
+	var (
+		outputPath     string
+		cachePath      string
+		inputFilesPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		cachePath, err = ioutil.TempDir("", "i18n4go4go_cache")
+		Ω(err).ToNot(HaveOccurred())
+
+		inputFilesPath = filepath.Join("..", "..", "test_fixtures", "extract_strings", "descriptions", "input_files")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+		os.RemoveAll(cachePath)
+	})
+
+	extract := func(args ...string) *Session {
+		args = append([]string{"-c", "extract-strings", "-v", "--meta", "--cache-dir", cachePath,
+			"-d", inputFilesPath, "--ignore-regexp", "^[.]\\w+.go$", "-o", outputPath}, args...)
+		session := Runi18n(args...)
+		Ω(session.ExitCode()).Should(Equal(0))
+		return session
+	}
+
+	Context("When the files did not change since the last run", func() {
+		It("reuses the cached strings and generates the same files", func() {
+			session := extract()
+			Ω(string(session.Out.Contents())).Should(ContainSubstring("Cache hits: 0, misses: 2"))
+
+			expected, err := ioutil.ReadFile(filepath.Join(outputPath, "app.go.extracted.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			os.RemoveAll(outputPath)
+
+			session = extract()
+			Ω(string(session.Out.Contents())).Should(ContainSubstring("Cache hits: 2, misses: 0"))
+
+			generated, err := ioutil.ReadFile(filepath.Join(outputPath, "app.go.extracted.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(generated)).Should(Equal(string(expected)))
+		})
+	})
+
+	Context("When the excluded JSON file changes", func() {
+		It("extracts the files again", func() {
+			extract()
+
+			excludedFile := filepath.Join(cachePath, "..", filepath.Base(cachePath)+"_excluded.json")
+			err := ioutil.WriteFile(excludedFile, []byte(`{"excludedStrings": ["Pushing app %s"]}`), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+			defer os.Remove(excludedFile)
+
+			session := extract("-e", excludedFile)
+			Ω(string(session.Out.Contents())).Should(ContainSubstring("Cache hits: 0, misses: 2"))
+		})
+	})
+
+	Context("When --cache-dir is not set", func() {
+		var userCacheEnv map[string]string
+
+		BeforeEach(func() {
+			userCacheEnv = map[string]string{"HOME": os.Getenv("HOME"), "XDG_CACHE_HOME": os.Getenv("XDG_CACHE_HOME")}
+			os.Setenv("HOME", cachePath)
+			os.Setenv("XDG_CACHE_HOME", cachePath)
+		})
+
+		AfterEach(func() {
+			for name, value := range userCacheEnv {
+				os.Setenv(name, value)
+			}
+		})
+
+		It("caches the strings in the user cache directory rather than in the working directory", func() {
+			session := Runi18n("-c", "extract-strings", "-v", "-d", inputFilesPath, "--ignore-regexp", "^[.]\\w+.go$", "-o", outputPath)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			userCacheDirname, err := os.UserCacheDir()
+			Ω(err).ShouldNot(HaveOccurred())
+			entries, err := ioutil.ReadDir(filepath.Join(userCacheDirname, "i18n4go", "extract-strings"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(entries).Should(HaveLen(2))
+
+			Ω(".i18n4go").ShouldNot(BeAnExistingFile())
+		})
+	})
+
+	Context("When --no-cache is set", func() {
+		It("neither reads nor writes the cache", func() {
+			session := extract("--no-cache")
+			Ω(string(session.Out.Contents())).ShouldNot(ContainSubstring("Cache hits"))
+
+			entries, err := ioutil.ReadDir(cachePath)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(entries).Should(BeEmpty())
+		})
+	})
+
+	Context("When --clear-cache is set without files", func() {
+		It("removes the cache", func() {
+			extract()
+
+			session := Runi18n("-c", "extract-strings", "--clear-cache", "--cache-dir", cachePath)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			_, err := os.Stat(cachePath)
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,65 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings constant folding", func() {
+	var (
+		outputPath        string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		_, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "extract_strings", "constants")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	Context("When strings are concatenated with literals and constants", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--meta", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("extracts each run of constant operands as one string", func() {
+			CompareExpectedToGeneratedExtendedJson(
+				filepath.Join(expectedFilesPath, "app.go.extracted.json"),
+				filepath.Join(outputPath, "app.go.extracted.json"),
+			)
+		})
+	})
+
+	Context("When named constants flow into a sink", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--sinks", "--meta", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("extracts the constants at their declaration", func() {
+			CompareExpectedToGeneratedExtendedJson(
+				filepath.Join(expectedFilesPath, "app.go.sinks.extracted.json"),
+				filepath.Join(outputPath, "app.go.extracted.json"),
+			)
+		})
+	})
+})
@@ -37,7 +37,7 @@ var _ = Describe("extract-strings -d dirName", func() {
 
 	Context("When i18n4go4go is run with the -d flag", func() {
 		BeforeEach(func() {
-			session := Runi18n("-c", "extract-strings", "-v", "--po", "--meta", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--po", "--meta", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
 
 			Ω(session.ExitCode()).Should(Equal(0))
 		})
@@ -67,7 +67,7 @@ var _ = Describe("extract-strings -d dirName", func() {
 		BeforeEach(func() {
 			inputFilesPath = filepath.Join(inputFilesPath, "..")
 
-			session := Runi18n("-c", "extract-strings", "-v", "--po", "--meta", "-d", inputFilesPath, "-o", outputPath, "-r", "--ignore-regexp", "^[.]\\w+.go$")
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--po", "--meta", "-d", inputFilesPath, "-o", outputPath, "-r", "--ignore-regexp", "^[.]\\w+.go$")
 			Ω(session.ExitCode()).Should(Equal(0))
 		})
 
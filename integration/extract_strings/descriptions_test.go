@@ -0,0 +1,64 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings translator comments", func() {
+	var (
+		outputPath        string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		_, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "extract_strings", "descriptions")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	for _, fileName := range []string{"app.go", "page.html"} {
+		fileName := fileName
+
+		Context("When "+fileName+" has TRANSLATORS: and i18n: comments", func() {
+			BeforeEach(func() {
+				session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--po", "--meta", "-f", filepath.Join(inputFilesPath, fileName), "-o", outputPath)
+
+				Ω(session.ExitCode()).Should(Equal(0))
+			})
+
+			It("saves the comments as the description of the strings", func() {
+				CompareExpectedToGeneratedExtendedJson(
+					filepath.Join(expectedFilesPath, fileName+".extracted.json"),
+					filepath.Join(outputPath, fileName+".extracted.json"),
+				)
+			})
+
+			It("writes the descriptions as extracted comments in the PO file", func() {
+				expected, err := ioutil.ReadFile(filepath.Join(expectedFilesPath, fileName+".en.po"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				generated, err := ioutil.ReadFile(filepath.Join(outputPath, fileName+".en.po"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(string(generated)).Should(Equal(string(expected)))
+			})
+		})
+	}
+})
@@ -0,0 +1,68 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings directives", func() {
+	var (
+		outputPath        string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		_, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "extract_strings", "directives")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	Context("When a file has ignore and translate directives", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--meta",
+				"-e", filepath.Join(inputFilesPath, "excluded.json"),
+				"-f", filepath.Join(inputFilesPath, "app.go"),
+				"-o", outputPath)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("skips the ignored strings and keeps the ones forced by translate", func() {
+			CompareExpectedToGeneratedExtendedJson(
+				filepath.Join(expectedFilesPath, "app.go.extracted.json"),
+				filepath.Join(outputPath, "app.go.extracted.json"),
+			)
+		})
+	})
+
+	Context("When a file has an ignore-file directive", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--meta",
+				"-f", filepath.Join(inputFilesPath, "generated.go"),
+				"-o", outputPath)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("extracts nothing from the file", func() {
+			_, err := os.Stat(filepath.Join(outputPath, "generated.go.extracted.json"))
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,47 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings enforced fields and qualified enforced funcs", func() {
+	var (
+		outputPath        string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "extract_strings", "enforced_fields")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+
+		session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--meta",
+			"--rules", filepath.Join(inputFilesPath, "rules.yml"),
+			"-f", filepath.Join(inputFilesPath, "app.go"),
+			"-o", outputPath)
+
+		Ω(session.ExitCode()).Should(Equal(0))
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	It("extracts the enforced fields of command literals and the enforced arguments, and skips their other strings", func() {
+		CompareExpectedToGeneratedExtendedJson(
+			filepath.Join(expectedFilesPath, "app.go.extracted.json"),
+			filepath.Join(outputPath, "app.go.extracted.json"),
+		)
+	})
+})
@@ -0,0 +1,56 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("extract-strings --explain", func() {
+	var (
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "extract_strings", "explain")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	for _, format := range []string{"text", "json"} {
+		format := format
+
+		Context("When the report format is "+format, func() {
+			It("lists every string literal with its decision and the rule, directive or detector behind it", func() {
+				session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "--dry-run",
+					"--explain", "--explain-format", format,
+					"--rules", filepath.Join(inputFilesPath, "rules.yml"),
+					"-f", filepath.Join(inputFilesPath, "app.go"))
+
+				Ω(session.ExitCode()).Should(Equal(0))
+
+				fileName := map[string]string{"text": "explain.txt", "json": "explain.json"}[format]
+				expected, err := ioutil.ReadFile(filepath.Join(expectedFilesPath, fileName))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(string(session.Out.Contents())).Should(Equal(string(expected)))
+			})
+		})
+	}
+
+	Context("When the report format is unknown", func() {
+		It("fails before extracting", func() {
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "--dry-run",
+				"--explain", "--explain-format", "xml",
+				"-f", filepath.Join(inputFilesPath, "app.go"))
+
+			Ω(session.ExitCode()).Should(Equal(1))
+			Ω(session).Should(Say(`unknown explain format "xml"`))
+		})
+	})
+})
@@ -1,6 +1,9 @@
 package extract_strings_test
 
 import (
+	"io/ioutil"
+	"os"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -10,6 +13,19 @@ import (
 	"testing"
 )
 
+// the extraction cache of a spec, every spec has its own so its results do not depend on the earlier runs
+var cacheDir string
+
+var _ = BeforeEach(func() {
+	var err error
+	cacheDir, err = ioutil.TempDir("", "i18n4go_cache")
+	Ω(err).ShouldNot(HaveOccurred())
+})
+
+var _ = AfterEach(func() {
+	os.RemoveAll(cacheDir)
+})
+
 func TestExtractStrings(t *testing.T) {
 	BeforeSuite(test_helpers.BuildExecutable)
 
@@ -39,7 +39,7 @@ var _ = Describe("extract-strings -f fileName -o outputDir", func() {
 
 	Context("-o outputDir --output-flat (default)", func() {
 		BeforeEach(func() {
-			session := Runi18n("-c", "extract-strings", "-v", "--po", "--meta", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath)
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--po", "--meta", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath)
 			Ω(session.ExitCode()).Should(Equal(0))
 		})
 
@@ -64,7 +64,7 @@ var _ = Describe("extract-strings -f fileName -o outputDir", func() {
 
 	Context("-o outputDir --output-match-package", func() {
 		BeforeEach(func() {
-			session := Runi18n("-c", "extract-strings", "-v", "--po", "--meta", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath, "--output-match-package")
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--po", "--meta", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath, "--output-match-package")
 			Ω(session.ExitCode()).Should(Equal(0))
 		})
 
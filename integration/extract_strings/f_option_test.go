@@ -28,7 +28,7 @@ var _ = Describe("extract-strings -f fileName", func() {
 
 	Context("compare generated and expected file", func() {
 		BeforeEach(func() {
-			session := Runi18n("-c", "extract-strings", "-v", "--po", "--meta", "-f", filepath.Join(inputFilesPath, "app.go"))
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--po", "--meta", "-f", filepath.Join(inputFilesPath, "app.go"))
 			Ω(session.ExitCode()).Should(Equal(0))
 		})
 
@@ -64,7 +64,7 @@ var _ = Describe("extract-strings -f fileName", func() {
 
 	Context("GitHub issue #4: extracting some character as ascii code, e.g., > as \u003e", func() {
 		BeforeEach(func() {
-			session := Runi18n("-c", "extract-strings", "-v", "-f", filepath.Join(inputFilesPath, "issue4.go"))
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "-f", filepath.Join(inputFilesPath, "issue4.go"))
 			Ω(session.ExitCode()).Should(Equal(0))
 		})
 
@@ -84,7 +84,7 @@ var _ = Describe("extract-strings -f fileName", func() {
 
 	Context("GitHub issue #16: Extract Strings should ignore string keys in maps", func() {
 		BeforeEach(func() {
-			session := Runi18n("-c", "extract-strings", "-v", "-f", filepath.Join(inputFilesPath, "issue16.go"))
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "-f", filepath.Join(inputFilesPath, "issue16.go"))
 			Ω(session.ExitCode()).Should(Equal(0))
 		})
 
@@ -112,7 +112,7 @@ var _ = Describe("extract-strings -f fileName", func() {
 			OUTPUT_PATH, err = ioutil.TempDir("", "i18n4go4go")
 			Ω(err).ShouldNot(HaveOccurred())
 
-			session := Runi18n("-c", "extract-strings", "-f", filepath.Join(inputFilesPath, "no_strings.go"), "-o", OUTPUT_PATH)
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-f", filepath.Join(inputFilesPath, "no_strings.go"), "-o", OUTPUT_PATH)
 			Ω(session.ExitCode()).Should(Equal(0))
 		})
 
@@ -127,7 +127,7 @@ var _ = Describe("extract-strings -f fileName", func() {
 
 	Context("GitHub issue #45: Extract Strings should extract strings string embedded inside a func, inside a func in a return", func() {
 		BeforeEach(func() {
-			session := Runi18n("-c", "extract-strings", "-v", "-f", filepath.Join(inputFilesPath, "issue45.go"))
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "-f", filepath.Join(inputFilesPath, "issue45.go"))
 			Ω(session.ExitCode()).Should(Equal(0))
 		})
 
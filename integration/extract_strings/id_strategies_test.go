@@ -0,0 +1,128 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings --id-strategy", func() {
+	var (
+		outputPath        string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		_, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "extract_strings", "id_strategies")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	It("gives the messages a hash of their context and source text as ID", func() {
+		session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--meta", "--id-strategy", "hash", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath)
+		Ω(session.ExitCode()).Should(Equal(0))
+
+		CompareExpectedToGeneratedExtendedJson(
+			filepath.Join(expectedFilesPath, "hash", "app.go.extracted.json"),
+			filepath.Join(outputPath, "app.go.extracted.json"),
+		)
+	})
+
+	It("gives the messages an ID made of their package, function and first words", func() {
+		session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--meta", "--id-strategy", "semantic", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath)
+		Ω(session.ExitCode()).Should(Equal(0))
+
+		CompareExpectedToGeneratedExtendedJson(
+			filepath.Join(expectedFilesPath, "semantic", "app.go.extracted.json"),
+			filepath.Join(outputPath, "app.go.extracted.json"),
+		)
+	})
+
+	It("keeps the ID of a message whose source text is fixed", func() {
+		sourcePath := filepath.Join(outputPath, "app.go")
+		CopyFile(filepath.Join(inputFilesPath, "app.go"), sourcePath)
+
+		session := Runi18n("-c", "extract-strings", "--no-cache", "--meta", "--id-strategy", "hash", "-f", sourcePath, "-o", outputPath)
+		Ω(session.ExitCode()).Should(Equal(0))
+		Ω(ReadJsonExtended(filepath.Join(outputPath, "app.go.extracted.json"))["Invalid password!"]["id"]).Should(Equal("4b94bb1971"))
+
+		content, err := ioutil.ReadFile(sourcePath)
+		Ω(err).ShouldNot(HaveOccurred())
+		content = []byte(strings.Replace(string(content), `"Invalid password!"`, `"Invalid passwords!"`, 1))
+		err = ioutil.WriteFile(sourcePath, content, 0644)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		session = Runi18n("-c", "extract-strings", "--no-cache", "--meta", "--id-strategy", "hash", "-f", sourcePath, "-o", outputPath)
+		Ω(session.ExitCode()).Should(Equal(0))
+
+		extractedStrings := ReadJsonExtended(filepath.Join(outputPath, "app.go.extracted.json"))
+		Ω(extractedStrings["Invalid passwords!"]["id"]).Should(Equal("4b94bb1971"))
+		Ω(extractedStrings["Invalid password"]["id"]).Should(Equal("ee62cca1f6"))
+	})
+
+	It("gives unique semantic IDs to the messages of the files of a package", func() {
+		session := Runi18n("-c", "extract-strings", "--no-cache", "--id-strategy", "semantic", "-d", filepath.Join(inputFilesPath, "package"), "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
+		Ω(session.ExitCode()).Should(Equal(0))
+
+		fileNames, err := filepath.Glob(filepath.Join(outputPath, "*.en.json"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(fileNames).Should(HaveLen(2))
+
+		ids := make(map[string]string)
+		for _, fileName := range fileNames {
+			for id, translation := range ReadJson(fileName) {
+				ids[id] = translation
+			}
+		}
+		Ω(ids).Should(Equal(map[string]string{
+			"login.invalidPassword":      "Invalid password",
+			"login.invalidPassword_4b94": "Invalid password!",
+		}))
+	})
+
+	It("gives the same semantic IDs to the messages of cached files", func() {
+		for _, cacheStats := range []string{"Cache hits: 0, misses: 2", "Cache hits: 2, misses: 0"} {
+			os.RemoveAll(outputPath)
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--id-strategy", "semantic", "-d", filepath.Join(inputFilesPath, "package"), "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(string(session.Out.Contents())).Should(ContainSubstring(cacheStats))
+
+			fileNames, err := filepath.Glob(filepath.Join(outputPath, "*.en.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(fileNames).Should(HaveLen(2))
+
+			ids := make(map[string]string)
+			for _, fileName := range fileNames {
+				for id, translation := range ReadJson(fileName) {
+					ids[id] = translation
+				}
+			}
+			Ω(ids).Should(Equal(map[string]string{
+				"login.invalidPassword":      "Invalid password",
+				"login.invalidPassword_4b94": "Invalid password!",
+			}))
+		}
+	})
+
+	It("fails with an unknown ID strategy", func() {
+		session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "--id-strategy", "random", "-f", filepath.Join(inputFilesPath, "app.go"), "-o", outputPath)
+		Ω(session.ExitCode()).Should(Equal(1))
+		Ω(string(session.Out.Contents())).Should(ContainSubstring(`unknown ID strategy "random"`))
+	})
+})
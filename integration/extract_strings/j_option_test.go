@@ -0,0 +1,85 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings -j jobs", func() {
+	var (
+		inputFilesPath   string
+		sequentialOutput string
+		concurrentOutput string
+	)
+
+	BeforeEach(func() {
+		var err error
+		sequentialOutput, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		concurrentOutput, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		inputFilesPath = filepath.Join("..", "..", "test_fixtures", "extract_strings", "d_option", "input_files")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(sequentialOutput)
+		os.RemoveAll(concurrentOutput)
+	})
+
+	Context("When i18n4go4go is run with -j 1 and -j 8", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "--po", "--meta", "-d", inputFilesPath, "-r", "-o", sequentialOutput, "--ignore-regexp", "^[.]\\w+.go$", "-j", "1")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			session = Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "--po", "--meta", "-d", inputFilesPath, "-r", "-o", concurrentOutput, "--ignore-regexp", "^[.]\\w+.go$", "-j", "8")
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("generates byte identical files", func() {
+			fileInfos, err := ioutil.ReadDir(sequentialOutput)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(fileInfos).ShouldNot(BeEmpty())
+
+			concurrentFileInfos, err := ioutil.ReadDir(concurrentOutput)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(concurrentFileInfos).Should(HaveLen(len(fileInfos)))
+
+			for _, fileInfo := range fileInfos {
+				expected, err := ioutil.ReadFile(filepath.Join(sequentialOutput, fileInfo.Name()))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				generated, err := ioutil.ReadFile(filepath.Join(concurrentOutput, fileInfo.Name()))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(string(generated)).Should(Equal(string(expected)), fileInfo.Name())
+			}
+		})
+	})
+
+	Context("When i18n4go4go is run verbosely with -j 1 and -j 8", func() {
+		var sequentialSession, concurrentSession string
+
+		BeforeEach(func() {
+			session := Runi18n("-c", "extract-strings", "-v", "--no-cache", "-d", inputFilesPath, "-r", "-o", sequentialOutput, "--ignore-regexp", "^[.]\\w+.go$", "-j", "1")
+			Ω(session.ExitCode()).Should(Equal(0))
+			sequentialSession = strings.Split(strings.Replace(string(session.Out.Contents()), sequentialOutput, "<output>", -1), "Total time:")[0]
+
+			session = Runi18n("-c", "extract-strings", "-v", "--no-cache", "-d", inputFilesPath, "-r", "-o", concurrentOutput, "--ignore-regexp", "^[.]\\w+.go$", "-j", "8")
+			Ω(session.ExitCode()).Should(Equal(0))
+			concurrentSession = strings.Split(strings.Replace(string(session.Out.Contents()), concurrentOutput, "<output>", -1), "Total time:")[0]
+		})
+
+		It("prints the messages of the files in the same order", func() {
+			Ω(concurrentSession).Should(Equal(sequentialSession))
+			Ω(concurrentSession).Should(ContainSubstring("Extracted total of"))
+		})
+	})
+})
@@ -0,0 +1,58 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings in a module", func() {
+	var (
+		outputPath     string
+		inputFilesPath string
+		go111Module    string
+	)
+
+	BeforeEach(func() {
+		var err error
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		inputFilesPath = filepath.Join("..", "..", "test_fixtures", "extract_strings", "module_layout", "input_files")
+
+		// the fixture is a module whatever the mode the tests run in
+		go111Module = os.Getenv("GO111MODULE")
+		os.Setenv("GO111MODULE", "on")
+	})
+
+	AfterEach(func() {
+		os.Setenv("GO111MODULE", go111Module)
+		os.RemoveAll(outputPath)
+	})
+
+	generatedFiles := func(importPath, suffix string) []string {
+		matches, err := filepath.Glob(filepath.Join(outputPath, filepath.FromSlash(importPath), "*"+suffix))
+		Ω(err).ShouldNot(HaveOccurred())
+		return matches
+	}
+
+	Context("When i18n4go4go is run with the -d -r flags and --output-match-import", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "-d", inputFilesPath, "-r", "-o", outputPath, "--output-match-import", "--ignore-regexp", "^[.]\\w+.go$")
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("saves the strings of each package in the directory of its import path below the module path", func() {
+			Ω(generatedFiles("example.com/shop/cmd/shop", "main.go.en.json")).Should(HaveLen(1))
+		})
+
+		It("saves the strings of a module replaced by a local directory below its own module path", func() {
+			Ω(generatedFiles("example.com/money", "money.go.en.json")).Should(HaveLen(1))
+		})
+	})
+})
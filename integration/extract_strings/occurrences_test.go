@@ -0,0 +1,60 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings occurrences", func() {
+	var (
+		outputPath        string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		_, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "extract_strings", "occurrences")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files", "app.go")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	Context("When a string is used in several places", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--po", "--meta", "-f", inputFilesPath, "-o", outputPath)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("records every occurrence and the count in the extracted JSON", func() {
+			CompareExpectedToGeneratedExtendedJson(
+				filepath.Join(expectedFilesPath, "app.go.extracted.json"),
+				filepath.Join(outputPath, "app.go.extracted.json"),
+			)
+		})
+
+		It("writes a gettext reference line for every occurrence in the PO file", func() {
+			expected, err := ioutil.ReadFile(filepath.Join(expectedFilesPath, "app.go.en.po"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			generated, err := ioutil.ReadFile(filepath.Join(outputPath, "app.go.en.po"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(string(generated)).Should(Equal(string(expected)))
+		})
+	})
+})
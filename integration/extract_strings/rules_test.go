@@ -0,0 +1,120 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("extract-strings rules files", func() {
+	var (
+		outputPath        string
+		inputFilesPath    string
+		expectedFilesPath string
+		session           *Session
+	)
+
+	BeforeEach(func() {
+		var err error
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "extract_strings", "rules")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	Context("When the directories have rules files", func() {
+		BeforeEach(func() {
+			session = Runi18n("-c", "extract-strings", "-v", "--meta", "--no-cache",
+				"--rules", filepath.Join(inputFilesPath, "rules.yml"),
+				"-d", filepath.Join(inputFilesPath, "app"), "-r",
+				"--ignore-regexp", "^[.]\\w+.go$",
+				"-o", outputPath)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("applies the rules file and the files it extends", func() {
+			CompareExpectedToGeneratedExtendedJson(
+				filepath.Join(expectedFilesPath, "app.go.extracted.json"),
+				filepath.Join(outputPath, "app.go.extracted.json"),
+			)
+		})
+
+		It("adds the rules of a nested .i18n4go.yml to the inherited ones", func() {
+			CompareExpectedToGeneratedExtendedJson(
+				filepath.Join(expectedFilesPath, "nested.go.extracted.json"),
+				filepath.Join(outputPath, "nested.go.extracted.json"),
+			)
+
+			_, err := os.Stat(filepath.Join(outputPath, "nested_generated.go.extracted.json"))
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+
+		It("does not inherit the rules of the parent directories past a rules file with root: true", func() {
+			CompareExpectedToGeneratedExtendedJson(
+				filepath.Join(expectedFilesPath, "standalone.go.extracted.json"),
+				filepath.Join(outputPath, "standalone.go.extracted.json"),
+			)
+		})
+	})
+
+	Context("When the legacy excluded JSON file has keys i18n4go does not know", func() {
+		BeforeEach(func() {
+			session = Runi18n("-c", "extract-strings", "--meta", "--no-cache",
+				"-e", filepath.Join(inputFilesPath, "legacy", "excluded.json"),
+				"-f", filepath.Join(inputFilesPath, "legacy", "app.go"),
+				"-o", outputPath)
+		})
+
+		It("warns about them and applies its other rules", func() {
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(string(session.Out.Contents())).Should(ContainSubstring("WARNING ignoring the rule: " + filepath.Join(inputFilesPath, "legacy", "excluded.json") + ":7:3: unknown rule: comment"))
+
+			generated := ReadJsonExtended(filepath.Join(outputPath, "app.go.extracted.json"))
+			Ω(generated).Should(HaveKey("Hello"))
+			Ω(generated).ShouldNot(HaveKey("Ignored"))
+		})
+	})
+
+	Context("When the rules are invalid", func() {
+		BeforeEach(func() {
+			session = Runi18n("-c", "extract-strings", "--meta", "--no-cache",
+				"--rules", filepath.Join(inputFilesPath, "invalid", "rules.yml"),
+				"-f", filepath.Join(inputFilesPath, "invalid", "app.go"),
+				"-o", outputPath)
+		})
+
+		It("fails before extracting any file", func() {
+			Ω(session.ExitCode()).Should(Equal(1))
+
+			_, err := os.Stat(filepath.Join(outputPath, "app.go.extracted.json"))
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+
+		It("reports every invalid rule with its file and position", func() {
+			rulesFilename := filepath.Join(inputFilesPath, "invalid", "rules.yml")
+			output := string(session.Out.Contents())
+
+			Ω(output).Should(ContainSubstring(rulesFilename + ":12:1: unknown rule: excludedString"))
+			Ω(output).Should(ContainSubstring(rulesFilename + `:7:5: excludedRegexps: invalid regexp "[unclosed"`))
+			Ω(output).Should(ContainSubstring(rulesFilename + `:10:5: captureGroupSubstrings: regexp "no group" has no capturing group`))
+			Ω(output).Should(ContainSubstring(rulesFilename + `:17:5: detectors: unknown detector "colour"`))
+			Ω(output).Should(ContainSubstring(rulesFilename + `:20:5: enforcedFields: invalid enforced field "cli.Command"`))
+			Ω(output).Should(ContainSubstring(rulesFilename + `:24:11: assets: unknown asset mode "sections", expected file or paragraph`))
+			Ω(output).Should(ContainSubstring(rulesFilename + `:26:13: assets: invalid path "$.commands[x]"`))
+			Ω(output).Should(ContainSubstring(rulesFilename + ":2:5: extends: could not find missing.yml"))
+			Ω(output).Should(ContainSubstring(filepath.Join(inputFilesPath, "invalid", "invalid.json") + `:3:28: enforcedFuncs: "not a func" is not a function name`))
+		})
+	})
+})
@@ -40,7 +40,7 @@ var _ = Describe("extract-strings -s filePath", func() {
 
 	Context("When i18n4go4go is run with the -s flag", func() {
 		BeforeEach(func() {
-			session := Runi18n("-c", "extract-strings", "-v", "-f", inputFilesPath, "-o", outputPath, "-s", matchingGroupFilePath)
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "-f", inputFilesPath, "-o", outputPath, "-s", matchingGroupFilePath)
 
 			Ω(session.ExitCode()).Should(Equal(0))
 		})
@@ -0,0 +1,64 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings --tags", func() {
+	var (
+		outputPath     string
+		inputFilesPath string
+	)
+
+	BeforeEach(func() {
+		_, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		inputFilesPath = filepath.Join("..", "..", "test_fixtures", "extract_strings", "tags_option", "input_files", "app")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	generatedFiles := func(suffix string) []string {
+		matches, err := filepath.Glob(filepath.Join(outputPath, "*"+suffix))
+		Ω(err).ShouldNot(HaveOccurred())
+		return matches
+	}
+
+	Context("When i18n4go4go is run with the -d flag and no build tags", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("skips the files excluded by build constraints", func() {
+			Ω(generatedFiles("app.go.en.json")).Should(HaveLen(1))
+			Ω(generatedFiles("integration.go.en.json")).Should(BeEmpty())
+		})
+	})
+
+	Context("When i18n4go4go is run with the -d flag and --tags", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--tags", "integration", "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("extracts strings from the files selected by the build tags", func() {
+			Ω(generatedFiles("app.go.en.json")).Should(HaveLen(1))
+			Ω(generatedFiles("integration.go.en.json")).Should(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,69 @@
+package extract_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extract-strings from templates", func() {
+	var (
+		outputPath        string
+		fixturesPath      string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		_, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputPath, err = ioutil.TempDir("", "i18n4go4go")
+		Ω(err).ToNot(HaveOccurred())
+
+		fixturesPath = filepath.Join("..", "..", "test_fixtures", "extract_strings", "templates")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outputPath)
+	})
+
+	for _, fileName := range []string{"index.html", "email.tmpl", "app.go"} {
+		fileName := fileName
+
+		Context("When i18n4go4go is run with -f "+fileName, func() {
+			BeforeEach(func() {
+				session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--meta", "-e", filepath.Join(fixturesPath, "excluded.json"), "-f", filepath.Join(inputFilesPath, fileName), "-o", outputPath)
+
+				Ω(session.ExitCode()).Should(Equal(0))
+			})
+
+			It("extracts the text and the T strings of the templates with their positions", func() {
+				CompareExpectedToGeneratedExtendedJson(
+					filepath.Join(expectedFilesPath, fileName+".extracted.json"),
+					filepath.Join(outputPath, fileName+".extracted.json"),
+				)
+			})
+		})
+	}
+
+	Context("When i18n4go4go is run with -d", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c", "extract-strings", "--cache-dir", cacheDir, "-v", "--meta", "-e", filepath.Join(fixturesPath, "excluded.json"), "-d", inputFilesPath, "-o", outputPath, "--ignore-regexp", "^[.]\\w+.go$")
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("extracts the template files next to the go files", func() {
+			for _, fileName := range []string{"index.html", "email.tmpl", "app.go"} {
+				Ω(filepath.Join(outputPath, fileName+".extracted.json")).Should(BeAnExistingFile())
+			}
+		})
+	})
+})
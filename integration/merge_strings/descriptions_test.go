@@ -0,0 +1,50 @@
+package merge_strings_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("merge-strings descriptions", func() {
+	var (
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		_, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "merge_strings", "descriptions")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	Context("merging files with descriptions for the same strings", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c", "merge-strings", "-v", "-d", inputFilesPath, "--source-language", "en")
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		AfterEach(func() {
+			RemoveAllFiles(
+				GetFilePath(inputFilesPath, "en.all.json"),
+			)
+		})
+
+		It("keeps every distinct description", func() {
+			expectedBytes, err := ioutil.ReadFile(GetFilePath(expectedFilesPath, "en.all.json"))
+			Ω(err).Should(BeNil())
+
+			actualBytes, err := ioutil.ReadFile(GetFilePath(inputFilesPath, "en.all.json"))
+			Ω(err).Should(BeNil())
+
+			Ω(string(actualBytes)).Should(Equal(string(expectedBytes)))
+		})
+	})
+})
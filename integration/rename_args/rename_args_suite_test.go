@@ -0,0 +1,15 @@
+package rename_args_test
+
+import (
+	"github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestRenameArgs(t *testing.T) {
+	BeforeSuite(test_helpers.BuildExecutable)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RenameArgs Suite")
+}
@@ -0,0 +1,101 @@
+package rename_args_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rename-args -d dirName", func() {
+	var (
+		workDir           string
+		fixturesPath      string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		workDir, err = ioutil.TempDir("", "i18n4go_rename_args")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath = filepath.Join("..", "..", "test_fixtures", "rename_args")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+
+		for _, fileName := range []string{"app.go", "en_US.all.json", "fr_FR.all.json"} {
+			CopyFile(filepath.Join(inputFilesPath, fileName), filepath.Join(workDir, fileName))
+		}
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(workDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("with an arg names file", func() {
+		BeforeEach(func() {
+			err := os.Chmod(filepath.Join(workDir, "app.go"), 0600)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			session := Runi18n("-c", "rename-args", "-d", workDir, "--arg-names", filepath.Join(fixturesPath, "arg_names.json"), "-v")
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("names the args after their values in the code, the same way for every call of a string", func() {
+			CompareExpectedOutputToGeneratedOutput(
+				filepath.Join(expectedFilesPath, "app.go"),
+				filepath.Join(workDir, "app.go"),
+			)
+		})
+
+		It("keeps the permissions of the files it rewrites", func() {
+			fileInfo, err := os.Stat(filepath.Join(workDir, "app.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(fileInfo.Mode().Perm()).Should(Equal(os.FileMode(0600)))
+		})
+
+		It("renames the args of the IDs and translations of every language file", func() {
+			for _, fileName := range []string{"en_US.all.json", "fr_FR.all.json"} {
+				CompareExpectedToGeneratedTraslationJson(
+					filepath.Join(expectedFilesPath, fileName),
+					filepath.Join(workDir, fileName),
+				)
+			}
+		})
+	})
+
+	Context("with --dry-run", func() {
+		It("writes no file", func() {
+			session := Runi18n("-c", "rename-args", "-d", workDir, "--dry-run")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			for _, fileName := range []string{"app.go", "en_US.all.json", "fr_FR.all.json"} {
+				CompareExpectedOutputToGeneratedOutput(
+					filepath.Join(inputFilesPath, fileName),
+					filepath.Join(workDir, fileName),
+				)
+			}
+		})
+	})
+
+	Context("with an invalid arg names file", func() {
+		It("fails without renaming", func() {
+			argNamesFile := filepath.Join(workDir, "arg_names.json")
+			err := ioutil.WriteFile(argNamesFile, []byte(`{"Deleting {{.Arg0}} in org {{.Arg1}}": {"Arg0": "app name"}}`), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			session := Runi18n("-c", "rename-args", "-d", workDir, "--arg-names", argNamesFile)
+			Ω(session.ExitCode()).Should(Equal(1))
+
+			CompareExpectedOutputToGeneratedOutput(
+				filepath.Join(inputFilesPath, "app.go"),
+				filepath.Join(workDir, "app.go"),
+			)
+		})
+	})
+})
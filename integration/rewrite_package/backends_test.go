@@ -0,0 +1,214 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing/fstest"
+	"time"
+
+	"github.com/Liam-Williams/i18n4go/i18n"
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("rewrite-package --backend backend", func() {
+	var (
+		outputDir         string
+		inputFilesPath    string
+		expectedFilesPath string
+		session           *Session
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "rewrite_package", "backends")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("with go-i18n v2", func() {
+		BeforeEach(func() {
+			session = Runi18n("-c",
+				"rewrite-package",
+				"-d", inputFilesPath,
+				"-o", outputDir,
+				"--backend", "go-i18n-v2",
+				"--embed-dirname", "i18n/resources",
+				"-v",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("wraps the strings with Localizer.MustLocalize(), their args are the template data", func() {
+			CompareExpectedOutputToGeneratedOutput(
+				filepath.Join(expectedFilesPath, "go_i18n_v2", "app.go"),
+				filepath.Join(outputDir, "app.go"),
+			)
+		})
+
+		It("generates an i18n_init.go declaring the localizer of the embedded translations", func() {
+			content, err := ioutil.ReadFile(filepath.Join(outputDir, "i18n_init.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`"github.com/nicksnyder/go-i18n/v2/i18n"`))
+			Ω(string(content)).Should(ContainSubstring("var Localizer *i18n.Localizer"))
+			Ω(string(content)).Should(ContainSubstring("i18n4go.Translations(i18nResources, filepath.Join("))
+			Ω(string(content)).Should(ContainSubstring("&i18n.Message{ID: i18n4go.ReadableMessageID(id), Other: translation}"))
+		})
+	})
+
+	Context("with golang.org/x/text/message", func() {
+		BeforeEach(func() {
+			session = Runi18n("-c",
+				"rewrite-package",
+				"-d", inputFilesPath,
+				"-o", outputDir,
+				"--backend", "x-text",
+				"--embed-dirname", "i18n/resources",
+				"-v",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("wraps the strings with Printer.Sprintf(), their args are passed in order to the format", func() {
+			CompareExpectedOutputToGeneratedOutput(
+				filepath.Join(expectedFilesPath, "x_text", "app.go"),
+				filepath.Join(outputDir, "app.go"),
+			)
+		})
+
+		It("generates an i18n_init.go declaring the printer of the embedded translations", func() {
+			content, err := ioutil.ReadFile(filepath.Join(outputDir, "i18n_init.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`"golang.org/x/text/message"`))
+			Ω(string(content)).Should(ContainSubstring("var Printer *message.Printer"))
+			Ω(string(content)).Should(ContainSubstring(`i18n.Translations(i18nResources, filepath.Join(`))
+		})
+
+		It("prints the message of a context without translation as is", func() {
+			moduleDir, err := ioutil.TempDir("", "i18n4go_fallback")
+			Ω(err).ShouldNot(HaveOccurred())
+			defer os.RemoveAll(moduleDir)
+
+			appGo, err := ioutil.ReadFile(filepath.Join(outputDir, "app.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// the printer of an empty catalog, none of the messages has a translation
+			files := map[string]string{
+				"go.mod":                       "module fallback\n\ngo 1.18\n\nrequire golang.org/x/text v0.14.0\n",
+				"main.go":                      "package main\n\nimport \"fallback/app\"\n\nfunc main() {\n\tapp.Push(\"myapp\", 2)\n}\n",
+				filepath.Join("app", "app.go"): string(appGo),
+				filepath.Join("app", "printer.go"): `package app
+
+import (
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+var Printer = message.NewPrinter(language.French, message.Catalog(catalog.NewBuilder()))
+`,
+			}
+			for fileName, content := range files {
+				err = os.MkdirAll(filepath.Dir(filepath.Join(moduleDir, fileName)), 0755)
+				Ω(err).ShouldNot(HaveOccurred())
+				err = ioutil.WriteFile(filepath.Join(moduleDir, fileName), []byte(content), 0644)
+				Ω(err).ShouldNot(HaveOccurred())
+			}
+
+			tidySession := runGo(moduleDir, "mod", "tidy")
+			if tidySession.ExitCode() != 0 {
+				Skip("cannot download golang.org/x/text")
+			}
+
+			session = runGo(moduleDir, "run", ".")
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(string(session.Out.Contents())).Should(ContainSubstring("Pushing app\nOpen\nUpload at 100% speed\n"))
+			Ω(string(session.Out.Contents())).ShouldNot(ContainSubstring("\x04"))
+		})
+	})
+
+	It("fails without --embed-dirname, the translations would be looked up in the dir the binaries are run from", func() {
+		for _, backend := range []string{"go-i18n-v2", "x-text"} {
+			session = Runi18n("-c",
+				"rewrite-package",
+				"-d", inputFilesPath,
+				"-o", outputDir,
+				"--backend", backend,
+				"-v",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(1))
+			Ω(string(session.Out.Contents())).Should(ContainSubstring("the " + backend + " backend requires --embed-dirname"))
+			Ω(filepath.Join(outputDir, "app.go")).ShouldNot(BeAnExistingFile())
+		}
+	})
+
+	It("fails with an unknown backend", func() {
+		session = Runi18n("-c",
+			"rewrite-package",
+			"-d", inputFilesPath,
+			"-o", outputDir,
+			"--backend", "gettext",
+			"-v",
+		)
+
+		Ω(session.ExitCode()).Should(Equal(1))
+		Ω(string(session.Out.Contents())).Should(ContainSubstring("unknown backend gettext"))
+	})
+})
+
+// runGo runs the go command in the module dir, downloading its modules when needed
+func runGo(dir string, args ...string) *Session {
+	command := exec.Command("go", args...)
+	command.Dir = dir
+	command.Env = append(os.Environ(), "GO111MODULE=on", "GOFLAGS=-mod=mod")
+
+	session, err := Start(command, GinkgoWriter, GinkgoWriter)
+	Ω(err).ShouldNot(HaveOccurred())
+	session.Wait(2 * time.Minute)
+	return session
+}
+
+var _ = Describe("i18n.Translations", func() {
+	It("loads the other form of the plural translations of go-i18n v1", func() {
+		fsys := fstest.MapFS{
+			"resources/fr/app/fr_FR.all.json": &fstest.MapFile{Data: []byte(`[
+  {"id": "Open", "translation": "Ouvrir"},
+  {"id": "{{.Count}} apps", "translation": {"one": "{{.Count}} application", "other": "{{.Count}} applications"}}
+]`)},
+		}
+
+		translations, err := i18n.Translations(fsys, "app", "resources")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(translations["fr_FR"]).Should(Equal(map[string]string{
+			"Open":            "Ouvrir",
+			"{{.Count}} apps": "{{.Count}} applications",
+		}))
+	})
+
+	It("fails on a plural translation without other form", func() {
+		fsys := fstest.MapFS{
+			"resources/fr/app/fr_FR.all.json": &fstest.MapFile{Data: []byte(`[{"id": "{{.Count}} apps", "translation": {"one": "{{.Count}} application"}}]`)},
+		}
+
+		_, err := i18n.Translations(fsys, "app", "resources")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring("the plural translation has no other form"))
+	})
+})
@@ -0,0 +1,67 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rewrite-package with comments", func() {
+	var (
+		outputDir         string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "rewrite_package", "comments")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("only rewrites the spans of the strings and keeps the comments and the layout of the file", func() {
+		session := Runi18n("-c",
+			"rewrite-package",
+			"-f", filepath.Join(inputFilesPath, "commands.go"),
+			"-o", outputDir,
+			"-v",
+		)
+		Ω(session.ExitCode()).Should(Equal(0))
+
+		CompareExpectedOutputToGeneratedOutput(
+			filepath.Join(expectedFilesPath, "commands.go"),
+			filepath.Join(outputDir, "commands.go"),
+		)
+	})
+
+	It("keeps the comments and the layout of the file when T() is called with IDs", func() {
+		session := Runi18n("-c",
+			"rewrite-package",
+			"-f", filepath.Join(inputFilesPath, "commands.go"),
+			"-o", outputDir,
+			"--id-strategy", "hash",
+			"-v",
+		)
+		Ω(session.ExitCode()).Should(Equal(0))
+
+		CompareExpectedOutputToGeneratedOutput(
+			filepath.Join(expectedFilesPath, "hash_ids", "commands.go"),
+			filepath.Join(outputDir, "commands.go"),
+		)
+	})
+})
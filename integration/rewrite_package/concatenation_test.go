@@ -0,0 +1,52 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rewrite-package with concatenated strings", func() {
+	var (
+		outputDir         string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "rewrite_package", "concatenation")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+
+		session := Runi18n("-c",
+			"rewrite-package",
+			"-f", filepath.Join(inputFilesPath, "test.go"),
+			"-o", outputDir,
+			"-v",
+		)
+
+		Ω(session.ExitCode()).Should(Equal(0))
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("wraps each run of adjacent literals with one T() call", func() {
+		CompareExpectedOutputToGeneratedOutput(
+			filepath.Join(expectedFilesPath, "test.go"),
+			filepath.Join(outputDir, "test.go"),
+		)
+	})
+})
@@ -0,0 +1,53 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rewrite-package with message contexts", func() {
+	var (
+		outputDir         string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "rewrite_package", "contexts")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+
+		session := Runi18n("-c",
+			"rewrite-package",
+			"-f", filepath.Join(inputFilesPath, "test.go"),
+			"-o", outputDir,
+			"--i18n-strings-filename", filepath.Join(inputFilesPath, "strings.json"),
+			"-v",
+		)
+
+		Ω(session.ExitCode()).Should(Equal(0))
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("wraps the strings with a context directive with TC() when the strings file has them in that context", func() {
+		CompareExpectedOutputToGeneratedOutput(
+			filepath.Join(expectedFilesPath, "test.go"),
+			filepath.Join(outputDir, "test.go"),
+		)
+	})
+})
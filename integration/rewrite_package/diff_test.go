@@ -0,0 +1,120 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("rewrite-package --diff, --check and --patch", func() {
+	var (
+		outputDir         string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "rewrite_package", "diff")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+
+		CopyFile(filepath.Join(inputFilesPath, "app.go"), filepath.Join(outputDir, "app.go"))
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	rewrite := func(args ...string) *Session {
+		return Runi18n(append([]string{"-c", "rewrite-package",
+			"-f", filepath.Join(outputDir, "app.go"),
+			"--root-path", outputDir,
+		}, args...)...)
+	}
+
+	Context("--diff", func() {
+		It("prints a unified diff of the changes and leaves the files untouched", func() {
+			session := rewrite("--diff")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			expectedDiff, err := ioutil.ReadFile(filepath.Join(expectedFilesPath, "app.go.diff"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(session.Out.Contents())).Should(ContainSubstring(string(expectedDiff)))
+			Ω(string(session.Out.Contents())).Should(ContainSubstring("--- /dev/null\n+++ b/i18n_init.go\n"))
+
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(inputFilesPath, "app.go"), filepath.Join(outputDir, "app.go"))
+			Ω(filepath.Join(outputDir, "i18n_init.go")).ShouldNot(BeAnExistingFile())
+		})
+
+		It("prints the messages of -v to stderr so that stdout is a clean patch", func() {
+			session := rewrite("--diff", "-v")
+			Ω(session.ExitCode()).Should(Equal(0))
+			Ω(string(session.Out.Contents())).ShouldNot(ContainSubstring("saving file to path"))
+			Ω(string(session.Err.Contents())).Should(ContainSubstring("saving file to path"))
+
+			patchFile := filepath.Join(outputDir, "changes.patch")
+			err := ioutil.WriteFile(patchFile, session.Out.Contents(), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			gitApply := exec.Command("git", "apply", "changes.patch")
+			gitApply.Dir = outputDir
+			gitApply.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+filepath.Dir(outputDir))
+			output, err := gitApply.CombinedOutput()
+			Ω(err).ShouldNot(HaveOccurred(), string(output))
+
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "app.go"), filepath.Join(outputDir, "app.go"))
+		})
+	})
+
+	Context("--check", func() {
+		It("fails when a file would change", func() {
+			session := rewrite("--check")
+			Ω(session.ExitCode()).Should(Equal(1))
+			Ω(session).Should(Say("i18n4go: would rewrite i18n_init.go"))
+			Ω(session).Should(Say("i18n4go: would rewrite app.go"))
+
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(inputFilesPath, "app.go"), filepath.Join(outputDir, "app.go"))
+		})
+
+		It("succeeds when the files are already rewritten", func() {
+			Ω(rewrite().ExitCode()).Should(Equal(0))
+
+			session := rewrite("--check")
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+	})
+
+	Context("--patch", func() {
+		It("writes a patch that git apply accepts", func() {
+			patchFile := filepath.Join(outputDir, "changes.patch")
+			session := rewrite("--patch", patchFile)
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(inputFilesPath, "app.go"), filepath.Join(outputDir, "app.go"))
+
+			// git must not look for the repository of the fixtures, the patch is applied like a plain patch
+			gitApply := exec.Command("git", "apply", "changes.patch")
+			gitApply.Dir = outputDir
+			gitApply.Env = append(os.Environ(), "GIT_CEILING_DIRECTORIES="+filepath.Dir(outputDir))
+			output, err := gitApply.CombinedOutput()
+			Ω(err).ShouldNot(HaveOccurred(), string(output))
+
+			CompareExpectedOutputToGeneratedOutput(filepath.Join(expectedFilesPath, "app.go"), filepath.Join(outputDir, "app.go"))
+			Ω(filepath.Join(outputDir, "i18n_init.go")).Should(BeAnExistingFile())
+		})
+	})
+})
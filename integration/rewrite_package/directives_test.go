@@ -0,0 +1,63 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rewrite-package with directives", func() {
+	var (
+		outputDir         string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "rewrite_package", "directives")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+
+		// the i18n strings file is updated in place
+		CopyFile(filepath.Join(inputFilesPath, "strings.json"), filepath.Join(outputDir, "strings.json"))
+
+		session := Runi18n("-c",
+			"rewrite-package",
+			"-f", filepath.Join(inputFilesPath, "test.go"),
+			"-o", outputDir,
+			"--i18n-strings-filename", filepath.Join(outputDir, "strings.json"),
+			"-v",
+		)
+
+		Ω(session.ExitCode()).Should(Equal(0))
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("does not wrap the ignored strings and wraps the ones forced by translate", func() {
+		CompareExpectedOutputToGeneratedOutput(
+			filepath.Join(expectedFilesPath, "test.go"),
+			filepath.Join(outputDir, "test.go"),
+		)
+	})
+
+	It("adds the strings forced by translate to the i18n strings file", func() {
+		CompareExpectedToGeneratedTraslationJson(
+			filepath.Join(expectedFilesPath, "strings.json"),
+			filepath.Join(outputDir, "strings.json"),
+		)
+	})
+})
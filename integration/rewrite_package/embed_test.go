@@ -0,0 +1,71 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rewrite-package --embed-dirname dirname", func() {
+	var (
+		outputDir      string
+		inputFilesPath string
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		inputFilesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package", "embed", "input_files")
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("generates an i18n_init.go that embeds the locale dir and loads the translations from it", func() {
+		session := Runi18n("-c",
+			"rewrite-package",
+			"-d", inputFilesPath,
+			"-o", outputDir,
+			"--embed-dirname", "i18n/resources",
+			"-v",
+		)
+
+		Ω(session.ExitCode()).Should(Equal(0))
+
+		content, err := ioutil.ReadFile(filepath.Join(outputDir, "i18n_init.go"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(content)).Should(ContainSubstring("//go:embed i18n/resources\nvar i18nResources embed.FS"))
+		Ω(string(content)).Should(ContainSubstring(`T = i18n.InitFS(i18nResources, filepath.Join(`))
+		Ω(string(content)).Should(ContainSubstring(`), "i18n/resources")`))
+		Ω(string(content)).ShouldNot(ContainSubstring("GetResourcesPath"))
+
+		Ω(string(session.Out.Contents())).Should(ContainSubstring("WARNING the package app does not compile without the locale dir " + filepath.Join(outputDir, "i18n", "resources")))
+	})
+
+	It("fails with a locale dir outside of the package", func() {
+		session := Runi18n("-c",
+			"rewrite-package",
+			"-d", inputFilesPath,
+			"-o", outputDir,
+			"--embed-dirname", "../resources",
+			"-v",
+		)
+
+		Ω(session.ExitCode()).Should(Equal(1))
+		Ω(string(session.Out.Contents())).Should(ContainSubstring("invalid --embed-dirname ../resources"))
+
+		fileInfos, err := ioutil.ReadDir(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(fileInfos).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,60 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rewrite-package --id-strategy", func() {
+	var (
+		outputDir         string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "rewrite_package", "id_strategies")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+
+		session := Runi18n("-c",
+			"rewrite-package",
+			"-f", filepath.Join(inputFilesPath, "app.go"),
+			"-o", outputDir,
+			"--id-strategy", "semantic",
+			"-v",
+		)
+
+		Ω(session.ExitCode()).Should(Equal(0))
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("wraps the strings with T() called with their semantic IDs", func() {
+		CompareExpectedOutputToGeneratedOutput(
+			filepath.Join(expectedFilesPath, "app.go"),
+			filepath.Join(outputDir, "app.go"),
+		)
+	})
+
+	It("saves the source text of the IDs to the source language file", func() {
+		CompareExpectedToGeneratedTraslationJson(
+			filepath.Join(expectedFilesPath, "en.all.json"),
+			filepath.Join(outputDir, "en.all.json"),
+		)
+	})
+})
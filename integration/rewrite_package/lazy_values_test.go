@@ -0,0 +1,90 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("rewrite-package -d dirname with constants and package level variables", func() {
+	var (
+		outputDir         string
+		inputFilesPath    string
+		expectedFilesPath string
+		session           *Session
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "rewrite_package", "lazy_values")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("when T() is assigned by the init() of the package", func() {
+		BeforeEach(func() {
+			session = Runi18n("-c",
+				"rewrite-package",
+				"-d", inputFilesPath,
+				"-o", outputDir,
+				"-v",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("turns the constants and the variables initialized before T() into funcs and the local constants into variables", func() {
+			CompareExpectedOutputToGeneratedOutput(
+				filepath.Join(expectedFilesPath, "app.go"),
+				filepath.Join(outputDir, "app.go"),
+			)
+		})
+
+		It("reports the constants and variables it cannot make safe with their positions", func() {
+			output := string(session.Out.Contents())
+			Ω(output).Should(ContainSubstring("unfixable constant Banner at " + filepath.Join(inputFilesPath, "app.go") + ":20:7"))
+			Ω(output).Should(ContainSubstring("it is exported, other packages may use it"))
+			Ω(output).Should(ContainSubstring("unfixable constant farewell at " + filepath.Join(inputFilesPath, "app.go") + ":22:7"))
+			Ω(output).Should(ContainSubstring("it is used in a constant expression"))
+			Ω(output).Should(ContainSubstring("unfixable variable prompt at " + filepath.Join(inputFilesPath, "app.go") + ":29:5"))
+			Ω(output).Should(ContainSubstring("it is assigned"))
+		})
+	})
+
+	Context("with a central translator package", func() {
+		BeforeEach(func() {
+			session = Runi18n("-c",
+				"rewrite-package",
+				"-d", inputFilesPath,
+				"-o", outputDir,
+				"-q", "i18n",
+				"--translator-package", "github.com/Liam-Williams/i18n4go/test_fixtures/rewrite_package/qualifier/translator",
+				"-v",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("keeps the package level variables, the translator package is initialized first", func() {
+			CompareExpectedOutputToGeneratedOutput(
+				filepath.Join(expectedFilesPath, "translator", "app.go"),
+				filepath.Join(outputDir, "app.go"),
+			)
+		})
+	})
+})
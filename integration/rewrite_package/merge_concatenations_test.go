@@ -0,0 +1,67 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("rewrite-package --merge-concatenations", func() {
+	var (
+		outputDir         string
+		expectedFilesPath string
+		session           *Session
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "rewrite_package", "merge_concatenations")
+		inputFilesPath := filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+
+		// the strings file is updated in place
+		CopyFile(filepath.Join(inputFilesPath, "strings.json"), filepath.Join(outputDir, "strings.json"))
+
+		session = Runi18n("-c",
+			"rewrite-package",
+			"-f", filepath.Join(inputFilesPath, "app.go"),
+			"-o", outputDir,
+			"--i18n-strings-filename", filepath.Join(outputDir, "strings.json"),
+			"--merge-concatenations",
+			"-v",
+		)
+
+		Ω(session.ExitCode()).Should(Equal(0))
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("wraps the concatenations mixing strings and expressions with one templated message", func() {
+		CompareExpectedOutputToGeneratedOutput(
+			filepath.Join(expectedFilesPath, "app.go"),
+			filepath.Join(outputDir, "app.go"),
+		)
+	})
+
+	It("replaces the merged strings with the templated messages in the strings file, but those also wrapped on their own", func() {
+		CompareExpectedToGeneratedTraslationJson(
+			filepath.Join(expectedFilesPath, "strings.json"),
+			filepath.Join(outputDir, "strings.json"),
+		)
+
+		Ω(string(session.Out.Contents())).Should(ContainSubstring(`removing the fragment of a merged concatenation: " from org "`))
+	})
+})
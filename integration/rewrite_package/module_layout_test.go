@@ -0,0 +1,54 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rewrite-package in a module", func() {
+	var (
+		outputDir      string
+		inputFilesPath string
+		go111Module    string
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		inputFilesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package", "module_layout", "input_files")
+
+		// the fixture is a module whatever the mode the tests run in
+		go111Module = os.Getenv("GO111MODULE")
+		os.Setenv("GO111MODULE", "on")
+	})
+
+	AfterEach(func() {
+		os.Setenv("GO111MODULE", go111Module)
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("replaces __FULL_IMPORT_PATH__ with the import path of the package relative to the module root", func() {
+		session := Runi18n("-c",
+			"rewrite-package",
+			"-d", filepath.Join(inputFilesPath, "cmd", "shop"),
+			"-o", outputDir,
+			"-v",
+		)
+
+		Ω(session.ExitCode()).Should(Equal(0))
+
+		content, err := ioutil.ReadFile(filepath.Join(outputDir, "i18n_init.go"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(content)).Should(ContainSubstring(`T = i18n.Init(filepath.Join("cmd", "shop"), i18n.GetResourcesPath())`))
+	})
+})
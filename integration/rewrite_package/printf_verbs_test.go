@@ -0,0 +1,82 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rewrite-package -d dirname with printf verbs", func() {
+	var (
+		outputDir         string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "rewrite_package", "printf_verbs")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("with verbs having flags, width, precision and explicit arg indexes", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c",
+				"rewrite-package",
+				"-d", inputFilesPath,
+				"-o", outputDir,
+				"-v",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("maps each verb to its arg and pre-formats the args of the verbs a template does not print alike", func() {
+			for _, fileName := range []string{"report.go", "greet.go", "progress.go"} {
+				CompareExpectedOutputToGeneratedOutput(
+					filepath.Join(expectedFilesPath, fileName),
+					filepath.Join(outputDir, fileName),
+				)
+			}
+		})
+	})
+
+	Context("with a qualifier and a translator package", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c",
+				"rewrite-package",
+				"-d", inputFilesPath,
+				"-o", outputDir,
+				"-q", "i18n",
+				"--translator-package", "github.com/Liam-Williams/i18n4go/test_fixtures/rewrite_package/qualifier/translator",
+				"-v",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("imports package fmt along with the translator package", func() {
+			for _, fileName := range []string{"report.go", "greet.go"} {
+				CompareExpectedOutputToGeneratedOutput(
+					filepath.Join(expectedFilesPath, "translator", fileName),
+					filepath.Join(outputDir, fileName),
+				)
+			}
+		})
+	})
+})
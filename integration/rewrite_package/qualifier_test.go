@@ -0,0 +1,121 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rewrite-package -q qualifier --translator-package importPath", func() {
+	var (
+		outputDir         string
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	const TRANSLATOR_PACKAGE = "github.com/Liam-Williams/i18n4go/test_fixtures/rewrite_package/qualifier/translator"
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "rewrite_package", "qualifier")
+		inputFilesPath = filepath.Join(fixturesPath, "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "expected_output")
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("with a central translator package", func() {
+		BeforeEach(func() {
+			session := Runi18n("-c",
+				"rewrite-package",
+				"-d", inputFilesPath,
+				"-o", outputDir,
+				"-q", "i18n",
+				"--translator-package", TRANSLATOR_PACKAGE,
+				"-v",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("wraps the strings with qualified calls and imports the translator package", func() {
+			for _, fileName := range []string{"app.go", "single.go", "none.go", "aliased.go", "conflict.go"} {
+				CompareExpectedOutputToGeneratedOutput(
+					filepath.Join(expectedFilesPath, fileName),
+					filepath.Join(outputDir, fileName),
+				)
+			}
+		})
+
+		It("generates no i18n_init.go", func() {
+			Ω(filepath.Join(outputDir, "i18n_init.go")).ShouldNot(BeAnExistingFile())
+		})
+
+		It("compiles without any edits", func() {
+			goBuild := exec.Command("go", "build", ".")
+			goBuild.Dir = outputDir
+			output, err := goBuild.CombinedOutput()
+			Ω(err).ShouldNot(HaveOccurred(), string(output))
+		})
+	})
+
+	Context("without translator package", func() {
+		It("fails for a file that does not import a package named like the qualifier", func() {
+			session := Runi18n("-c",
+				"rewrite-package",
+				"-f", filepath.Join(inputFilesPath, "single.go"),
+				"-o", outputDir,
+				"-q", "i18n",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(1))
+		})
+
+		It("calls T() of the package imported with the qualifier", func() {
+			session := Runi18n("-c",
+				"rewrite-package",
+				"-f", filepath.Join(inputFilesPath, "aliased.go"),
+				"-o", outputDir,
+				"-q", "tr",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+			CompareExpectedOutputToGeneratedOutput(
+				filepath.Join(expectedFilesPath, "aliased.go"),
+				filepath.Join(outputDir, "aliased.go"),
+			)
+			Ω(filepath.Join(outputDir, "i18n_init.go")).Should(BeAnExistingFile())
+		})
+
+		It("leaves the files without strings as they are, whatever they import", func() {
+			fixturesPath := filepath.Join("..", "..", "test_fixtures", "rewrite_package", "qualifier_only")
+			session := Runi18n("-c",
+				"rewrite-package",
+				"-d", filepath.Join(fixturesPath, "input_files"),
+				"-o", outputDir,
+				"-q", "tr",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+			for _, fileName := range []string{"aliased.go", "untouched.go"} {
+				CompareExpectedOutputToGeneratedOutput(
+					filepath.Join(fixturesPath, "expected_output", fileName),
+					filepath.Join(outputDir, fileName),
+				)
+			}
+		})
+	})
+})
@@ -0,0 +1,109 @@
+package rewrite_package_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("rewrite-package verifying the rewritten packages compile", func() {
+	var (
+		outputDir      string
+		inputFilesPath string
+	)
+
+	BeforeEach(func() {
+		dir, err := os.Getwd()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		outputDir, err = ioutil.TempDir(filepath.Join(dir, "..", ".."), "i18n4go_integration")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		inputFilesPath = filepath.Join("..", "..", "test_fixtures", "rewrite_package", "transaction", "input_files")
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(outputDir)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("when a rewritten package does not compile", func() {
+		It("rolls back the rewrite of every package and prints the type errors with their positions", func() {
+			session := Runi18n("-c",
+				"rewrite-package",
+				"-d", inputFilesPath,
+				"-o", outputDir,
+				"-r",
+				"-v",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(1))
+
+			output := string(session.Out.Contents())
+			Ω(output).Should(ContainSubstring(filepath.Join("colors", "colors.go") + ":11:15: cannot use"))
+			Ω(output).Should(ContainSubstring("the rewrite of 4 files was rolled back"))
+
+			fileInfos, err := ioutil.ReadDir(outputDir)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(fileInfos).Should(BeEmpty())
+		})
+	})
+
+	Context("when the rewritten package compiles", func() {
+		var originalContent []byte
+
+		BeforeEach(func() {
+			var err error
+			originalContent, err = ioutil.ReadFile(filepath.Join(inputFilesPath, "app.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			CopyFile(filepath.Join(inputFilesPath, "app.go"), filepath.Join(outputDir, "app.go"))
+
+			session := Runi18n("-c",
+				"rewrite-package",
+				"-d", outputDir,
+				"-v",
+			)
+
+			Ω(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("rewrites the files in place and saves the undo journal", func() {
+			content, err := ioutil.ReadFile(filepath.Join(outputDir, "app.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`fmt.Println(T("Hello"), name)`))
+
+			Ω(filepath.Join(outputDir, "i18n_init.go")).Should(BeARegularFile())
+			Ω(filepath.Join(outputDir, ".i18n4go_rewrite_journal.json")).Should(BeARegularFile())
+		})
+
+		It("restores the sources and removes the created files with rewrite-undo", func() {
+			session := Runi18n("-c", "rewrite-undo", "-d", outputDir, "-v")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			content, err := ioutil.ReadFile(filepath.Join(outputDir, "app.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(Equal(originalContent))
+
+			Ω(filepath.Join(outputDir, "i18n_init.go")).ShouldNot(BeAnExistingFile())
+			Ω(filepath.Join(outputDir, ".i18n4go_rewrite_journal.json")).ShouldNot(BeAnExistingFile())
+		})
+
+		It("restores nothing with rewrite-undo when a file changed since the rewrite", func() {
+			appFileName := filepath.Join(outputDir, "app.go")
+			content, err := ioutil.ReadFile(appFileName)
+			Ω(err).ShouldNot(HaveOccurred())
+			err = ioutil.WriteFile(appFileName, append(content, []byte("\n// edited\n")...), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			session := Runi18n("-c", "rewrite-undo", "-d", outputDir, "-v")
+			Ω(session.ExitCode()).Should(Equal(1))
+			Ω(string(session.Out.Contents())).Should(ContainSubstring("changed since the rewrite"))
+
+			Ω(filepath.Join(outputDir, "i18n_init.go")).Should(BeARegularFile())
+		})
+	})
+})
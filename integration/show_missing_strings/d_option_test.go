@@ -40,6 +40,20 @@ var _ = Describe("show-missing-strings -d dirName", func() {
 		})
 	})
 
+	Context("When the strings missing from the json resource are ignored by directives", func() {
+		BeforeEach(func() {
+			languageFilePath := filepath.Join(inputFilesPath, "ignored_strings", "app.go.en.json")
+			codeDirPath := filepath.Join(inputFilesPath, "ignored_strings", "code")
+			session = Runi18n("-c", "show-missing-strings", "-d", codeDirPath, "--i18n-strings-filename", languageFilePath)
+
+			Eventually(session.ExitCode()).Should(Equal(0))
+		})
+
+		It("Should output nothing", func() {
+			Ω(session).Should(Say(""))
+		})
+	})
+
 	Context("When there are strings missing from the json resource", func() {
 		BeforeEach(func() {
 			languageFilePath := filepath.Join(inputFilesPath, "missing_strings", "app.go.en.json")
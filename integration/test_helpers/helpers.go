@@ -11,12 +11,15 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gexec"
 )
 
+const COMMAND_TIMEOUT = 30 * time.Second
+
 func CompareExpectedToGeneratedPo(expectedFilePath string, generatedFilePath string) {
 	expectedTranslation := ReadPo(expectedFilePath)
 	generatedTranslation := ReadPo(generatedFilePath)
@@ -61,7 +64,8 @@ func RunCommand(cmd string, args ...string) *Session {
 	command := exec.Command(cmd, args...)
 	session, err := Start(command, GinkgoWriter, GinkgoWriter)
 	Ω(err).ShouldNot(HaveOccurred())
-	session.Wait()
+	// loading and type checking packages with the go command can take longer than the default second
+	session.Wait(COMMAND_TIMEOUT)
 	return session
 }
 
@@ -136,6 +140,9 @@ func ReadJsonExtended(fileName string) map[string]map[string]string {
 				dataMap[key] = val.(string)
 			case float64:
 				dataMap[key] = fmt.Sprintf("%v", int(val.(float64)))
+			case []interface{}:
+				jsonVal, _ := json.Marshal(val)
+				dataMap[key] = string(jsonVal)
 			default:
 				fmt.Println("We did something wrong", key)
 			}
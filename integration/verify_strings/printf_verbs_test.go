@@ -0,0 +1,50 @@
+package verify_strings_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/Liam-Williams/i18n4go/integration/test_helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("verify-strings -f fileName with printf format strings", func() {
+	var (
+		inputFilesPath    string
+		expectedFilesPath string
+	)
+
+	BeforeEach(func() {
+		fixturesPath := filepath.Join("..", "..", "test_fixtures", "verify_strings")
+		inputFilesPath = filepath.Join(fixturesPath, "printf_verbs", "input_files")
+		expectedFilesPath = filepath.Join(fixturesPath, "printf_verbs", "expected_output")
+	})
+
+	AfterEach(func() {
+		RemoveAllFiles(
+			GetFilePath(expectedFilesPath, "apps.go.de.json.invalid.diff.json"),
+		)
+	})
+
+	Context("with translations formatting the args alike, in another order with explicit indexes", func() {
+		It("passes verifications", func() {
+			session := Runi18n("-c", "verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "apps.go.en.json"), "--languages", "\"fr\"", "-o", expectedFilesPath, "--source-language", "en")
+			Ω(session.ExitCode()).Should(Equal(0))
+
+			_, err := os.Stat(GetFilePath(expectedFilesPath, "apps.go.fr.json.invalid.diff.json"))
+			Ω(os.IsNotExist(err)).Should(Equal(true))
+		})
+	})
+
+	Context("with translations dropping the flags and width of verbs", func() {
+		It("generates invalid diff file", func() {
+			session := Runi18n("-c", "verify-strings", "-v", "-f", filepath.Join(inputFilesPath, "apps.go.en.json"), "--languages", "\"de\"", "-o", expectedFilesPath, "--source-language", "en")
+			Ω(session.ExitCode()).Should(Equal(1))
+
+			fileInfo, err := os.Stat(GetFilePath(expectedFilesPath, "apps.go.de.json.invalid.diff.json"))
+			Ω(err).Should(BeNil())
+			Ω(fileInfo.Name()).Should(Equal("apps.go.de.json.invalid.diff.json"))
+		})
+	})
+})
@@ -0,0 +1,7 @@
+//i18n4go:ignore-file
+
+package code
+
+func experimental() string {
+	return T("An experimental feature")
+}
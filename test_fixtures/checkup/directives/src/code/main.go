@@ -0,0 +1,8 @@
+package code
+
+import "fmt"
+
+func main() {
+	fmt.Println(T("Translated hello world!"))
+	fmt.Println(T("Work in progress, not translated yet")) //i18n4go:ignore
+}
@@ -0,0 +1,9 @@
+package code
+
+import "fmt"
+
+func main() {
+	fmt.Println(T("ee62cca1f6"))
+	fmt.Println(T("4b94bb1971"))
+	fmt.Println(T("Welcome"))
+}
@@ -0,0 +1,21 @@
+package app
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = "Usage: app [options] " +
+	"<file>"
+
+const errorPrefix = "Error: "
+
+func Run(reason string) {
+	fmt.Println(usage)
+
+	msg := "Failed to " + "connect: " + reason
+	fmt.Println(msg)
+
+	fmt.Fprintln(os.Stderr, errorPrefix+"file not found")
+	fmt.Println(reason + " was " + "rejected")
+}
@@ -0,0 +1,17 @@
+package app
+
+import "fmt"
+
+func TC(context, translationID string, args ...interface{}) string {
+	return translationID
+}
+
+func menu() {
+	fmt.Println(TC("menu", "Open"))
+	fmt.Println(TC("door", "Open"))
+	fmt.Println("Open")
+
+	fmt.Println("Close" /*i18n4go:context file*/)
+
+	fmt.Println("Close") //i18n4go:context window
+}
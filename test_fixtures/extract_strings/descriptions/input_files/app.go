@@ -0,0 +1,17 @@
+package app
+
+import "fmt"
+
+func Push(name string, instances int) {
+	// TRANSLATORS: shown while an application is uploaded, %s is the application name
+	fmt.Printf("Pushing app %s\n", name)
+
+	fmt.Println("Starting instances") // i18n: instances are the running copies of an application
+
+	// TRANSLATORS: a plural follows,
+	// %d is the number of instances
+	fmt.Printf("%d instances running\n", instances)
+
+	// a comment for the developers only
+	fmt.Println("Done")
+}
@@ -0,0 +1,30 @@
+package app
+
+import "fmt"
+
+const debugPrefix = "DEBUG" //i18n4go:ignore not shown to users
+
+func Greet(name string) {
+	fmt.Println("Hello", name)
+
+	//i18n4go:ignore-next-line
+	fmt.Println("trace: greeting sent")
+
+	fmt.Println("Welcome back", "(beta)" /*i18n4go:ignore*/)
+
+	//i18n4go:ignore-start
+	fmt.Println("internal state dump")
+	fmt.Println("internal counters")
+	//i18n4go:ignore-end
+
+	fmt.Println("Goodbye")
+}
+
+func Status() string {
+	//i18n4go:ignore-start
+	status := "OK"
+	label := "Status" //i18n4go:translate
+	//i18n4go:ignore-end
+
+	return fmt.Sprint(label, ": ", status, "x" /*i18n4go:translate*/)
+}
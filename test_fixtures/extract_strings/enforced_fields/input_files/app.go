@@ -0,0 +1,37 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	cli "github.com/urfave/cli/v2"
+)
+
+var app = &cli.App{
+	Name:  "demo",
+	Usage: "deploys apps",
+	Commands: []*cli.Command{
+		{
+			Name:        "push",
+			Usage:       "Push an app",
+			Description: "Uploads the app " + "and starts it",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "name", Usage: "the app name"},
+			},
+		},
+	},
+}
+
+var rootCmd = &cobra.Command{
+	Use:     "demo [command]",
+	Short:   "Demo deploys apps",
+	Long:    fmt.Sprintf("Demo deploys apps to %s", "the cloud"),
+	Example: "demo push my-app",
+}
+
+var port = flag.String("port", "8080", "the port to listen on")
+
+func main() {
+	fmt.Println("Done")
+}
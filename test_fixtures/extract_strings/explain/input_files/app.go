@@ -0,0 +1,25 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type config struct {
+	Name string `json:"name"`
+}
+
+func Serve(name string) {
+	fmt.Println("Starting server")
+	fmt.Printf("%s: %d\n", name, 8080)
+	http.Get("https://example.com/api")
+	fmt.Println("application/json")
+	fmt.Println("/etc/app/config.yml")
+	fmt.Println("userName")
+	fmt.Println("SELECT name FROM apps WHERE id = ?")
+	fmt.Println(`^\d+\.\d+$`)
+	fmt.Println("DEBUG") //i18n4go:ignore
+	fmt.Println("done")
+	headers := map[string]string{"X-Request-Id": "1"}
+	fmt.Println(headers)
+}
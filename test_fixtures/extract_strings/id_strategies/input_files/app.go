@@ -0,0 +1,17 @@
+package login
+
+import "fmt"
+
+type Form struct{}
+
+func (f *Form) Check(user string) error {
+	fmt.Println("Invalid password")
+	fmt.Printf("Hello %s, welcome back\n", user)
+	fmt.Println("Open" /*i18n4go:context menu*/)
+	return fmt.Errorf("Invalid password for user " + "admin")
+}
+
+func Greet() {
+	fmt.Println("Invalid password")
+	fmt.Println("Invalid password!")
+}
@@ -0,0 +1,5 @@
+package login
+
+import "errors"
+
+var errInvalid = errors.New("Invalid password")
@@ -0,0 +1,3 @@
+package login
+
+var invalidMessage = "Invalid password!"
@@ -0,0 +1,12 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/money"
+)
+
+func main() {
+	fmt.Println("Welcome to the shop")
+	fmt.Println(money.Format(3))
+}
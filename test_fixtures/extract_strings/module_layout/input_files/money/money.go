@@ -0,0 +1,7 @@
+package money
+
+import "fmt"
+
+func Format(amount int) string {
+	return fmt.Sprintf("%d dollars", amount)
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+)
+
+type App struct {
+	Name string
+}
+
+func (a *App) Start() error {
+	if a.Name == "" {
+		return errors.New("App name is required")
+	}
+
+	fmt.Println("Starting app")
+	return nil
+}
+
+func Validate(name string) error {
+	if name == "" {
+		return errors.New("App name is required")
+	}
+
+	fmt.Println("Starting app")
+	return nil
+}
+
+var defaultError = errors.New("App name is required")
@@ -0,0 +1,12 @@
+package app
+
+import "fmt"
+
+func Run() {
+	fmt.Println("Hello from the app")
+	fmt.Println("Project excluded")
+	fmt.Println("Shared excluded")
+	fmt.Println("snake_case_key")
+	fmt.Println("Captured: the captured part")
+	fmt.Println("Nested excluded")
+}
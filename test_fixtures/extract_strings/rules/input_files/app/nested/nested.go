@@ -0,0 +1,10 @@
+package nested
+
+import "fmt"
+
+func Run() {
+	fmt.Println("Hello from nested")
+	fmt.Println("Project excluded")
+	fmt.Println("Nested excluded")
+	fmt.Println("snake_case_key")
+}
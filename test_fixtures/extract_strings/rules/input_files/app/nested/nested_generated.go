@@ -0,0 +1,7 @@
+package nested
+
+import "fmt"
+
+func Generated() {
+	fmt.Println("Generated string")
+}
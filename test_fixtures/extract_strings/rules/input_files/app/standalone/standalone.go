@@ -0,0 +1,9 @@
+package standalone
+
+import "fmt"
+
+func Run() {
+	fmt.Println("Hello from standalone")
+	fmt.Println("Project excluded")
+	fmt.Println("Standalone excluded")
+}
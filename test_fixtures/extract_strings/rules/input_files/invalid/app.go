@@ -0,0 +1,7 @@
+package invalid
+
+import "fmt"
+
+func Run() {
+	fmt.Println("Never extracted")
+}
@@ -0,0 +1,8 @@
+package legacy
+
+import "fmt"
+
+func Greet() {
+	fmt.Println("Ignored")
+	fmt.Println("Hello")
+}
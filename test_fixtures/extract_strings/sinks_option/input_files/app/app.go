@@ -0,0 +1,27 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var config = map[string]string{"key": "value"}
+
+func greeting() string {
+	return "Hello from a helper"
+}
+
+func Run() error {
+	msg := "Deploying app"
+	fmt.Println(msg)
+	fmt.Fprintf(os.Stdout, "Done in %d seconds\n", 3)
+	os.Getenv("HOME_DIR")
+	var buf bytes.Buffer
+	buf.Write([]byte("written to a buffer"))
+	fmt.Println(greeting())
+	path := "/etc/config.yml"
+	_ = path
+	return errors.New("something failed: " + fmt.Sprintf("code %d", 1))
+}
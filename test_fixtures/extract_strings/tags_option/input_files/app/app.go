@@ -0,0 +1,7 @@
+package app
+
+import "fmt"
+
+func Run() {
+	fmt.Println("Running the app")
+}
@@ -0,0 +1,10 @@
+//go:build integration
+// +build integration
+
+package app
+
+import "fmt"
+
+func RunIntegration() {
+	fmt.Println("Running the integration checks")
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"text/template"
+)
+
+func Render(name string) error {
+	tmpl, err := template.New("greeting").Parse(`Greetings from {{.Name}}
+{{T "Have a nice day"}}`)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(os.Stdout, map[string]string{"Name": name})
+}
+
+func RenderBanner(w io.Writer) error {
+	banner := htmltemplate.Must(htmltemplate.New("banner").Parse(`<img src="logo.png">Fresh arrivals {{.}}`))
+	return banner.Execute(w, "today")
+}
+
+type Greeter struct{}
+
+func (Greeter) Parse(text string) string {
+	return text
+}
+
+func Welcome(greeter Greeter) string {
+	return greeter.Parse("Welcome back {{.Name}}")
+}
@@ -0,0 +1,16 @@
+package app
+
+import "fmt"
+
+func deleteApp(name string, org Org, err error) {
+	fmt.Println(T("Deleting {{.Name}} in org {{.OrgName}}", map[string]interface{}{"Name": name, "OrgName": org.Name}))
+	fmt.Println(T("Deleting {{.Name}} in org {{.OrgName}}", map[string]interface{}{"Name": org.App, "OrgName": org.Name}))
+	fmt.Println(T("{{.Name}} failed: {{.Err}}", map[string]interface{}{
+		"Name": name,        // the app
+		"Err":  err.Error(), // the reason
+	}))
+	fmt.Println(T("Copying {{.Name}} to {{.Name2}}", map[string]interface{}{"Name": name, "Name2": name}))
+	fmt.Println(T("Pushing {{.Name}} with {{.Instances}}", map[string]interface{}{"Name": name, "Instances": 10}))
+	fmt.Println(TC("button", "Stop {{.OrgName}}", map[string]interface{}{"OrgName": org.GetName()}))
+	fmt.Println(T("Hello {{.Name}}", map[string]interface{}{"Name": name}))
+}
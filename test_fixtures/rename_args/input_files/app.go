@@ -0,0 +1,16 @@
+package app
+
+import "fmt"
+
+func deleteApp(name string, org Org, err error) {
+	fmt.Println(T("Deleting {{.Arg0}} in org {{.Arg1}}", map[string]interface{}{"Arg0": name, "Arg1": org.Name}))
+	fmt.Println(T("Deleting {{.Arg0}} in org {{.Arg1}}", map[string]interface{}{"Arg0": org.App, "Arg1": org.Name}))
+	fmt.Println(T("{{.Arg0}} failed: {{.Arg1}}", map[string]interface{}{
+		"Arg0": name,        // the app
+		"Arg1": err.Error(), // the reason
+	}))
+	fmt.Println(T("Copying {{.Arg0}} to {{.Arg1}}", map[string]interface{}{"Arg0": name, "Arg1": name}))
+	fmt.Println(T("Pushing {{.Arg0}} with {{.Arg1}}", map[string]interface{}{"Arg0": name, "Arg1": 10}))
+	fmt.Println(TC("button", "Stop {{.Arg0}}", map[string]interface{}{"Arg0": org.GetName()}))
+	fmt.Println(T("Hello {{.Name}}", map[string]interface{}{"Name": name}))
+}
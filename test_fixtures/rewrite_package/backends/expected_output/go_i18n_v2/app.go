@@ -0,0 +1,20 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+func Push(name string, instances int) {
+	fmt.Println(Localizer.MustLocalize(&i18n.LocalizeConfig{MessageID: "Pushing app"}))
+	fmt.Println(Localizer.MustLocalize(&i18n.LocalizeConfig{MessageID: "menu.Open"})) //i18n4go:context menu
+	fmt.Println(Localizer.MustLocalize(&i18n.LocalizeConfig{MessageID: "Upload at 100% speed"}))
+	fmt.Println(Localizer.MustLocalize(&i18n.LocalizeConfig{MessageID: "Started " + "the app"}))
+
+	fmt.Printf(Localizer.MustLocalize(&i18n.LocalizeConfig{MessageID: "Pushing {{.Name}} with {{.Instances}} instances\n", TemplateData: map[string]interface{}{
+		"Name":      name,      // the app
+		"Instances": instances, // its instances
+	}}))
+	fmt.Printf(Localizer.MustLocalize(&i18n.LocalizeConfig{MessageID: "Scaling {{.Name}} to {{.Instances}}\n", TemplateData: map[string]interface{}{"Name": name, "Instances": instances}}))
+}
@@ -0,0 +1,20 @@
+package app
+
+import (
+	"fmt"
+
+	"golang.org/x/text/message"
+)
+
+func Push(name string, instances int) {
+	fmt.Println(Printer.Sprintf("Pushing app"))
+	fmt.Println(Printer.Sprintf(message.Key("menu\x04Open", "Open"))) //i18n4go:context menu
+	fmt.Println(Printer.Sprintf("Upload at 100%% speed"))
+	fmt.Println(Printer.Sprintf("Started " + "the app"))
+
+	fmt.Printf(Printer.Sprintf("Pushing %s with %d instances\n",
+		name,      // the app
+		instances, // its instances
+	))
+	fmt.Printf(Printer.Sprintf("Scaling %[1]v to %[2]v\n", name, instances))
+}
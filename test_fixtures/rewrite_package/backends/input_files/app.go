@@ -0,0 +1,16 @@
+package app
+
+import "fmt"
+
+func Push(name string, instances int) {
+	fmt.Println("Pushing app")
+	fmt.Println("Open") //i18n4go:context menu
+	fmt.Println("Upload at 100% speed")
+	fmt.Println("Started " + "the app")
+
+	fmt.Printf("Pushing %s with %d instances\n",
+		name,      // the app
+		instances, // its instances
+	)
+	fmt.Printf("Scaling {{.Name}} to {{.Instances}}\n", name, instances)
+}
@@ -0,0 +1,54 @@
+// Package commands holds the commands of the app.
+//
+// The layout of this file, its comments included, must survive a rewrite.
+package commands
+
+import (
+	"fmt" // printing
+
+	// errors are wrapped
+	"errors"
+)
+
+/*
+Command is one command of the app,
+with a name and a usage.
+*/
+type Command struct {
+	Name  string // the name typed by the user
+	Usage string // shown by help
+}
+
+// the commands, aligned by gofmt
+func commands() []Command {
+	return []Command{
+		{
+			Name:  T("push"),           // deploys an app
+			Usage: T("Push a new app"), // one line
+		},
+		{Name: T("delete") /* internal name */, Usage: T("Delete an app") /* shown */},
+	}
+}
+
+func Run(name string, count int) error {
+	// greet first
+	fmt.Println(T("Welcome") /* the greeting */) // trailing comment
+
+	/* a block comment
+	   over several lines */
+	fmt.Printf(T("Running {{.Name}} for the {{.Count}} time\n", map[string]interface{}{ // the format
+		"Name":  name,  // the command
+		"Count": count, // how many times
+	}))
+
+	if len(name) == 0 {
+		return errors.New(T("Missing " + // the first half
+			"command name")) // the second half
+	}
+
+	fmt.Println(
+		T("Done"), // the result
+	)
+
+	return nil // nothing went wrong
+}
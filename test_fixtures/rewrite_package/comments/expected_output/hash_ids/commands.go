@@ -0,0 +1,53 @@
+// Package commands holds the commands of the app.
+//
+// The layout of this file, its comments included, must survive a rewrite.
+package commands
+
+import (
+	"fmt" // printing
+
+	// errors are wrapped
+	"errors"
+)
+
+/*
+Command is one command of the app,
+with a name and a usage.
+*/
+type Command struct {
+	Name  string // the name typed by the user
+	Usage string // shown by help
+}
+
+// the commands, aligned by gofmt
+func commands() []Command {
+	return []Command{
+		{
+			Name:  T("d107ea3629"), // deploys an app
+			Usage: T("36a5a52b32"), // one line
+		},
+		{Name: T("6197595503") /* internal name */, Usage: T("e080b9c2d0") /* shown */},
+	}
+}
+
+func Run(name string, count int) error {
+	// greet first
+	fmt.Println(T("0e2226b523") /* the greeting */) // trailing comment
+
+	/* a block comment
+	   over several lines */
+	fmt.Printf(T("c368abebd9", map[string]interface{}{ // the format
+		"Name":  name,  // the command
+		"Count": count, // how many times
+	}))
+
+	if len(name) == 0 {
+		return errors.New(T("71f6224beb")) // the first half // the second half
+	}
+
+	fmt.Println(
+		T("11a6767d56"), // the result
+	)
+
+	return nil // nothing went wrong
+}
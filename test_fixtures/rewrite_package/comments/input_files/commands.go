@@ -0,0 +1,54 @@
+// Package commands holds the commands of the app.
+//
+// The layout of this file, its comments included, must survive a rewrite.
+package commands
+
+import (
+	"fmt" // printing
+
+	// errors are wrapped
+	"errors"
+)
+
+/*
+Command is one command of the app,
+with a name and a usage.
+*/
+type Command struct {
+	Name  string // the name typed by the user
+	Usage string // shown by help
+}
+
+// the commands, aligned by gofmt
+func commands() []Command {
+	return []Command{
+		{
+			Name:  "push",           // deploys an app
+			Usage: "Push a new app", // one line
+		},
+		{Name: "delete" /* internal name */, Usage: "Delete an app" /* shown */},
+	}
+}
+
+func Run(name string, count int) error {
+	// greet first
+	fmt.Println("Welcome" /* the greeting */) // trailing comment
+
+	/* a block comment
+	   over several lines */
+	fmt.Printf("Running %s for the %d time\n", // the format
+		name,  // the command
+		count, // how many times
+	)
+
+	if len(name) == 0 {
+		return errors.New("Missing " + // the first half
+			"command name") // the second half
+	}
+
+	fmt.Println(
+		"Done", // the result
+	)
+
+	return nil // nothing went wrong
+}
@@ -0,0 +1,14 @@
+package input_files
+
+import "fmt"
+
+func Connect(reason string) string {
+	fmt.Println(T("Failed to "+"connect: ") + reason)
+	fmt.Println(reason + T(" was "+"rejected"))
+
+	help := T("Usage: app [options] " +
+		"<file>")
+	fmt.Println(help)
+
+	return T("Connecting to ") + reason + T("...")
+}
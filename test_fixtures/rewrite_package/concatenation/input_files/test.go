@@ -0,0 +1,14 @@
+package input_files
+
+import "fmt"
+
+func Connect(reason string) string {
+	fmt.Println("Failed to " + "connect: " + reason)
+	fmt.Println(reason + " was " + "rejected")
+
+	help := "Usage: app [options] " +
+		"<file>"
+	fmt.Println(help)
+
+	return "Connecting to " + reason + "..."
+}
@@ -0,0 +1,11 @@
+package input_files
+
+import "fmt"
+
+func Something() {
+	fmt.Println("Open") //i18n4go:context menu
+	fmt.Println("Open")
+
+	fmt.Println("Close") //i18n4go:context door
+	fmt.Println(TC("window", "Close"))
+}
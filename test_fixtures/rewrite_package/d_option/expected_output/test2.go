@@ -9,6 +9,6 @@ func DOption2() string {
 	fmt.Printf(T("Bye from {{.Arg0}}", map[string]interface{}{"Arg0": T("Evil")}))
 
 	for i := range 10 {
-		fmt.Printf(T("Hello {{.Arg0}} world!", map[string]interface{}{"Arg0": name}))
+		fmt.Printf(T("Hello {{.Name}} world!", map[string]interface{}{"Name": name}))
 	}
 }
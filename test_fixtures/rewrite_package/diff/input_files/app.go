@@ -0,0 +1,24 @@
+package app
+
+import (
+	"fmt"
+	"os"
+)
+
+func Run(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: app <name>")
+		os.Exit(1)
+	}
+
+	name := args[0]
+	fmt.Println(name)
+	fmt.Println(len(name))
+	fmt.Println(args)
+	fmt.Println(os.Args)
+	fmt.Println(os.Getpid())
+	fmt.Println(os.Getppid())
+	fmt.Println(os.Getuid())
+	fmt.Println(os.Getgid())
+	fmt.Println("Goodbye")
+}
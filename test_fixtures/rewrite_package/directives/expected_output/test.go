@@ -0,0 +1,18 @@
+package input_files
+
+import "fmt"
+
+func Something() {
+	fmt.Println(T("Hello"))
+
+	//i18n4go:ignore-next-line
+	fmt.Println("trace: something")
+
+	fmt.Println(T("Status:"), "ok" /*i18n4go:ignore*/)
+
+	//i18n4go:ignore-start
+	fmt.Println("internal state dump")
+	//i18n4go:ignore-end
+
+	fmt.Println(T("Not in the strings file")) //i18n4go:translate
+}
@@ -36,7 +36,7 @@ func Something() string {
 	trickyT := t{T("this is a tricky case")}
 	println(trickyT.myString)
 
-	concatenatedStrings := T("foo") + T(" ") + T("bar")
+	concatenatedStrings := T("foo" + " " + "bar")
 	println(concatenatedStrings)
 
 	fmt.Printf(T("HAI"))
@@ -44,7 +44,7 @@ func Something() string {
 		fmt.Printf(filepath.Clean(os.Getenv(T("SOMETHING"))))
 	}
 
-	fmt.Println(T("hello") + T("world"))
+	fmt.Println(T("hello" + "world"))
 
 	return T("enqueuedequeueenqueuebananapants")
 }
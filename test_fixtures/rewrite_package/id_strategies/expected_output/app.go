@@ -0,0 +1,17 @@
+package login
+
+import "fmt"
+
+type Form struct{}
+
+func (f *Form) Check(user string) error {
+	fmt.Println(T("login.formCheckInvalidPassword"))
+	fmt.Printf(T("login.formCheckHelloWelcomeBack", map[string]interface{}{"User": user}))
+	fmt.Println(TC("menu", "login.formCheckOpen") /*i18n4go:context menu*/)
+	return fmt.Errorf(T("login.formCheckInvalidPasswordForUser"))
+}
+
+func Greet() {
+	fmt.Println(T("login.formCheckInvalidPassword"))
+	fmt.Println(T("login.greetInvalidPassword"))
+}
@@ -8,7 +8,9 @@ import (
 )
 
 var T goi18n.TranslateFunc
+var TC i18n.TranslateContextFunc
 
 func init() {
 	T = i18n.Init(filepath.Join("test_fixtures", "rewrite_package", "init_code_snippet_filename", "input_files"), i18n.GetResourcesPath())
+	TC = i18n.ContextTfunc(T)
 }
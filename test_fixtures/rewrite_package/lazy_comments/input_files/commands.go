@@ -0,0 +1,29 @@
+// Package commands holds the commands of the app.
+package commands
+
+/*
+Command is one command of the app,
+with a name and a usage.
+*/
+type Command struct {
+	Name  string // the name typed by the user
+	Usage string // shown by help
+}
+
+// the commands, aligned by gofmt
+var commands = []Command{
+	{
+		Name:  "push",           // deploys an app
+		Usage: "Push a new app", // one line
+	},
+	{Name: "delete" /* internal name */, Usage: "Delete an app" /* shown */},
+} // the end of the commands
+
+// Names are the names of the commands
+func Names() []string {
+	var names []string
+	for _, command := range commands {
+		names = append(names, command.Name)
+	}
+	return names
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+)
+
+// the greeting shown on start
+func greeting() string {
+	return T("Hello")
+}
+
+const (
+	maxRetries = 3
+)
+
+// shown when giving up
+func giveUp() string {
+	return T("Giving up")
+}
+
+func tryAgain() string {
+	return T("Try again") // after a failure
+}
+
+// Banner is part of the API of the package
+const Banner = "Welcome to the app"
+
+const farewell = "Goodbye"
+
+func shortFarewell() string {
+	return T(farewell + "!")
+}
+
+func errNotFound() error {
+	return errors.New(T("Not found"))
+}
+
+func usage() string {
+	return fmt.Sprintf(T("Usage: {{.Arg0}} [options]", map[string]interface{}{"Arg0": T("app")}))
+}
+
+var prompt = "Continue?"
+
+func lines() []string {
+	return []string{
+		T("First line"),
+		T("Second line"),
+	}
+}
+
+var handler = func() string {
+	return T("Handled")
+}
+
+func Start(name string) error {
+	fmt.Println(greeting(), name)
+	fmt.Println(usage())
+	fmt.Println(shortFarewell())
+	for _, line := range lines() {
+		fmt.Println(line)
+	}
+
+	var (
+		retrying = T("Retrying")
+		failed   = T("Failed")
+	)
+	for i := 0; i < maxRetries; i++ {
+		fmt.Println(retrying, tryAgain())
+	}
+	fmt.Println(failed, giveUp())
+
+	prompt = prompt + T(" [y/n]")
+	fmt.Println(prompt, handler())
+
+	return errNotFound()
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	i18n "github.com/Liam-Williams/i18n4go/test_fixtures/rewrite_package/qualifier/translator"
+)
+
+// the greeting shown on start
+func greeting() string {
+	return i18n.T("Hello")
+}
+
+const (
+	maxRetries = 3
+)
+
+// shown when giving up
+func giveUp() string {
+	return i18n.T("Giving up")
+}
+
+func tryAgain() string {
+	return i18n.T("Try again") // after a failure
+}
+
+// Banner is part of the API of the package
+const Banner = "Welcome to the app"
+
+const farewell = "Goodbye"
+
+func shortFarewell() string {
+	return i18n.T(farewell + "!")
+}
+
+var errNotFound = errors.New(i18n.T("Not found"))
+
+var usage = fmt.Sprintf(i18n.T("Usage: {{.Arg0}} [options]", map[string]interface{}{"Arg0": i18n.T("app")}))
+
+var prompt = i18n.T("Continue?")
+
+var lines = []string{
+	i18n.T("First line"),
+	i18n.T("Second line"),
+}
+
+var handler = func() string {
+	return i18n.T("Handled")
+}
+
+func Start(name string) error {
+	fmt.Println(greeting(), name)
+	fmt.Println(usage)
+	fmt.Println(shortFarewell())
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+
+	var (
+		retrying = i18n.T("Retrying")
+		failed   = i18n.T("Failed")
+	)
+	for i := 0; i < maxRetries; i++ {
+		fmt.Println(retrying, tryAgain())
+	}
+	fmt.Println(failed, giveUp())
+
+	prompt = prompt + i18n.T(" [y/n]")
+	fmt.Println(prompt, handler())
+
+	return errNotFound
+}
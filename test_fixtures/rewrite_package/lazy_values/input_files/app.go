@@ -0,0 +1,61 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+)
+
+// the greeting shown on start
+const greeting = "Hello"
+
+const (
+	maxRetries = 3
+
+	// shown when giving up
+	giveUp   = "Giving up"
+	tryAgain = "Try again" // after a failure
+)
+
+// Banner is part of the API of the package
+const Banner = "Welcome to the app"
+
+const farewell = "Goodbye"
+const shortFarewell = farewell + "!"
+
+var errNotFound = errors.New("Not found")
+
+var usage = fmt.Sprintf("Usage: %s [options]", "app")
+
+var prompt = "Continue?"
+
+var lines = []string{
+	"First line",
+	"Second line",
+}
+
+var handler = func() string {
+	return "Handled"
+}
+
+func Start(name string) error {
+	fmt.Println(greeting, name)
+	fmt.Println(usage)
+	fmt.Println(shortFarewell)
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+
+	const (
+		retrying = "Retrying"
+		failed   = "Failed"
+	)
+	for i := 0; i < maxRetries; i++ {
+		fmt.Println(retrying, tryAgain)
+	}
+	fmt.Println(failed, giveUp)
+
+	prompt = prompt + " [y/n]"
+	fmt.Println(prompt, handler())
+
+	return errNotFound
+}
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"fmt"
+	"os"
+)
+
+func DeleteUser(name, org string, spaces int) {
+	fmt.Println(T("Deleted {{.Name}} from org {{.Org}}", map[string]interface{}{"Name": name, "Org": org}))
+	fmt.Println(T("Removed {{.FmtSprint}} spaces of {{.Org}}", map[string]interface{}{"FmtSprint": fmt.Sprint(spaces), "Org": org}))
+
+	if len(os.Args) > 1 {
+		fmt.Println(T("Deleted {{.OsArgs}}!", map[string]interface{}{"OsArgs": os.Args[1]}))
+	}
+
+	fmt.Println(name + T(": ") + org)
+	fmt.Println(T("Deleted "), name)
+}
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"fmt"
+	"os"
+)
+
+func DeleteUser(name, org string, spaces int) {
+	fmt.Println("Deleted " + name + " from org " + org)
+	fmt.Println("Removed " + fmt.Sprint(spaces) + " spaces of " + org)
+
+	if len(os.Args) > 1 {
+		fmt.Println("Deleted " + os.Args[1] + "!")
+	}
+
+	fmt.Println(name + ": " + org)
+	fmt.Println("Deleted ", name)
+}
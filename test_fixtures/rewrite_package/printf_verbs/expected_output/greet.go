@@ -0,0 +1,9 @@
+package app
+
+import "fmt"
+
+func say(format string, args ...interface{}) {}
+
+func greet(name string) {
+	say(T("Hello {{.Name}}!", map[string]interface{}{"Name": fmt.Sprintf("%-8s", name)}))
+}
@@ -0,0 +1,8 @@
+package app
+
+import "fmt"
+
+func progress(name string) {
+	fmt.Println(T("Disk 50% full"), name)
+	fmt.Println(T("Uploading 50% of files"), name)
+}
@@ -0,0 +1,13 @@
+package app
+
+import (
+	"fmt"
+
+	i18n "github.com/Liam-Williams/i18n4go/test_fixtures/rewrite_package/qualifier/translator"
+)
+
+func say(format string, args ...interface{}) {}
+
+func greet(name string) {
+	say(i18n.T("Hello {{.Name}}!", map[string]interface{}{"Name": fmt.Sprintf("%-8s", name)}))
+}
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	i18n "github.com/Liam-Williams/i18n4go/test_fixtures/rewrite_package/qualifier/translator"
+)
+
+func report(name string, count int, ratio float64, id uint32, width int) {
+	fmt.Printf(i18n.T("{{.Name}} has {{.Count}} apps\n", map[string]interface{}{"Name": fmt.Sprintf("%-10s", name), "Count": fmt.Sprintf("%5d", count)}))
+	fmt.Printf(i18n.T("{{.Ratio}}%% of the memory is used by {{.Name}}\n", map[string]interface{}{"Ratio": fmt.Sprintf("%.2f", ratio), "Name": name}))
+	fmt.Printf(i18n.T("{{.Arg1}} was pushed to {{.Name}}\n", map[string]interface{}{"Arg1": i18n.T("the space"), "Name": name}))
+	fmt.Printf(i18n.T("{{.Name}}, {{.Id}} and {{.Name2}}\n", map[string]interface{}{"Name": fmt.Sprintf("%+v", name), "Id": fmt.Sprintf("%08x", id), "Name2": fmt.Sprintf("%q", name)}))
+	fmt.Printf(i18n.T("{{.Count}} apps are running\n", map[string]interface{}{"Count": fmt.Sprintf("%*d", width, count)}))
+	fmt.Printf(i18n.T("{{.Name}} owns {{.Arg1}}, {{.Name2}} is its name\n", map[string]interface{}{"Name": name, "Arg1": i18n.T("my-app"), "Name2": fmt.Sprintf("%q", name)}))
+	fmt.Fprintf(os.Stderr, i18n.T("{{.Count}} of {{.Count2}} apps failed\n", map[string]interface{}{"Count": count, "Count2": count}))
+}
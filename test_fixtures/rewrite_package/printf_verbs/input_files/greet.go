@@ -0,0 +1,7 @@
+package app
+
+func say(format string, args ...interface{}) {}
+
+func greet(name string) {
+	say("Hello %-8s!", name)
+}
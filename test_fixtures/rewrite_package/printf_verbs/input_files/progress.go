@@ -0,0 +1,8 @@
+package app
+
+import "fmt"
+
+func progress(name string) {
+	fmt.Println("Disk 50% full", name)
+	fmt.Println("Uploading 50% of files", name)
+}
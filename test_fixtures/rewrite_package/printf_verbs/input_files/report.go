@@ -0,0 +1,16 @@
+package app
+
+import (
+	"fmt"
+	"os"
+)
+
+func report(name string, count int, ratio float64, id uint32, width int) {
+	fmt.Printf("%-10s has %5d apps\n", name, count)
+	fmt.Printf("%.2f%% of the memory is used by %s\n", ratio, name)
+	fmt.Printf("%[2]s was pushed to %[1]s\n", name, "the space")
+	fmt.Printf("%+v, %08x and %q\n", name, id, name)
+	fmt.Printf("%*d apps are running\n", width, count)
+	fmt.Printf("%s owns %s, %[1]q is its name\n", name, "my-app")
+	fmt.Fprintf(os.Stderr, "%d of %d apps failed\n", count, count)
+}
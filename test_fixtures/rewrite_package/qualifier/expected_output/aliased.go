@@ -0,0 +1,12 @@
+package input_files
+
+import (
+	"fmt"
+
+	tr "github.com/Liam-Williams/i18n4go/test_fixtures/rewrite_package/qualifier/translator"
+)
+
+func Greet() {
+	fmt.Println(tr.T("Hello"))
+	fmt.Println(tr.T("Goodbye"))
+}
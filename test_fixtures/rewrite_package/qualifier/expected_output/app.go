@@ -0,0 +1,17 @@
+package input_files
+
+import (
+	"fmt"
+	"os"
+
+	i18n "github.com/Liam-Williams/i18n4go/test_fixtures/rewrite_package/qualifier/translator"
+)
+
+func Run(args []string) {
+	if len(args) == 0 {
+		fmt.Printf(i18n.T("Usage: {{.OsArgs}} <name>\n", map[string]interface{}{"OsArgs": os.Args[0]}))
+		os.Exit(1)
+	}
+
+	fmt.Println(i18n.TC("menu", "Open") /*i18n4go:context menu*/)
+}
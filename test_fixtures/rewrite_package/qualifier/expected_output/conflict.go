@@ -0,0 +1,14 @@
+package input_files
+
+import (
+	"fmt"
+
+	i18n2 "github.com/Liam-Williams/i18n4go/test_fixtures/rewrite_package/qualifier/translator"
+	"github.com/nicksnyder/go-i18n/i18n"
+)
+
+func Load(fileName string) {
+	if err := i18n.LoadTranslationFile(fileName); err != nil {
+		fmt.Println(i18n2.T("Could not load the translations"))
+	}
+}
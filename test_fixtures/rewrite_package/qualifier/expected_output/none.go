@@ -0,0 +1,9 @@
+package input_files
+
+import i18n "github.com/Liam-Williams/i18n4go/test_fixtures/rewrite_package/qualifier/translator"
+
+var greeting = i18n.T("Hello")
+
+func Greeting() string {
+	return greeting
+}
@@ -0,0 +1,15 @@
+package input_files
+
+import (
+	"errors"
+
+	i18n "github.com/Liam-Williams/i18n4go/test_fixtures/rewrite_package/qualifier/translator"
+)
+
+func Check(name string) error {
+	if len(name) == 0 {
+		return errors.New(i18n.T("Missing name"))
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package input_files
+
+import (
+	"fmt"
+	"os"
+)
+
+func Run(args []string) {
+	if len(args) == 0 {
+		fmt.Printf("Usage: %s <name>\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	fmt.Println("Open" /*i18n4go:context menu*/)
+}
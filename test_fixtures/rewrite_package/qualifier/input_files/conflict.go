@@ -0,0 +1,13 @@
+package input_files
+
+import (
+	"fmt"
+
+	"github.com/nicksnyder/go-i18n/i18n"
+)
+
+func Load(fileName string) {
+	if err := i18n.LoadTranslationFile(fileName); err != nil {
+		fmt.Println("Could not load the translations")
+	}
+}
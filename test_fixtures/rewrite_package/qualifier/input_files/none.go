@@ -0,0 +1,7 @@
+package input_files
+
+var greeting = "Hello"
+
+func Greeting() string {
+	return greeting
+}
@@ -0,0 +1,11 @@
+package input_files
+
+import "errors"
+
+func Check(name string) error {
+	if len(name) == 0 {
+		return errors.New("Missing name")
+	}
+
+	return nil
+}
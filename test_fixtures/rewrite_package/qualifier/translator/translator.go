@@ -0,0 +1,11 @@
+package translator
+
+// T returns the ID of the message, it stands for the T() of go-i18n
+var T = func(translationID string, args ...interface{}) string {
+	return translationID
+}
+
+// TC returns the ID of the message in context
+var TC = func(context, translationID string, args ...interface{}) string {
+	return translationID
+}
@@ -0,0 +1,7 @@
+package input_files
+
+import "strings"
+
+func normalize(name string) string {
+	return strings.TrimSpace(strings.ToLower(name))
+}
@@ -0,0 +1,7 @@
+package app
+
+import "fmt"
+
+func Greet(name string) {
+	fmt.Println("Hello", name)
+}
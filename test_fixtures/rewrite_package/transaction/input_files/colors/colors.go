@@ -0,0 +1,12 @@
+package colors
+
+type Color string
+
+func paint(color Color) Color {
+	return color
+}
+
+// Red does not compile once "red" is translated, T() returns a string
+func Red() Color {
+	return paint("red")
+}
@@ -0,0 +1,10 @@
+package app
+
+import "fmt"
+
+func Greet(name string) {
+	fmt.Println(T("Hello")) // the greeting
+	fmt.Println(name)    // the name
+	count := len(name)   // the length of the name
+	fmt.Println(count)
+}
@@ -0,0 +1,15 @@
+package app
+
+import "strings"
+
+func main() {
+	println("I am a string")
+	println(T("This should be verified."))
+	println(T("This should be also {{.Verified}}", map[string]interface{}{
+		"Verified": T("verified"),
+	}))
+	//i18n4go:ignore-start
+	println(T("Not in the json resource"))
+	//i18n4go:ignore-end
+	println(T(strings.Join("a", "b"))) //ast.Expr is *ast.CallExpr, not *ast.BasicLit
+}